@@ -0,0 +1,89 @@
+package clink
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// ConfigMutation reports a change of the exported configuration of the client while a request was in flight.
+type ConfigMutation struct {
+	// Fields are the names of the changed fields of the Client, such as "Headers" or "MaxRetries".
+	Fields []string
+	// Request is the request in flight during the change.
+	Request *http.Request
+}
+
+// WithMutationAudit reports changes of the exported fields of the client, such as Headers or MaxRetries, made while
+// requests are in flight. Such changes race with the requests, which may see some of them and not others, such as
+// the headers applied when the request was prepared but a MaxRetries changed while it was retried. Each request
+// compares the configuration when it completes with the one it started with. Headers are compared by identity and
+// number of entries only, as iterating over a map being changed crashes the program, so changing the value of an
+// existing header goes unreported. Meant for development and staging, it does not replace the race detector.
+func WithMutationAudit(report func(ConfigMutation)) Option {
+	return func(c *Client) {
+		c.mutationAudit = report
+	}
+}
+
+// configSnapshot fingerprints the exported configuration of the client.
+type configSnapshot map[string]uint64
+
+// snapshotConfig returns the fingerprint of the configuration when mutations are audited, nil otherwise.
+func (c *Client) snapshotConfig() configSnapshot {
+	if c.mutationAudit == nil {
+		return nil
+	}
+
+	return configSnapshot{
+		"HttpClient":      pointerOf(c.HttpClient),
+		"Headers":         pointerOf(c.Headers) ^ uint64(len(c.Headers)),
+		"RateLimiter":     pointerOf(c.RateLimiter),
+		"Limiter":         pointerOf(c.Limiter),
+		"MaxRetries":      uint64(c.MaxRetries),
+		"ShouldRetryFunc": pointerOf(c.ShouldRetryFunc),
+		"BackoffFunc":     pointerOf(c.BackoffFunc),
+	}
+}
+
+// auditConfig reports the fields of the configuration changed since the snapshot taken when the request started.
+func (c *Client) auditConfig(req *http.Request, snapshot configSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	var fields []string
+	for field, value := range c.snapshotConfig() {
+		if snapshot[field] != value {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return
+	}
+
+	sort.Strings(fields)
+	func() {
+		defer recordPanic(req)
+		c.mutationAudit(ConfigMutation{Fields: fields, Request: req})
+	}()
+}
+
+// pointerOf returns the address held by a pointer, map or func value, the hash of other values, and zero for nil.
+func pointerOf(v any) uint64 {
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Func, reflect.Chan, reflect.Slice, reflect.UnsafePointer:
+		return uint64(value.Pointer())
+	case reflect.Invalid:
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = fmt.Fprint(hash, v)
+
+	return hash.Sum64()
+}
@@ -0,0 +1,50 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithMutationAudit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var mutations []clink.ConfigMutation
+	var client *clink.Client
+	client = clink.NewClient(
+		clink.WithHeader("X-Tenant", "a"),
+		clink.WithMutationAudit(func(mutation clink.ConfigMutation) {
+			mutations = append(mutations, mutation)
+		}),
+		clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path == "/mutate" {
+					client.Headers["X-Request-Tenant"] = "b"
+					client.MaxRetries = 3
+				}
+				return next(req)
+			}
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	for _, path := range []string{"/", "/mutate", "/"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if len(mutations) != 1 {
+		t.Fatalf("expected 1 mutation, got: %+v", mutations)
+	}
+	if want := []string{"Headers", "MaxRetries"}; !reflect.DeepEqual(mutations[0].Fields, want) ||
+		mutations[0].Request.URL.Path != "/mutate" {
+		t.Errorf("expected %v to change during /mutate, got: %v during %s", want, mutations[0].Fields, mutations[0].Request.URL.Path)
+	}
+}
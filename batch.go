@@ -0,0 +1,261 @@
+package clink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// BatchFormat is the envelope format used to send batch requests.
+type BatchFormat int
+
+const (
+	// BatchMultipart sends the requests as application/http parts of a multipart/mixed body,
+	// as used by OData $batch and the Google batch API.
+	BatchMultipart BatchFormat = iota
+	// BatchJSON sends the requests as an OData JSON batch document.
+	BatchJSON
+)
+
+// Batch sends the requests as a single batch request posted to url and returns their responses in the same order.
+// The client configuration is applied to the batch request and to each of the requests.
+func (c *Client) Batch(ctx context.Context, url string, format BatchFormat, reqs ...*http.Request) ([]*http.Response, error) {
+	prepared := make([]*http.Request, len(reqs))
+	for i, req := range reqs {
+		p, err := c.prepare(req.Clone(ctx))
+		if err != nil {
+			return nil, err
+		}
+		prepared[i] = p
+	}
+
+	var body bytes.Buffer
+	var contentType string
+	var err error
+	switch format {
+	case BatchJSON:
+		contentType = "application/json"
+		err = writeJSONBatch(&body, prepared)
+	default:
+		contentType, err = writeMultipartBatch(&body, prepared)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode batch request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create batch request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("batch request failed with status %s", resp.Status)
+	}
+
+	var responses []*http.Response
+	switch format {
+	case BatchJSON:
+		responses, err = readJSONBatch(resp.Body, prepared)
+	default:
+		responses, err = readMultipartBatch(resp, prepared)
+	}
+	if err != nil {
+		return nil, &DecodeError{Content: "batch response", Err: err}
+	}
+
+	return responses, nil
+}
+
+// writeMultipartBatch writes each request as an application/http part and returns the content type of the body.
+func writeMultipartBatch(w io.Writer, reqs []*http.Request) (string, error) {
+	mw := multipart.NewWriter(w)
+
+	for i, req := range reqs {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"application/http"},
+			"Content-Transfer-Encoding": {"binary"},
+			"Content-Id":                {strconv.Itoa(i + 1)},
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if err := req.Write(part); err != nil {
+			return "", err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	return "multipart/mixed; boundary=" + mw.Boundary(), nil
+}
+
+// readMultipartBatch reads the application/http parts of the batch response, in order.
+func readMultipartBatch(resp *http.Response, reqs []*http.Request) ([]*http.Response, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unexpected content type %q", mediaType)
+	}
+
+	responses := make([]*http.Response, 0, len(reqs))
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var req *http.Request
+		if len(responses) < len(reqs) {
+			req = reqs[len(responses)]
+		}
+
+		r, err := http.ReadResponse(bufio.NewReader(part), req)
+		if err != nil {
+			return nil, err
+		}
+
+		// The part is only valid until the next one is read, so the body is read now.
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		responses = append(responses, r)
+	}
+
+	if len(responses) != len(reqs) {
+		return nil, fmt.Errorf("expected %d responses, got %d", len(reqs), len(responses))
+	}
+
+	return responses, nil
+}
+
+type jsonBatchRequest struct {
+	ID      string            `json:"id"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type jsonBatchResponse struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// writeJSONBatch writes the requests as an OData JSON batch document.
+// JSON bodies are embedded as is, other bodies as JSON strings.
+func writeJSONBatch(w io.Writer, reqs []*http.Request) error {
+	var batch struct {
+		Requests []jsonBatchRequest `json:"requests"`
+	}
+
+	for i, req := range reqs {
+		r := jsonBatchRequest{
+			ID:      strconv.Itoa(i + 1),
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: make(map[string]string, len(req.Header)),
+		}
+
+		for key := range req.Header {
+			r.Headers[key] = req.Header.Get(key)
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return err
+			}
+
+			if json.Valid(body) {
+				r.Body = body
+			} else if r.Body, err = json.Marshal(string(body)); err != nil {
+				return err
+			}
+		}
+
+		batch.Requests = append(batch.Requests, r)
+	}
+
+	return json.NewEncoder(w).Encode(batch)
+}
+
+// readJSONBatch reads an OData JSON batch response, matching the responses to the requests by id.
+func readJSONBatch(r io.Reader, reqs []*http.Request) ([]*http.Response, error) {
+	var batch struct {
+		Responses []jsonBatchResponse `json:"responses"`
+	}
+	if err := json.NewDecoder(r).Decode(&batch); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*http.Response, len(reqs))
+	for _, br := range batch.Responses {
+		i, err := strconv.Atoi(br.ID)
+		if err != nil || i < 1 || i > len(reqs) {
+			return nil, fmt.Errorf("unexpected response id %q", br.ID)
+		}
+
+		body := []byte(br.Body)
+		var s string
+		if json.Unmarshal(body, &s) == nil {
+			body = []byte(s)
+		}
+
+		header := make(http.Header, len(br.Headers))
+		for key, value := range br.Headers {
+			header.Set(key, value)
+		}
+
+		responses[i-1] = &http.Response{
+			Status:        strconv.Itoa(br.Status) + " " + http.StatusText(br.Status),
+			StatusCode:    br.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       reqs[i-1],
+		}
+	}
+
+	for i, resp := range responses {
+		if resp == nil {
+			return nil, fmt.Errorf("missing response for request %d", i+1)
+		}
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,120 @@
+package clink_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func batchServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		if mediaType == "application/json" {
+			var batch struct {
+				Requests []struct {
+					ID     string          `json:"id"`
+					Method string          `json:"method"`
+					URL    string          `json:"url"`
+					Body   json.RawMessage `json:"body"`
+				} `json:"requests"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&batch)
+
+			var responses []map[string]any
+			for i := len(batch.Requests) - 1; i >= 0; i-- {
+				req := batch.Requests[i]
+				responses = append(responses, map[string]any{
+					"id":     req.ID,
+					"status": http.StatusOK,
+					"body":   map[string]string{"echo": req.Method + " " + req.URL},
+				})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"responses": responses})
+			return
+		}
+
+		mw := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+
+			req, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				t.Errorf("failed to read batch part: %v", err)
+				return
+			}
+
+			out, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/http"}})
+			body := req.Method + " " + req.URL.Path + " " + req.Header.Get("Authorization")
+			_, _ = fmt.Fprintf(out, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n%s", len(body), body)
+		}
+		_ = mw.Close()
+	}))
+}
+
+func TestBatch(t *testing.T) {
+	server := batchServer(t)
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithBearerAuth("token"),
+	)
+
+	testCases := []struct {
+		name     string
+		format   clink.BatchFormat
+		expected []string
+	}{
+		{
+			name:     "multipart batch",
+			format:   clink.BatchMultipart,
+			expected: []string{"GET /users/1 Bearer token", "POST /users Bearer token"},
+		},
+		{
+			name:     "json batch",
+			format:   clink.BatchJSON,
+			expected: []string{`{"echo":"GET http://api.example.com/users/1"}`, `{"echo":"POST http://api.example.com/users"}`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			get, _ := http.NewRequest(http.MethodGet, "http://api.example.com/users/1", nil)
+			post, _ := http.NewRequest(http.MethodPost, "http://api.example.com/users", strings.NewReader(`{"name":"yumi"}`))
+
+			responses, err := client.Batch(context.Background(), server.URL+"/$batch", tc.format, get, post)
+			if err != nil {
+				t.Fatalf("failed to send batch: %v", err)
+			}
+
+			if len(responses) != len(tc.expected) {
+				t.Fatalf("expected %d responses, got %d", len(tc.expected), len(responses))
+			}
+
+			for i, resp := range responses {
+				body, _ := io.ReadAll(resp.Body)
+				if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != tc.expected[i] {
+					t.Errorf("unexpected response %d: %d %s", i, resp.StatusCode, body)
+				}
+			}
+		})
+	}
+}
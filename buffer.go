@@ -0,0 +1,69 @@
+package clink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which buffers are not returned to the pool, to avoid holding on to large allocations.
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// GetBuffer borrows an empty buffer from the pool shared by the client.
+// Return it with PutBuffer once its contents are no longer referenced.
+func GetBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// PutBuffer returns a buffer borrowed with GetBuffer or ReadBody to the pool.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// ReadBody reads and closes the response body into a pooled buffer.
+// Return the buffer with PutBuffer once its contents are no longer referenced.
+func ReadBody(resp *http.Response) (*bytes.Buffer, error) {
+	if resp == nil {
+		return nil, ErrNilResponse
+	}
+
+	if resp.Body == nil {
+		return nil, ErrNilBody
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	buf := GetBuffer()
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		PutBuffer(buf)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return buf, nil
+}
+
+// peekBody reads up to n bytes from the beginning of the response body, leaving the body readable from its start.
+func peekBody(resp *http.Response, n int64) []byte {
+	peeked, _ := io.ReadAll(io.LimitReader(resp.Body, n))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), resp.Body), resp.Body}
+
+	return peeked
+}
@@ -0,0 +1,42 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestReadBody(t *testing.T) {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(`{"key": "value"}`))}
+
+	buf, err := clink.ReadBody(resp)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	if buf.String() != `{"key": "value"}` {
+		t.Errorf("unexpected body: %s", buf.String())
+	}
+
+	clink.PutBuffer(buf)
+
+	if buf := clink.GetBuffer(); buf.Len() != 0 {
+		t.Errorf("expected borrowed buffer to be empty")
+	}
+
+	if _, err := clink.ReadBody(nil); err == nil {
+		t.Errorf("expected error for nil response")
+	}
+}
+
+func BenchmarkReadBody(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		resp := &http.Response{Body: io.NopCloser(strings.NewReader(`{"key": "value"}`))}
+		buf, _ := clink.ReadBody(resp)
+		clink.PutBuffer(buf)
+	}
+}
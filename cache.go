@@ -0,0 +1,359 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatusHeader is set on responses served from the cache, to "HIT" for fresh responses
+// and to "REVALIDATED" for stale responses confirmed by the server, and to "MISS" on OnlyIfCached misses.
+const CacheStatusHeader = "X-Clink-Cache"
+
+// Cache stores serialized responses by key.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
+
+// MemoryCache is a Cache keeping responses in memory.
+type MemoryCache struct {
+	mu    sync.RWMutex
+	items map[string][]byte
+}
+
+// NewMemoryCache creates an empty in memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{items: make(map[string][]byte)}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	value, ok := m.items[key]
+	return value, ok
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.items[key] = value
+}
+
+// Delete implements Cache.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.items, key)
+}
+
+// WithCache caches GET and HEAD responses in the given cache, following their Cache-Control, Expires and Vary headers.
+// Fresh responses are served from the cache and stale ones are revalidated with their ETag or Last-Modified.
+// Requests with an Authorization header are not cached unless a key function is set with WithCacheKeyFunc.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithCacheKeyFunc sets the function computing the cache key of requests, for example to cache authenticated
+// responses per user. Requests for which it returns an empty key are not cached.
+func WithCacheKeyFunc(keyFunc func(*http.Request) string) Option {
+	return func(c *Client) {
+		c.cacheKeyFunc = keyFunc
+	}
+}
+
+// DefaultCacheKey returns the cache key of GET and HEAD requests without an Authorization header, and an empty key otherwise.
+func DefaultCacheKey(req *http.Request) string {
+	if req.Header.Get("Authorization") != "" {
+		return ""
+	}
+
+	return req.Method + " " + req.URL.String()
+}
+
+// cacheMode controls how a single request interacts with the cache.
+type cacheMode int
+
+const (
+	cacheDefault cacheMode = iota
+	cacheBypass
+	cacheOnly
+	cacheRefresh
+)
+
+// NoCache sends the request without reading or storing its response in the cache.
+func NoCache() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.cacheMode = cacheBypass
+	}
+}
+
+// OnlyIfCached serves the request from the cache, even if stale, without contacting the server.
+// A 504 Gateway Timeout response is returned when the response is not cached.
+func OnlyIfCached() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.cacheMode = cacheOnly
+	}
+}
+
+// Refresh sends the request to the server even if a fresh response is cached, and stores the new response.
+func Refresh() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.cacheMode = cacheRefresh
+	}
+}
+
+// cacheEntry is a cached response along with the request header values it varies on.
+type cacheEntry struct {
+	StatusCode int                 `json:"status_code"`
+	Header     http.Header         `json:"header"`
+	Body       []byte              `json:"body"`
+	Vary       map[string][]string `json:"vary,omitempty"`
+	StoredAt   time.Time           `json:"stored_at"`
+}
+
+// cacheKey returns the cache key of the request, or an empty key when it must not be cached.
+func (c *Client) cacheKey(req *http.Request) string {
+	if c.cache == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return ""
+	}
+
+	if hasDirective(req.Header.Get("Cache-Control"), "no-store") || requestConfigFrom(req).cacheMode == cacheBypass {
+		return ""
+	}
+
+	keyFunc := c.cacheKeyFunc
+	if keyFunc == nil {
+		keyFunc = DefaultCacheKey
+	}
+
+	return keyFunc(req)
+}
+
+// doCached sends the request through the cache stored under key.
+func (c *Client) doCached(req *http.Request, key string) (*http.Response, error) {
+	mode := requestConfigFrom(req).cacheMode
+	entry := c.cachedEntry(req, key)
+
+	switch {
+	case mode == cacheOnly && entry != nil:
+		return entry.response(req, "HIT"), nil
+	case mode == cacheOnly:
+		return gatewayTimeout(req), nil
+	case mode == cacheRefresh:
+		entry = nil
+	case entry != nil && entry.fresh(c.clock().Now()):
+		return entry.response(req, "HIT"), nil
+	}
+
+	conditional := entry != nil && addValidators(req, entry)
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		discardBody(resp)
+		for name, values := range resp.Header {
+			entry.Header[name] = values
+		}
+		entry.StoredAt = c.clock().Now()
+		c.storeEntry(key, entry)
+
+		return entry.response(req, "REVALIDATED"), nil
+	}
+
+	return c.storeResponse(req, key, resp)
+}
+
+// cachedEntry returns the entry stored under key if it matches the request headers it varies on.
+func (c *Client) cachedEntry(req *http.Request, key string) *cacheEntry {
+	data, ok := c.cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		c.cache.Delete(key)
+		return nil
+	}
+
+	for name, values := range entry.Vary {
+		if strings.Join(req.Header.Values(name), ",") != strings.Join(values, ",") {
+			return nil
+		}
+	}
+
+	return &entry
+}
+
+// storeResponse stores the response under key if it is cacheable, and returns it with a replayable body.
+func (c *Client) storeResponse(req *http.Request, key string, resp *http.Response) (*http.Response, error) {
+	if !cacheable(resp) {
+		return resp, nil
+	}
+
+	entry := &cacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		StoredAt:   c.clock().Now(),
+	}
+
+	for _, field := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(field, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "*" {
+				return resp, nil
+			}
+			if name == "" {
+				continue
+			}
+			if entry.Vary == nil {
+				entry.Vary = make(map[string][]string)
+			}
+			entry.Vary[name] = req.Header.Values(name)
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	entry.Body = body
+
+	c.storeEntry(key, entry)
+
+	return entry.response(req, ""), nil
+}
+
+func (c *Client) storeEntry(key string, entry *cacheEntry) {
+	if data, err := json.Marshal(entry); err == nil {
+		c.cache.Set(key, data)
+	}
+}
+
+// response returns the cached response for the request, with the CacheStatusHeader set to status if not empty.
+func (e *cacheEntry) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	if status != "" {
+		header.Set(CacheStatusHeader, status)
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// gatewayTimeout returns the response to OnlyIfCached requests missing from the cache.
+func gatewayTimeout(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:     "504 " + http.StatusText(http.StatusGatewayTimeout),
+		StatusCode: http.StatusGatewayTimeout,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{CacheStatusHeader: {"MISS"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+}
+
+// fresh reports whether the entry can be served without revalidation at the given time.
+func (e *cacheEntry) fresh(now time.Time) bool {
+	cacheControl := e.Header.Get("Cache-Control")
+	if hasDirective(cacheControl, "no-cache") {
+		return false
+	}
+
+	age := now.Sub(e.StoredAt)
+	if value, ok := directiveValue(cacheControl, "max-age"); ok {
+		maxAge, err := strconv.Atoi(value)
+		return err == nil && age < time.Duration(maxAge)*time.Second
+	}
+
+	if expires, err := http.ParseTime(e.Header.Get("Expires")); err == nil {
+		date, err := http.ParseTime(e.Header.Get("Date"))
+		if err != nil {
+			date = e.StoredAt
+		}
+		return age < expires.Sub(date)
+	}
+
+	return false
+}
+
+// addValidators makes the request conditional on the validators of the entry, and reports whether it did.
+func addValidators(req *http.Request, entry *cacheEntry) bool {
+	if req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return false
+	}
+
+	etag, lastModified := entry.Header.Get("ETag"), entry.Header.Get("Last-Modified")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return etag != "" || lastModified != ""
+}
+
+// cacheable reports whether the response may be stored.
+func cacheable(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusMovedPermanently, http.StatusNotFound, http.StatusGone:
+	default:
+		return false
+	}
+
+	cacheControl := resp.Header.Get("Cache-Control")
+	if hasDirective(cacheControl, "no-store") {
+		return false
+	}
+
+	_, hasMaxAge := directiveValue(cacheControl, "max-age")
+	return hasMaxAge || resp.Header.Get("Expires") != "" || resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// hasDirective reports whether the Cache-Control header value holds the directive.
+func hasDirective(cacheControl, directive string) bool {
+	_, ok := directiveValue(cacheControl, directive)
+	return ok
+}
+
+// directiveValue returns the value of the directive of the Cache-Control header value.
+func directiveValue(cacheControl, directive string) (string, bool) {
+	for _, part := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if strings.EqualFold(name, directive) {
+			return strings.Trim(value, `"`), true
+		}
+	}
+
+	return "", false
+}
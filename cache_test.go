@@ -0,0 +1,174 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Cache(t *testing.T) {
+	testCases := []struct {
+		name     string
+		handler  http.HandlerFunc
+		opts     []clink.Option
+		requests []map[string]string
+		bodies   []string
+		hits     int32
+	}{
+		{
+			name: "serves fresh responses from the cache",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				_, _ = w.Write([]byte("fresh"))
+			},
+			requests: []map[string]string{nil, nil},
+			bodies:   []string{"fresh", "fresh"},
+			hits:     1,
+		},
+		{
+			name: "does not cache no-store responses",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-store, max-age=60")
+				_, _ = w.Write([]byte("private"))
+			},
+			requests: []map[string]string{nil, nil},
+			bodies:   []string{"private", "private"},
+			hits:     2,
+		},
+		{
+			name: "revalidates stale responses",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"v1"`)
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = w.Write([]byte("tagged"))
+			},
+			requests: []map[string]string{nil, nil},
+			bodies:   []string{"tagged", "tagged"},
+			hits:     2,
+		},
+		{
+			name: "stores a variant per varying header",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				w.Header().Set("Vary", "Accept-Language")
+				_, _ = w.Write([]byte(r.Header.Get("Accept-Language")))
+			},
+			requests: []map[string]string{{"Accept-Language": "en"}, {"Accept-Language": "fr"}, {"Accept-Language": "fr"}},
+			bodies:   []string{"en", "fr", "fr"},
+			hits:     2,
+		},
+		{
+			name: "does not cache authenticated requests by default",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+			},
+			requests: []map[string]string{{"Authorization": "alice"}, {"Authorization": "bob"}},
+			bodies:   []string{"alice", "bob"},
+			hits:     2,
+		},
+		{
+			name: "caches authenticated requests per user with a key function",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+			},
+			opts: []clink.Option{clink.WithCacheKeyFunc(func(r *http.Request) string {
+				return r.Header.Get("Authorization") + " " + r.URL.String()
+			})},
+			requests: []map[string]string{{"Authorization": "alice"}, {"Authorization": "bob"}, {"Authorization": "alice"}},
+			bodies:   []string{"alice", "bob", "alice"},
+			hits:     2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				tc.handler(w, r)
+			}))
+			defer server.Close()
+
+			opts := append([]clink.Option{clink.WithCache(clink.NewMemoryCache())}, tc.opts...)
+			c := clink.NewClient(append(opts, clink.WithClient(server.Client()))...)
+
+			for i, headers := range tc.requests {
+				req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+				for key, value := range headers {
+					req.Header.Set(key, value)
+				}
+
+				resp, err := c.Do(req)
+				if err != nil {
+					t.Fatalf("request %d failed: %v", i, err)
+				}
+
+				body, _ := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				if string(body) != tc.bodies[i] {
+					t.Errorf("expected request %d body to be %q, got %q", i, tc.bodies[i], body)
+				}
+			}
+
+			if hits.Load() != tc.hits {
+				t.Errorf("expected %d requests to reach the server, got %d", tc.hits, hits.Load())
+			}
+		})
+	}
+}
+
+func TestClient_CacheDirectives(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("cached"))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithCache(clink.NewMemoryCache()), clink.WithClient(server.Client()))
+
+	testCases := []struct {
+		name   string
+		opts   []clink.RequestOption
+		status int
+		cache  string
+		hits   int32
+	}{
+		{name: "only if cached misses", opts: []clink.RequestOption{clink.OnlyIfCached()}, status: http.StatusGatewayTimeout, cache: "MISS", hits: 0},
+		{name: "no cache bypasses the cache", opts: []clink.RequestOption{clink.NoCache()}, status: http.StatusOK, hits: 1},
+		{name: "first request is stored", status: http.StatusOK, hits: 2},
+		{name: "second request hits", status: http.StatusOK, cache: "HIT", hits: 2},
+		{name: "refresh goes to the server", opts: []clink.RequestOption{clink.Refresh()}, status: http.StatusOK, hits: 3},
+		{name: "only if cached hits", opts: []clink.RequestOption{clink.OnlyIfCached()}, status: http.StatusOK, cache: "HIT", hits: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := c.Get(server.URL, tc.opts...)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if cache := resp.Header.Get(clink.CacheStatusHeader); cache != tc.cache {
+				t.Errorf("expected cache status %q, got %q", tc.cache, cache)
+			}
+			if hits.Load() != tc.hits {
+				t.Errorf("expected %d requests to reach the server, got %d", tc.hits, hits.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package clink
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WithBodyCapture keeps a copy of up to limit bytes of every response body as it is read,
+// so that error handlers and logs can show the payload after a decoder consumed it. See CapturedBody.
+func WithBodyCapture(limit int) Option {
+	return func(c *Client) {
+		c.captureLimit = limit
+	}
+}
+
+// CapturedBody returns the beginning of the response body read so far, for responses of clients created
+// with WithBodyCapture. It returns false if the body of the response is not captured.
+func CapturedBody(resp *http.Response) ([]byte, bool) {
+	if resp == nil {
+		return nil, false
+	}
+
+	body, ok := resp.Body.(*capturingBody)
+	if !ok {
+		return nil, false
+	}
+
+	body.mu.Lock()
+	defer body.mu.Unlock()
+
+	return append([]byte(nil), body.captured...), true
+}
+
+// captureBody wraps the response body so that its beginning is captured as it is read.
+func (c *Client) captureBody(resp *http.Response) {
+	if c.captureLimit <= 0 || resp.Body == nil {
+		return
+	}
+
+	resp.Body = &capturingBody{ReadCloser: resp.Body, limit: c.captureLimit}
+}
+
+type capturingBody struct {
+	io.ReadCloser
+
+	mu       sync.Mutex
+	limit    int
+	captured []byte
+}
+
+// Read implements io.Reader.
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	if remaining := b.limit - len(b.captured); remaining > 0 && n > 0 {
+		b.captured = append(b.captured, p[:min(n, remaining)]...)
+	}
+	b.mu.Unlock()
+
+	return n, err
+}
@@ -0,0 +1,50 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_BodyCapture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error": "invalid token"}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		captured bool
+		expected string
+	}{
+		{name: "captures up to the limit", opts: []clink.Option{clink.WithBodyCapture(10)}, captured: true, expected: `{"error": `},
+		{name: "captures the whole body", opts: []clink.Option{clink.WithBodyCapture(1024)}, captured: true, expected: `{"error": "invalid token"}`},
+		{name: "no capture by default"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(append(tc.opts, clink.WithClient(server.Client()))...)
+
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			var target struct {
+				Count int `json:"count"`
+			}
+			if err := clink.ResponseToJson(resp, &target); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			captured, ok := clink.CapturedBody(resp)
+			if ok != tc.captured || string(captured) != tc.expected {
+				t.Errorf("expected captured body %q, got %q (%v)", tc.expected, captured, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package clink
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ChallengeSolution holds the cookies and headers a challenged request is retried with.
+type ChallengeSolution struct {
+	Cookies []*http.Cookie
+	Header  http.Header
+}
+
+// ChallengeSolver solves the bot protection challenge of a response, for example with a headless browser.
+type ChallengeSolver func(req *http.Request, resp *http.Response) (*ChallengeSolution, error)
+
+// WithChallengeSolver hands challenge responses, as detected by IsChallenge, to the solver and retries the request once
+// with the cookies and headers of its solution. The cookies are also stored in the cookie jar of the http client, if any,
+// so that the following requests are not challenged again.
+func WithChallengeSolver(solver ChallengeSolver) Option {
+	return func(c *Client) {
+		c.challengeSolver = solver
+	}
+}
+
+// IsChallenge reports whether the response is a bot protection challenge, such as Cloudflare's.
+// The beginning of the body is read to find known markers and left readable.
+func IsChallenge(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+	default:
+		return false
+	}
+
+	if resp.Header.Get("Cf-Mitigated") == "challenge" {
+		return true
+	}
+
+	return resp.Body != nil && containsAny(peekBody(resp, 8192), challengeMarkers)
+}
+
+// solveChallenge retries the request with the solution to the challenge of the response.
+// The response is returned as is when the challenge cannot be solved or the request body cannot be replayed.
+func (c *Client) solveChallenge(req *http.Request, resp *http.Response) (*http.Response, error) {
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	solution, err := c.challengeSolver(req, resp)
+	if err != nil {
+		discardBody(resp)
+		return nil, fmt.Errorf("failed to solve challenge: %w", err)
+	}
+	if solution == nil {
+		return resp, nil
+	}
+	discardBody(resp)
+
+	if jar := c.HttpClient.Jar; jar != nil {
+		jar.SetCookies(req.URL, solution.Cookies)
+	} else {
+		for _, cookie := range solution.Cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	for key, values := range solution.Header {
+		req.Header[key] = values
+	}
+
+	if hasBody {
+		if req.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+	}
+
+	return c.doWithServerName(req)
+}
@@ -0,0 +1,58 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_ChallengeSolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("cf_clearance"); err != nil || cookie.Value != "solved" || r.Header.Get("User-Agent") != "solver" {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<script src="/cdn-cgi/challenge-platform/h/g/orchestrate"></script>`))
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	jar, _ := cookiejar.New(nil)
+	httpClient := server.Client()
+	httpClient.Jar = jar
+
+	solved := 0
+	c := clink.NewClient(
+		clink.WithUserAgent("solver"),
+		clink.WithChallengeSolver(func(req *http.Request, resp *http.Response) (*clink.ChallengeSolution, error) {
+			solved++
+			return &clink.ChallengeSolution{Cookies: []*http.Cookie{{Name: "cf_clearance", Value: "solved"}}}, nil
+		}),
+		clink.WithClient(httpClient),
+	)
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Post(server.URL, strings.NewReader("payload"))
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+			t.Errorf("expected request %d to succeed with its body, got %d %q", i, resp.StatusCode, body)
+		}
+	}
+
+	if solved != 1 {
+		t.Errorf("expected the challenge to be solved once, got %d", solved)
+	}
+}
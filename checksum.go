@@ -0,0 +1,194 @@
+package clink
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Integrity headers computed and verified by WithChecksums.
+const (
+	ChecksumContentMD5 = "Content-MD5"
+	ChecksumCRC32      = "X-Amz-Checksum-Crc32"
+	ChecksumCRC32C     = "X-Amz-Checksum-Crc32c"
+	ChecksumSHA1       = "X-Amz-Checksum-Sha1"
+	ChecksumSHA256     = "X-Amz-Checksum-Sha256"
+)
+
+// checksumHeaders are the integrity headers verified on responses, in order of preference.
+var checksumHeaders = []string{ChecksumSHA256, ChecksumSHA1, ChecksumCRC32C, ChecksumCRC32, ChecksumContentMD5}
+
+// ErrChecksumMismatch is matched by the *ChecksumError returned when a response body does not match its integrity header.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChecksumError reports a response body that does not match its integrity header.
+type ChecksumError struct {
+	URL      string
+	Header   string
+	Expected string
+	Actual   string
+}
+
+// Error implements the error interface.
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %s: %s is %s, body has %s", e.URL, e.Header, e.Expected, e.Actual)
+}
+
+// Is makes errors.Is match ErrChecksumMismatch.
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// Checksums configures the integrity headers of WithChecksums.
+type Checksums struct {
+	// Upload is the integrity header computed for request bodies that can be read again, such as ChecksumSHA256.
+	// No header is computed when empty or when the request already has it.
+	Upload string
+	// Verify checks the body of responses against their integrity header as it is read, with the first of
+	// x-amz-checksum-sha256, -sha1, -crc32c, -crc32 and Content-MD5 found. Partial and transparently
+	// decompressed responses are not verified.
+	Verify bool
+	// FailOnMismatch makes reading the end of a mismatching body fail with a *ChecksumError.
+	FailOnMismatch bool
+	// OnMismatch is called with the *ChecksumError of mismatching bodies.
+	OnMismatch func(*ChecksumError)
+}
+
+// WithChecksums computes integrity headers for uploads and verifies those of downloads.
+// Algorithms that are not approved in FIPS mode are neither computed nor verified.
+func WithChecksums(checksums Checksums) Option {
+	return func(c *Client) {
+		c.checksums = &checksums
+	}
+}
+
+// newChecksum returns the hash of the integrity header.
+func (c *Client) newChecksum(header string) (hash.Hash, error) {
+	switch {
+	case strings.EqualFold(header, ChecksumContentMD5):
+		return c.NewHash(HashMD5)
+	case strings.EqualFold(header, ChecksumSHA1):
+		return c.NewHash(HashSHA1)
+	case strings.EqualFold(header, ChecksumSHA256):
+		return c.NewHash(HashSHA256)
+	case strings.EqualFold(header, ChecksumCRC32):
+		return crc32.NewIEEE(), nil
+	case strings.EqualFold(header, ChecksumCRC32C):
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported checksum header %q", header)
+}
+
+// setUploadChecksum sets the integrity header of the request body.
+func (c *Client) setUploadChecksum(req *http.Request) error {
+	if c.checksums == nil || c.checksums.Upload == "" || req.GetBody == nil || req.Header.Get(c.checksums.Upload) != "" {
+		return nil
+	}
+
+	h, err := c.newChecksum(c.checksums.Upload)
+	if errors.Is(err, ErrUnapprovedAlgorithm) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(body)
+
+	if _, err := io.Copy(h, body); err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	req.Header.Set(c.checksums.Upload, base64.StdEncoding.EncodeToString(h.Sum(nil)))
+
+	return nil
+}
+
+// verifyChecksum wraps the response body so that it is verified against its integrity header as it is read.
+func (c *Client) verifyChecksum(req *http.Request, resp *http.Response) {
+	if c.checksums == nil || !c.checksums.Verify || resp.Body == nil || req.Method == http.MethodHead ||
+		resp.StatusCode == http.StatusPartialContent || resp.Uncompressed {
+		return
+	}
+
+	for _, header := range checksumHeaders {
+		expected := resp.Header.Get(header)
+		if expected == "" {
+			continue
+		}
+
+		h, err := c.newChecksum(header)
+		if err != nil {
+			continue
+		}
+
+		resp.Body = &checksumBody{
+			ReadCloser: resp.Body,
+			hash:       h,
+			checksums:  c.checksums,
+			err:        &ChecksumError{URL: req.URL.String(), Header: header, Expected: strings.TrimSpace(expected)},
+		}
+		return
+	}
+}
+
+// checksumBody hashes the body as it is read and compares it with the expected checksum at the end.
+type checksumBody struct {
+	io.ReadCloser
+	hash      hash.Hash
+	checksums *Checksums
+	err       *ChecksumError
+	checked   bool
+}
+
+// Read implements io.Reader.
+func (b *checksumBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+
+	if errors.Is(err, io.EOF) && !b.checked {
+		b.checked = true
+		if mismatch := b.check(); mismatch != nil {
+			if b.checksums.OnMismatch != nil {
+				b.checksums.OnMismatch(mismatch)
+			}
+			if b.checksums.FailOnMismatch {
+				return n, mismatch
+			}
+		}
+	}
+
+	return n, err
+}
+
+// check returns a *ChecksumError if the hash of the body differs from the expected checksum.
+// CRC32 checksums are also accepted in hex, as sent by some servers.
+func (b *checksumBody) check() *ChecksumError {
+	sum := b.hash.Sum(nil)
+	actual := base64.StdEncoding.EncodeToString(sum)
+	if actual == b.err.Expected {
+		return nil
+	}
+
+	if len(sum) == 4 && strings.EqualFold(b.err.Expected, fmt.Sprintf("%08x", binary.BigEndian.Uint32(sum))) {
+		return nil
+	}
+
+	mismatch := *b.err
+	mismatch.Actual = actual
+
+	return &mismatch
+}
@@ -0,0 +1,111 @@
+package clink_test
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithChecksums_Upload(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   func([]byte) string
+	}{
+		{name: "sha256", header: clink.ChecksumSHA256, want: func(b []byte) string {
+			sum := sha256.Sum256(b)
+			return base64.StdEncoding.EncodeToString(sum[:])
+		}},
+		{name: "content-md5", header: clink.ChecksumContentMD5, want: func(b []byte) string {
+			sum := md5.Sum(b)
+			return base64.StdEncoding.EncodeToString(sum[:])
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(tt.header)
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(clink.WithChecksums(clink.Checksums{Upload: tt.header}), clink.WithClient(server.Client()))
+
+			resp, err := client.Put(server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if want := tt.want([]byte("payload")); got != want {
+				t.Errorf("expected %s %s, got %s", tt.header, want, got)
+			}
+		})
+	}
+}
+
+func TestWithChecksums_Verify(t *testing.T) {
+	body := "downloaded content"
+	sha := sha256.Sum256([]byte(body))
+	crc := crc32.Checksum([]byte(body), crc32.MakeTable(crc32.Castagnoli))
+	crcBytes := []byte{byte(crc >> 24), byte(crc >> 16), byte(crc >> 8), byte(crc)}
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		fail     bool
+		wantErr  bool
+		wantCall bool
+	}{
+		{name: "matching sha256", header: clink.ChecksumSHA256, value: base64.StdEncoding.EncodeToString(sha[:]), fail: true},
+		{name: "matching crc32c", header: clink.ChecksumCRC32C, value: base64.StdEncoding.EncodeToString(crcBytes), fail: true},
+		{name: "mismatch fails", header: clink.ChecksumSHA256, value: base64.StdEncoding.EncodeToString(make([]byte, 32)), fail: true, wantErr: true, wantCall: true},
+		{name: "mismatch reported", header: clink.ChecksumContentMD5, value: "AAAAAAAAAAAAAAAAAAAAAA==", wantCall: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(tt.header, tt.value)
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			var mismatch *clink.ChecksumError
+			client := clink.NewClient(clink.WithChecksums(clink.Checksums{
+				Verify:         true,
+				FailOnMismatch: tt.fail,
+				OnMismatch:     func(err *clink.ChecksumError) { mismatch = err },
+			}), clink.WithClient(server.Client()))
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			data, err := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			if tt.wantErr != errors.Is(err, clink.ErrChecksumMismatch) {
+				t.Errorf("expected mismatch error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && string(data) != body {
+				t.Errorf("unexpected body %q", data)
+			}
+			if tt.wantCall != (mismatch != nil) {
+				t.Errorf("expected mismatch callback %v, got %v", tt.wantCall, mismatch)
+			}
+		})
+	}
+}
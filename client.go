@@ -2,11 +2,17 @@ package clink
 
 import (
 	"bytes"
+	"container/list"
+	"context"
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -17,8 +23,83 @@ type Client struct {
 	HttpClient      *http.Client
 	Headers         map[string]string
 	RateLimiter     *rate.Limiter
+	Limiter         Limiter
 	MaxRetries      int
 	ShouldRetryFunc func(*http.Request, *http.Response, error) bool
+	BackoffFunc     func(attempt int, resp *http.Response) time.Duration
+
+	dialer           *net.Dialer
+	dialNetwork      string
+	hostOverrides    map[string]string
+	transportOptions []func(*http.Transport)
+	connectionHooks  *ConnectionHooks
+
+	transportWrappers []func(http.RoundTripper) http.RoundTripper
+	baseTransport     http.RoundTripper
+
+	informationalHooks []InformationalHook
+	middleware         []scopedMiddleware
+
+	rateLimitNoWait   bool
+	rateLimitWaitHook func(req *http.Request, waited time.Duration)
+	stateHook         func(StateEvent)
+	limiterSaturated  atomic.Bool
+	quota             *quota
+	quotaEnforced     bool
+
+	headerPrecedence   HeaderPrecedence
+	forcedHeaders      map[string]string
+	userAgentProducts  []string
+	defaultContentType string
+
+	baseURL        *url.URL
+	rules          []Rule
+	routeTemplates [][]string
+	trafficSplit   *trafficSplit
+	shadow         *shadowMode
+	expectedStatus []int
+	statusErrors   bool
+	jsonCodec      *jsonCodec
+	decodeOptions  []DecodeOption
+	fipsMode       bool
+	captureLimit   int
+	spool          *spoolConfig
+	checksums      *Checksums
+	replay         *replayStamper
+	csrf           *csrfTokens
+	isSkewError    func(*http.Response) bool
+	clockOffset    int64
+	timeSource     Clock
+	random         *lockedRand
+
+	signingCredentials *SigningCredentials
+
+	detectMaintenance bool
+	challengeSolver   ChallengeSolver
+	robots            *robotsCache
+	proxies           *proxyPool
+	slo               *sloTracker
+	concurrency       *concurrencyLimiter
+	negativeCache     *negativeCache
+	mutationAudit     func(ConfigMutation)
+
+	cache        Cache
+	cacheKeyFunc func(*http.Request) string
+
+	mu                sync.Mutex
+	serverNameClients map[string]*list.Element
+	serverNameLRU     *list.List
+	session           *session
+	sessionExpired    func(*http.Response) bool
+
+	endpoints   *endpointPool
+	healthCheck HealthCheck
+
+	background []func(context.Context)
+	stop       context.CancelFunc
+	wg         sync.WaitGroup
+	closeOnce  sync.Once
+	closed     chan struct{}
 }
 
 // NewClient creates a new client with the given options.
@@ -29,64 +110,233 @@ func NewClient(opts ...Option) *Client {
 		opt(c)
 	}
 
+	if limiter, ok := c.Limiter.(clockSetter); ok && c.timeSource != nil {
+		limiter.setClock(c.timeSource)
+	}
+
+	c.configureTransport()
+	c.startBackground()
+
 	return c
 }
 
+// startBackground starts the background tasks registered by the options.
+func (c *Client) startBackground() {
+	if len(c.background) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stop = cancel
+
+	for _, task := range c.background {
+		c.wg.Add(1)
+		go func(task func(context.Context)) {
+			defer c.wg.Done()
+			task(ctx)
+		}(task)
+	}
+}
+
+// Close stops the client's background tasks and waits for them to finish.
+// It is only required for clients created with options that run in the background, such as WithKeepWarm,
+// or running scheduled requests.
+func (c *Client) Close() error {
+	if c.stop != nil {
+		c.stop()
+	}
+	c.closeOnce.Do(func() {
+		close(c.closing())
+	})
+	c.wg.Wait()
+
+	return nil
+}
+
+// closing returns a channel closed when the client is closed.
+func (c *Client) closing() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed == nil {
+		c.closed = make(chan struct{})
+	}
+
+	return c.closed
+}
+
 func defaultClient() *Client {
 	return &Client{
 		HttpClient: http.DefaultClient,
 		Headers:    make(map[string]string),
+		fipsMode:   fipsDefault,
 	}
 }
 
 // Do sends the given request and returns the response.
 // If the request is rate limited, the client will wait for the rate limiter to allow the request.
 // If the request fails, the client will retry the request the number of times specified by MaxRetries.
+// Errors are returned as an *Error wrapping the cause with the context of the request.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	for key, value := range c.Headers {
-		req.Header.Set(key, value)
+	stats := &requestStats{}
+	start := c.clock().Now()
+	snapshot := c.snapshotConfig()
+
+	tracked := req.WithContext(context.WithValue(req.Context(), requestStatsKey{}, stats))
+	resp, err := c.do(tracked)
+	c.auditConfig(tracked, snapshot)
+	if err == nil && stats.panic != nil {
+		discardBody(resp)
+		resp, err = nil, stats.panic
+	}
+	if err != nil {
+		return nil, newError(req, stats, c.clock().Now().Sub(start), err)
 	}
 
-	if c.RateLimiter != nil {
-		if err := c.RateLimiter.Wait(req.Context()); err != nil {
-			return nil, fmt.Errorf("failed to wait for rate limiter: %w", err)
-		}
+	return resp, nil
+}
+
+// do runs the request through the pipeline of the client.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req, err := c.prepare(req)
+	if err != nil {
+		return nil, err
 	}
 
-	var resp *http.Response
-	var body []byte
-	var err error
+	shadow, err := c.startShadow(req)
+	if err != nil {
+		return nil, err
+	}
 
-	if req.Body != nil && req.Body != http.NoBody {
-		body, err = io.ReadAll(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
+	var resp *http.Response
+	start := c.clock().Now()
+	if cached, cachedErr, ok := c.cachedFailure(req); ok {
+		resp, err = cached, cachedErr
+	} else {
+		if key := c.cacheKey(req); key != "" {
+			resp, err = c.doCached(req, key)
+		} else {
+			resp, err = c.send(req)
 		}
+		c.storeFailure(req, resp, err)
+	}
+	c.observeSLO(req, resp, err, c.clock().Now().Sub(start))
+	if shadow != nil {
+		shadow.observe(resp, err)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		err = req.Body.Close()
-		if err != nil {
-			return nil, fmt.Errorf("failed to close request body: %w", err)
-		}
+	if err := c.checkStatus(req, resp); err != nil {
+		return nil, err
+	}
+
+	c.verifyChecksum(req, resp)
+
+	if err := c.spoolBody(resp); err != nil {
+		return nil, err
+	}
+
+	c.captureBody(resp)
+
+	return resp, nil
+}
+
+// send waits for the rate limiter and sends the request, retrying it as configured.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if err := c.checkRobots(req); err != nil {
+		return nil, err
 	}
 
+	if err := c.waitLimiter(req); err != nil {
+		return nil, err
+	}
+
+	getBody, err := c.replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	shouldRetry := c.ShouldRetryFunc
+	if shouldRetry == nil {
+		shouldRetry = RetryOnNetworkErrors
+	}
+
+	var resp *http.Response
+	skewCorrected, reloggedIn := false, false
+	cookies := append([]string(nil), req.Header.Values("Cookie")...)
 	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
-		if len(body) > 0 {
-			req.Body = io.NopCloser(bytes.NewReader(body))
+		if attempt > 0 && getBody != nil {
+			if req.Body, err = getBody(); err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+		}
+
+		if err := c.takeQuota(); err != nil {
+			return nil, err
 		}
 
-		resp, err = c.HttpClient.Do(req)
+		sentAt := c.clock().Now()
+		resp, err = c.attempt(req)
+
+		if err == nil && !skewCorrected && c.correctClockSkew(resp) {
+			skewCorrected = true
+			if resp, err = c.resend(req, resp, getBody); err != nil {
+				return nil, err
+			}
+		}
+
+		if err == nil && !reloggedIn && c.shouldRelogin(req, resp) {
+			reloggedIn = true
+			discardBody(resp)
+			if err := c.relogin(req.Context(), sentAt); err != nil {
+				return nil, err
+			}
+			// The jar added the expired session cookies to the request headers.
+			req.Header.Del("Cookie")
+			for _, cookie := range cookies {
+				req.Header.Add("Cookie", cookie)
+			}
+			if resp, err = c.resend(req, nil, getBody); err != nil {
+				return nil, err
+			}
+		}
 
 		if req.Context().Err() != nil {
 			return nil, fmt.Errorf("request context error: %w", req.Context().Err())
 		}
 
-		if c.ShouldRetryFunc != nil && !c.ShouldRetryFunc(req, resp, err) {
+		retry, panicErr := callShouldRetry(shouldRetry, req, resp, err)
+		if panicErr != nil {
+			discardBody(resp)
+			return nil, panicErr
+		}
+		if !retry {
 			break
 		}
 
+		if attempt == c.MaxRetries && c.MaxRetries > 0 {
+			event := StateEvent{Kind: RetriesExhausted, Request: req, Attempts: attempt + 1, Err: err}
+			if resp != nil {
+				event.StatusCode = resp.StatusCode
+			}
+			c.emitState(event)
+
+			if stats, ok := requestValue[*requestStats](req, requestStatsKey{}); ok {
+				stats.retriesExhausted = true
+			}
+		}
+
 		if attempt < c.MaxRetries {
+			delay, err := c.backoff(attempt, resp)
+			discardBody(resp)
+			if err != nil {
+				return nil, err
+			}
+
 			select {
-			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-clockAfter(req.Context(), c.clock(), delay):
 			case <-req.Context().Done():
 				return nil, req.Context().Err()
 			}
@@ -100,18 +350,143 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+// attempt sends the request once.
+func (c *Client) attempt(req *http.Request) (*http.Response, error) {
+	c.stampRequest(req)
+	c.injectCSRF(req)
+
+	release, err := c.acquireConcurrency(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats, ok := requestValue[*requestStats](req, requestStatsKey{}); ok {
+		stats.attempts++
+	}
+
+	endpoint := c.useEndpoint(req)
+	sent, proxy := c.useProxy(req)
+	resp, err := c.handle(sent)
+	release(resp, err)
+	c.reportProxy(req, proxy, resp, err)
+	if err == nil && c.challengeSolver != nil && IsChallenge(resp) {
+		resp, err = c.solveChallenge(sent, resp)
+	}
+	resp, err = c.detectUnavailable(req, resp, err)
+	c.reportEndpoint(req, endpoint, resp, err)
+	if err == nil {
+		c.extractCSRF(req, resp)
+	}
+
+	return resp, err
+}
+
+// resend discards the response and sends the request again, outside of the retry count.
+func (c *Client) resend(req *http.Request, resp *http.Response, getBody func() (io.ReadCloser, error)) (*http.Response, error) {
+	discardBody(resp)
+
+	if getBody != nil {
+		var err error
+		if req.Body, err = getBody(); err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+	}
+
+	if err := c.takeQuota(); err != nil {
+		return nil, err
+	}
+
+	return c.attempt(req)
+}
+
+// replayableBody returns a function producing a fresh copy of the request body for retries.
+// It returns nil when the request has no body or cannot be retried, in which case the body is sent as is.
+// Bodies without GetBody are read into memory once so that they can be replayed.
+func (c *Client) replayableBody(req *http.Request) (func() (io.ReadCloser, error), error) {
+	replays := c.MaxRetries > 0 || c.isSkewError != nil || c.challengeSolver != nil || c.sessionExpired != nil ||
+		c.shadow != nil
+	if !replays || req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if err := req.Body.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close request body: %w", err)
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+
+	return req.GetBody, nil
+}
+
+// prepare applies the client and request configuration to the request before it is sent.
+func (c *Client) prepare(req *http.Request) (*http.Request, error) {
+	c.resolveURL(req)
+	c.applyHeaders(req)
+	c.applyRules(req)
+	req = c.splitTraffic(req)
+
+	cfg := requestConfigFrom(req)
+	if cfg.host != "" {
+		req.Host = cfg.host
+	}
+
+	if cfg.body != nil {
+		if err := cfg.body(c, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.setUploadChecksum(req); err != nil {
+		return nil, err
+	}
+
+	for _, trailer := range cfg.trailers {
+		if err := trailer(c, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.jsonCodec != nil {
+		req = req.WithContext(context.WithValue(req.Context(), jsonCodecKey{}, c.jsonCodec))
+	}
+
+	if len(c.decodeOptions) > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), decodeOptionsKey{}, c.decodeOptions))
+	}
+
+	if c.timeSource != nil {
+		req = req.WithContext(context.WithValue(req.Context(), clockKey{}, c.timeSource))
+	}
+
+	req = c.withRouteTemplates(req)
+
+	return c.traceInformational(c.traceConnections(req)), nil
+}
+
 // Head sends a HEAD request to the given URL.
-func (c *Client) Head(url string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodHead, url, nil)
+func (c *Client) Head(url string, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodHead, url, nil, opts)
 	if err != nil {
 		return nil, err
 	}
 	return c.Do(req)
 }
 
-// Get sends a GET request to the given URL.
-func (c *Client) Options(url string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodOptions, url, nil)
+// Options sends an OPTIONS request to the given URL.
+func (c *Client) Options(url string, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodOptions, url, nil, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -119,8 +494,8 @@ func (c *Client) Options(url string) (*http.Response, error) {
 }
 
 // Get sends a GET request to the given URL.
-func (c *Client) Get(url string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+func (c *Client) Get(url string, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodGet, url, nil, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -128,8 +503,8 @@ func (c *Client) Get(url string) (*http.Response, error) {
 }
 
 // Post sends a POST request to the given URL with the given body.
-func (c *Client) Post(url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, url, body)
+func (c *Client) Post(url string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodPost, url, body, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -137,8 +512,8 @@ func (c *Client) Post(url string, body io.Reader) (*http.Response, error) {
 }
 
 // Put sends a PUT request to the given URL.
-func (c *Client) Put(url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPut, url, body)
+func (c *Client) Put(url string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodPut, url, body, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +521,8 @@ func (c *Client) Put(url string, body io.Reader) (*http.Response, error) {
 }
 
 // Patch sends a PATCH request to the given URL.
-func (c *Client) Patch(url string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPatch, url, body)
+func (c *Client) Patch(url string, body io.Reader, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodPatch, url, body, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -155,8 +530,8 @@ func (c *Client) Patch(url string, body io.Reader) (*http.Response, error) {
 }
 
 // Delete sends a DELETE request to the given URL.
-func (c *Client) Delete(url string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+func (c *Client) Delete(url string, opts ...RequestOption) (*http.Response, error) {
+	req, err := newRequest(http.MethodDelete, url, nil, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -220,6 +595,7 @@ func WithUserAgent(ua string) Option {
 }
 
 // WithRetries sets the retry count and retry function for the client.
+// When retryFunc is nil, requests are retried on retryable network errors (see RetryOnNetworkErrors).
 func WithRetries(count int, retryFunc func(*http.Request, *http.Response, error) bool) Option {
 	return func(c *Client) {
 		c.MaxRetries = count
@@ -228,21 +604,35 @@ func WithRetries(count int, retryFunc func(*http.Request, *http.Response, error)
 }
 
 // ResponseToJson decodes the response body into the target.
-func ResponseToJson[T any](response *http.Response, target *T) error {
+// 204 and 205 responses and empty bodies fail with ErrNoContent unless the AllowNoContent option is given.
+func ResponseToJson[T any](response *http.Response, target *T, opts ...DecodeOption) error {
 	if response == nil {
-		return fmt.Errorf("response is nil")
+		return ErrNilResponse
 	}
 
 	if response.Body == nil {
-		return fmt.Errorf("response body is nil")
+		return ErrNilBody
 	}
 
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(response.Body)
 
-	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+	cfg := responseDecodeConfig(response, opts)
+
+	if response.StatusCode == http.StatusNoContent || response.StatusCode == http.StatusResetContent {
+		return noContent(cfg, target)
+	}
+
+	if err := cfg.checkContentType("application/json"); err != nil {
+		return err
+	}
+
+	if err := cfg.decode(response.Body, target); err != nil {
+		if errors.Is(err, io.EOF) {
+			return noContent(cfg, target)
+		}
+		return &DecodeError{Content: "response", Err: err}
 	}
 
 	return nil
@@ -384,7 +384,7 @@ func TestClient_ResponseToJson(t *testing.T) {
 					return false
 				}
 
-				return er.Error() == "response is nil"
+				return errors.Is(er, clink.ErrNilResponse)
 			},
 		},
 		{
@@ -399,7 +399,7 @@ func TestClient_ResponseToJson(t *testing.T) {
 					return false
 				}
 
-				return er.Error() == "response body is nil"
+				return errors.Is(er, clink.ErrNilBody)
 			},
 		},
 		{
@@ -415,7 +415,75 @@ func TestClient_ResponseToJson(t *testing.T) {
 					return false
 				}
 
-				return strings.Contains(er.Error(), "failed to decode response")
+				return errors.Is(er, clink.ErrDecode)
+			},
+		},
+		{
+			name: "large integer with use number",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`{"id": 9007199254740993}`)),
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				var t map[string]any
+				er := clink.ResponseToJson(response, &t, clink.UseNumber())
+				if er != nil {
+					return false
+				}
+
+				return t["id"] == json.Number("9007199254740993")
+			},
+		},
+		{
+			name: "unknown fields disallowed",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`{"key": "value", "other": 1}`)),
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				var t struct {
+					Key string `json:"key"`
+				}
+				er := clink.ResponseToJson(response, &t, clink.DisallowUnknownFields())
+
+				return er != nil && strings.Contains(er.Error(), "unknown field")
+			},
+		},
+		{
+			name: "no content response",
+			response: &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       http.NoBody,
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				var t map[string]string
+				er := clink.ResponseToJson(response, &t)
+
+				return errors.Is(er, clink.ErrNoContent)
+			},
+		},
+		{
+			name: "no content response allowed",
+			response: &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       http.NoBody,
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				t := map[string]string{"key": "value"}
+				er := clink.ResponseToJson(response, &t, clink.AllowNoContent())
+
+				return er == nil && t == nil
+			},
+		},
+		{
+			name: "empty body allowed",
+			response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("  ")),
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				var t struct{ Key string }
+				er := clink.ResponseToJson(response, &t, clink.AllowNoContent())
+
+				return er == nil && t.Key == ""
 			},
 		},
 	}
@@ -644,3 +712,151 @@ func TestRequestWithCanceledContext(t *testing.T) {
 		t.Errorf("expected context cancellation error, but got: %v", err)
 	}
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func BenchmarkClient_Do(b *testing.B) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+
+	benchmarks := []struct {
+		name string
+		opts []clink.Option
+		body bool
+	}{
+		{name: "default client", opts: []clink.Option{}},
+		{name: "client with headers", opts: []clink.Option{clink.WithHeaders(map[string]string{"X-One": "1", "X-Two": "2"})}},
+		{name: "client with retries and body", opts: []clink.Option{clink.WithRetries(3, nil)}, body: true},
+		{name: "client with rate limit", opts: []clink.Option{clink.WithRateLimit(1 << 30)}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			opts := append(bm.opts, clink.WithClient(&http.Client{Transport: transport}))
+			c := clink.NewClient(opts...)
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var body io.Reader
+				if bm.body {
+					body = strings.NewReader(`{"key": "value"}`)
+				}
+
+				req, _ := http.NewRequest(http.MethodPost, "http://localhost", body)
+				if _, err := c.Do(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeByStatus(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	type validationErrors struct {
+		Errors map[string]string `json:"errors"`
+	}
+
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		opts     []clink.DecodeOption
+		expected func(t *testing.T, u user, v validationErrors, err error)
+	}{
+		{
+			name:   "success target",
+			status: http.StatusOK,
+			body:   `{"name": "Ada"}`,
+			expected: func(t *testing.T, u user, v validationErrors, err error) {
+				if err != nil || u.Name != "Ada" {
+					t.Errorf("expected the user to be decoded, got %+v and %v", u, err)
+				}
+			},
+		},
+		{
+			name:   "error target",
+			status: http.StatusUnprocessableEntity,
+			body:   `{"errors": {"name": "required"}}`,
+			expected: func(t *testing.T, u user, v validationErrors, err error) {
+				if err != nil || v.Errors["name"] != "required" {
+					t.Errorf("expected the validation errors to be decoded, got %+v and %v", v, err)
+				}
+			},
+		},
+		{
+			name:   "nil target",
+			status: http.StatusAccepted,
+			body:   `ignored`,
+			expected: func(t *testing.T, u user, v validationErrors, err error) {
+				if err != nil {
+					t.Errorf("expected the body to be discarded, got %v", err)
+				}
+			},
+		},
+		{
+			name:   "no content",
+			status: http.StatusNoContent,
+			opts:   []clink.DecodeOption{clink.AllowNoContent()},
+			expected: func(t *testing.T, u user, v validationErrors, err error) {
+				if err != nil {
+					t.Errorf("expected no content to be allowed, got %v", err)
+				}
+			},
+		},
+		{
+			name:   "unregistered status",
+			status: http.StatusInternalServerError,
+			body:   `boom`,
+			expected: func(t *testing.T, u user, v validationErrors, err error) {
+				var statusErr *clink.StatusError
+				if !errors.As(err, &statusErr) || string(statusErr.Body) != "boom" {
+					t.Errorf("expected a status error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithClient(server.Client()))
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			var u user
+			var v validationErrors
+			err = clink.DecodeByStatus(resp, map[int]any{
+				http.StatusOK:                  &u,
+				http.StatusUnprocessableEntity: &v,
+				http.StatusAccepted:            nil,
+				http.StatusNoContent:           &u,
+			}, tc.opts...)
+
+			tc.expected(t, u, v, err)
+		})
+	}
+}
+
+func TestDecodeByStatus_NilBody(t *testing.T) {
+	err := clink.DecodeByStatus(&http.Response{StatusCode: http.StatusInternalServerError}, map[int]any{http.StatusOK: nil})
+
+	var statusErr *clink.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Package clinktest provides helpers to test code using clink clients.
+package clinktest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is a clink.Clock whose time only moves when advanced, so that retries, backoffs, rate limits
+// and cache expiry can be tested without sleeping.
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at   time.Time
+	ch   chan time.Time
+	stop func() bool
+}
+
+// NewClock returns a clock set to start.
+func NewClock(start time.Time) *Clock {
+	c := &Clock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel receiving the time once the clock has been advanced by d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	return c.AfterContext(context.Background(), d)
+}
+
+// AfterContext is like After, but stops waiting once ctx is done, so that Waiters and BlockUntil do not count
+// the waits given up on. Clients using the clock call it when waiting with a context.
+func (c *Clock) AfterContext(ctx context.Context, d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	w := clockWaiter{at: c.now.Add(d), ch: ch}
+	if ctx.Done() != nil {
+		w.stop = context.AfterFunc(ctx, func() { c.remove(ch) })
+	}
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+
+	return ch
+}
+
+// remove forgets the waiter of the channel, if still waiting.
+func (c *Clock) remove(ch chan time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, w := range c.waiters {
+		if w.ch == ch {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Advance moves the clock forward by d, waking up the waiters that are due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	waiting := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiting = append(waiting, w)
+			continue
+		}
+		if w.stop != nil {
+			w.stop()
+		}
+		w.ch <- c.now
+	}
+	c.waiters = waiting
+}
+
+// Waiters returns how many calls to After and AfterContext are waiting for the clock to be advanced.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n calls to After are waiting for the clock to be advanced,
+// so that a test can advance the clock once the code under test started waiting.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
@@ -0,0 +1,118 @@
+package clinktest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clinktest.NewClock(start)
+
+	early := clock.After(time.Second)
+	late := clock.After(time.Minute)
+	if clock.Waiters() != 2 {
+		t.Fatalf("expected 2 waiters, got %d", clock.Waiters())
+	}
+
+	clock.Advance(2 * time.Second)
+	select {
+	case now := <-early:
+		if !now.Equal(start.Add(2 * time.Second)) {
+			t.Errorf("expected the advanced time, got %v", now)
+		}
+	default:
+		t.Fatalf("expected the first waiter to be woken up")
+	}
+
+	select {
+	case <-late:
+		t.Fatalf("expected the second waiter to keep waiting")
+	default:
+	}
+
+	done := make(chan struct{})
+	go func() {
+		<-clock.After(time.Hour)
+		close(done)
+	}()
+	clock.BlockUntil(2)
+	clock.Advance(time.Hour)
+	<-done
+	<-late
+
+	if clock.Waiters() != 0 || !clock.Now().Equal(start.Add(time.Hour+2*time.Second)) {
+		t.Errorf("expected no waiters at the advanced time, got %d at %v", clock.Waiters(), clock.Now())
+	}
+}
+
+func TestClock_AfterContext(t *testing.T) {
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	abandoned := clock.AfterContext(ctx, time.Minute)
+	fired := clock.AfterContext(context.Background(), time.Second)
+	if clock.Waiters() != 2 {
+		t.Fatalf("expected 2 waiters, got %d", clock.Waiters())
+	}
+
+	cancel()
+	if waitForWaiters(clock, 1) != 1 {
+		t.Fatalf("expected the canceled waiter to be removed, got %d waiters", clock.Waiters())
+	}
+
+	clock.Advance(time.Hour)
+	<-fired
+	select {
+	case <-abandoned:
+		t.Error("expected the canceled waiter not to be woken up")
+	default:
+	}
+}
+
+func TestClock_CanceledRetry(t *testing.T) {
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	transport := clinktest.NewTransport()
+	transport.On(http.MethodGet, "api.example.com/*").Respond(clinktest.Reply(http.StatusServiceUnavailable, ""))
+
+	client := clink.NewClient(
+		clink.WithClock(clock),
+		clink.WithRetries(3, func(*http.Request, *http.Response, error) bool { return true }),
+		clink.WithClient(transport.Client()),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/users", nil)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Do(req)
+		done <- err
+	}()
+
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("expected the canceled request to fail")
+	}
+
+	if n := waitForWaiters(clock, 0); n != 0 {
+		t.Errorf("expected the canceled backoff not to be counted, got %d waiters", n)
+	}
+}
+
+// waitForWaiters waits up to a second for the clock to have n waiters, as canceled waiters are removed asynchronously,
+// and returns the number of waiters.
+func waitForWaiters(clock *clinktest.Clock, n int) int {
+	deadline := time.Now().Add(time.Second)
+	for clock.Waiters() != n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	return clock.Waiters()
+}
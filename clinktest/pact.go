@@ -0,0 +1,244 @@
+package clinktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// pactSpecification is the version of the Pact specification written by Pact.
+const pactSpecification = "2.0.0"
+
+// pactIgnoredHeaders are set by the transport rather than by the consumer, and are left out of the contract.
+var pactIgnoredHeaders = map[string]bool{
+	"Accept-Encoding": true,
+	"Content-Length":  true,
+	"Date":            true,
+	"User-Agent":      true,
+}
+
+type interactionKey struct{}
+
+type interaction struct {
+	description   string
+	providerState string
+}
+
+// WithInteraction returns a context describing the interaction of the requests sent with it in the contract,
+// along with the provider state it requires, which may be empty. Requests without description are described
+// by their method and path.
+func WithInteraction(ctx context.Context, description, providerState string) context.Context {
+	return context.WithValue(ctx, interactionKey{}, interaction{description: description, providerState: providerState})
+}
+
+// Pact records the requests of a consumer and the responses it received as a Pact contract with a provider,
+// so that consumer-driven contract tests can be generated from tests using a mock Transport.
+type Pact struct {
+	Consumer string
+	Provider string
+
+	mu           sync.Mutex
+	interactions []pactInteraction
+}
+
+type pactParticipant struct {
+	Name string `json:"name"`
+}
+
+type pactRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type pactResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type pactInteraction struct {
+	Description   string       `json:"description"`
+	ProviderState string       `json:"providerState,omitempty"`
+	Request       pactRequest  `json:"request"`
+	Response      pactResponse `json:"response"`
+}
+
+type pactFile struct {
+	Consumer     pactParticipant   `json:"consumer"`
+	Provider     pactParticipant   `json:"provider"`
+	Interactions []pactInteraction `json:"interactions"`
+	Metadata     map[string]any    `json:"metadata"`
+}
+
+// NewPact returns a contract between the consumer and the provider without interactions.
+func NewPact(consumer, provider string) *Pact {
+	return &Pact{Consumer: consumer, Provider: provider}
+}
+
+// Wrap returns a round tripper recording the interactions sent through next, usually a mock Transport.
+func (p *Pact) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requestBody, err := readBody(&req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		responseBody, err := readBody(&resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		p.record(req, requestBody, resp, responseBody)
+
+		return resp, nil
+	})
+}
+
+// record adds the interaction, unless one with the same description and provider state was already recorded.
+func (p *Pact) record(req *http.Request, requestBody []byte, resp *http.Response, responseBody []byte) {
+	described, ok := req.Context().Value(interactionKey{}).(interaction)
+	if !ok || described.description == "" {
+		described.description = req.Method + " " + req.URL.Path
+	}
+
+	recorded := pactInteraction{
+		Description:   described.description,
+		ProviderState: described.providerState,
+		Request: pactRequest{
+			Method:  req.Method,
+			Path:    req.URL.EscapedPath(),
+			Query:   req.URL.RawQuery,
+			Headers: pactHeaders(req.Header),
+			Body:    pactBody(requestBody),
+		},
+		Response: pactResponse{
+			Status:  resp.StatusCode,
+			Headers: pactHeaders(resp.Header),
+			Body:    pactBody(responseBody),
+		},
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, existing := range p.interactions {
+		if existing.Description == recorded.Description && existing.ProviderState == recorded.ProviderState {
+			return
+		}
+	}
+	p.interactions = append(p.interactions, recorded)
+}
+
+// MarshalJSON encodes the contract in the Pact format.
+func (p *Pact) MarshalJSON() ([]byte, error) {
+	p.mu.Lock()
+	interactions := append([]pactInteraction{}, p.interactions...)
+	p.mu.Unlock()
+
+	return json.Marshal(pactFile{
+		Consumer:     pactParticipant{Name: p.Consumer},
+		Provider:     pactParticipant{Name: p.Provider},
+		Interactions: interactions,
+		Metadata:     map[string]any{"pactSpecification": map[string]string{"version": pactSpecification}},
+	})
+}
+
+// WriteFile writes the contract to dir, in a file named after the consumer and the provider as Pact tools expect,
+// and returns its path.
+func (p *Pact) WriteFile(dir string) (string, error) {
+	data, err := p.MarshalJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pact: %w", err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return "", fmt.Errorf("failed to encode pact: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create pact directory: %w", err)
+	}
+
+	name := filepath.Join(dir, pactFileName(p.Consumer)+"-"+pactFileName(p.Provider)+".json")
+	if err := os.WriteFile(name, append(indented.Bytes(), '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write pact: %w", err)
+	}
+
+	return name, nil
+}
+
+// pactFileName returns the name of a participant as used in pact file names.
+func pactFileName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+}
+
+// pactHeaders returns the headers of the contract, with multiple values joined by commas.
+func pactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string)
+	for key, values := range header {
+		if pactIgnoredHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	return headers
+}
+
+// pactBody returns the body of the contract: JSON bodies are embedded as is and other bodies as strings.
+func pactBody(body []byte) json.RawMessage {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if json.Valid(body) {
+		return body
+	}
+
+	encoded, _ := json.Marshal(string(body))
+
+	return encoded
+}
+
+// readBody reads the body and replaces it with a copy, so that it can still be read.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+	_ = (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,118 @@
+package clinktest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestPact(t *testing.T) {
+	transport := clinktest.NewTransport()
+	transport.On(http.MethodGet, "/users/1").Respond(clinktest.ReplyJSON(http.StatusOK, map[string]any{"id": 1, "name": "alice"}))
+	transport.On(http.MethodPost, "/users").Respond(clinktest.Reply(http.StatusCreated, "created").WithHeader("Content-Type", "text/plain"))
+
+	pact := clinktest.NewPact("Web App", "Users API")
+	c := clink.NewClient(
+		clink.WithHeader("Accept", "application/json"),
+		clink.WithClient(&http.Client{Transport: pact.Wrap(transport)}),
+	)
+
+	ctx := clinktest.WithInteraction(context.Background(), "a request for user 1", "user 1 exists")
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/users/1?fields=name", nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+
+		var user map[string]any
+		if err := clink.ResponseToJson(resp, &user); err != nil || user["name"] != "alice" {
+			t.Fatalf("expected the response to stay readable, got %v %v", user, err)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/users", nil)
+	resp, err := c.Do(clink.WithRequestOptions(req, clink.JSONBody(map[string]string{"name": "bob"})))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	path, err := pact.WriteFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to write pact: %v", err)
+	}
+	if filepath.Base(path) != "web_app-users_api.json" {
+		t.Errorf("unexpected pact file name %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read pact: %v", err)
+	}
+
+	var contract struct {
+		Consumer     struct{ Name string }
+		Provider     struct{ Name string }
+		Interactions []struct {
+			Description   string
+			ProviderState string
+			Request       struct {
+				Method  string
+				Path    string
+				Query   string
+				Headers map[string]string
+				Body    any
+			}
+			Response struct {
+				Status  int
+				Headers map[string]string
+				Body    any
+			}
+		}
+		Metadata struct {
+			PactSpecification struct{ Version string }
+		}
+	}
+	if err := json.Unmarshal(data, &contract); err != nil {
+		t.Fatalf("failed to decode pact: %v", err)
+	}
+
+	if contract.Consumer.Name != "Web App" || contract.Provider.Name != "Users API" || contract.Metadata.PactSpecification.Version != "2.0.0" {
+		t.Errorf("unexpected pact participants or metadata: %s", data)
+	}
+	if len(contract.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions with duplicates removed, got %d", len(contract.Interactions))
+	}
+
+	get := contract.Interactions[0]
+	if get.Description != "a request for user 1" || get.ProviderState != "user 1 exists" {
+		t.Errorf("unexpected description %q and state %q", get.Description, get.ProviderState)
+	}
+	if get.Request.Method != http.MethodGet || get.Request.Path != "/users/1" || get.Request.Query != "fields=name" {
+		t.Errorf("unexpected request %+v", get.Request)
+	}
+	if get.Request.Headers["Accept"] != "application/json" || get.Request.Headers["User-Agent"] != "" {
+		t.Errorf("unexpected request headers %v", get.Request.Headers)
+	}
+	if body, _ := get.Response.Body.(map[string]any); get.Response.Status != http.StatusOK || body["name"] != "alice" {
+		t.Errorf("unexpected response %+v", get.Response)
+	}
+
+	post := contract.Interactions[1]
+	if post.Description != "POST /users" {
+		t.Errorf("expected the default description, got %q", post.Description)
+	}
+	if body, _ := post.Request.Body.(map[string]any); body["name"] != "bob" {
+		t.Errorf("expected the JSON request body, got %v", post.Request.Body)
+	}
+	if post.Response.Status != http.StatusCreated || post.Response.Body != "created" {
+		t.Errorf("expected the text response body as a string, got %+v", post.Response)
+	}
+}
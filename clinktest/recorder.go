@@ -0,0 +1,287 @@
+package clinktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden write the golden files instead of comparing them.
+const UpdateGoldenEnv = "CLINKTEST_UPDATE_GOLDEN"
+
+// RecordedRequest is a copy of a request received by a Recorder.
+type RecordedRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+}
+
+// Recorder records the requests it receives so that tests can make assertions on them.
+type Recorder struct {
+	mu       sync.Mutex
+	requests []*RecordedRequest
+}
+
+// NewServer starts a server recording the requests it receives before passing them to handler, which may be nil
+// to answer 200 OK with an empty body. The server is closed when the test ends.
+func NewServer(t testing.TB, handler http.Handler) (*httptest.Server, *Recorder) {
+	t.Helper()
+
+	recorder := &Recorder{}
+	server := httptest.NewServer(recorder.Handler(handler))
+	t.Cleanup(server.Close)
+
+	return server, recorder
+}
+
+// Record records a copy of the request, leaving its body readable.
+func (r *Recorder) Record(req *http.Request) (*RecordedRequest, error) {
+	recorded := &RecordedRequest{Method: req.Method, URL: req.URL, Header: req.Header.Clone()}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		recorded.Body = body
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, recorded)
+	r.mu.Unlock()
+
+	return recorded, nil
+}
+
+// Handler returns a handler recording the requests before passing them to next, which may be nil.
+func (r *Recorder) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, err := r.Record(req); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if next != nil {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// Requests returns the recorded requests, in the order they were received.
+func (r *Recorder) Requests() []*RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*RecordedRequest(nil), r.requests...)
+}
+
+// Last returns the last recorded request, failing the test if there is none.
+func (r *Recorder) Last(t testing.TB) *RecordedRequest {
+	t.Helper()
+
+	requests := r.Requests()
+	if len(requests) == 0 {
+		t.Fatalf("expected a request, got none")
+		return nil
+	}
+
+	return requests[len(requests)-1]
+}
+
+// AssertCalledTimes fails the test unless exactly n requests were recorded.
+func (r *Recorder) AssertCalledTimes(t testing.TB, n int) {
+	t.Helper()
+
+	if got := len(r.Requests()); got != n {
+		t.Errorf("expected %d requests, got %d", n, got)
+	}
+}
+
+// AssertHeader fails the test unless the request has the header with the given value.
+func (r *RecordedRequest) AssertHeader(t testing.TB, key, value string) {
+	t.Helper()
+
+	if values, ok := r.Header[http.CanonicalHeaderKey(key)]; !ok {
+		t.Errorf("expected header %s to be %q, got none", key, value)
+	} else if got := r.Header.Get(key); got != value {
+		t.Errorf("expected header %s to be %q, got %q", key, value, strings.Join(values, ", "))
+	}
+}
+
+// JSONMatcher checks a decoded JSON body, returning an error describing the mismatch.
+type JSONMatcher func(body any) error
+
+// JSONEquals matches bodies equal to the JSON encoding of v, whatever the order of their keys.
+func JSONEquals(v any) JSONMatcher {
+	return func(body any) error {
+		want, err := normalizeJSON(v)
+		if err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(body, want) {
+			got, _ := json.Marshal(body)
+			expected, _ := json.Marshal(want)
+			return fmt.Errorf("expected JSON body %s, got %s", expected, got)
+		}
+
+		return nil
+	}
+}
+
+// JSONHas matches bodies whose value at the dot separated path, such as "items.0.id", equals the JSON encoding of v.
+func JSONHas(path string, v any) JSONMatcher {
+	return func(body any) error {
+		want, err := normalizeJSON(v)
+		if err != nil {
+			return err
+		}
+
+		got, ok := jsonPath(body, path)
+		if !ok {
+			return fmt.Errorf("expected JSON body to have %s", path)
+		}
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("expected %s to be %v, got %v", path, want, got)
+		}
+
+		return nil
+	}
+}
+
+// AssertJSONBody fails the test unless the request body is JSON accepted by every matcher.
+func (r *RecordedRequest) AssertJSONBody(t testing.TB, matchers ...JSONMatcher) {
+	t.Helper()
+
+	var body any
+	if err := json.Unmarshal(r.Body, &body); err != nil {
+		t.Errorf("expected a JSON body, got %q: %v", r.Body, err)
+		return
+	}
+
+	for _, matcher := range matchers {
+		if err := matcher(body); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// AssertGolden fails the test unless the request matches the golden file at path, which holds its method, URL,
+// sorted headers and body, with JSON bodies indented. The headers given in ignore, such as "User-Agent", are left out.
+// When the CLINKTEST_UPDATE_GOLDEN environment variable is set, the golden file is written instead.
+func (r *RecordedRequest) AssertGolden(t testing.TB, path string, ignore ...string) {
+	t.Helper()
+
+	got := r.golden(ignore)
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file, set %s=1 to create it: %v", UpdateGoldenEnv, err)
+		return
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("request does not match golden file %s\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
+
+// golden returns the golden file representation of the request.
+func (r *RecordedRequest) golden(ignore []string) []byte {
+	skip := map[string]bool{"Accept-Encoding": true, "Content-Length": true}
+	for _, key := range ignore {
+		skip[http.CanonicalHeaderKey(key)] = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\n", r.Method, r.URL.RequestURI())
+
+	keys := make([]string, 0, len(r.Header))
+	for key := range r.Header {
+		if !skip[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range r.Header[key] {
+			fmt.Fprintf(&buf, "%s: %s\n", key, value)
+		}
+	}
+
+	if len(r.Body) > 0 {
+		buf.WriteString("\n")
+		var indented bytes.Buffer
+		if json.Indent(&indented, r.Body, "", "  ") == nil {
+			buf.Write(indented.Bytes())
+			buf.WriteString("\n")
+		} else {
+			buf.Write(r.Body)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+// normalizeJSON returns v as decoded from its JSON encoding, so that it compares equal to decoded bodies.
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode expected JSON: %w", err)
+	}
+
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// jsonPath returns the value at the dot separated path of the decoded JSON document.
+func jsonPath(doc any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := doc.(type) {
+		case map[string]any:
+			value, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			doc = value
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			doc = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return doc, true
+}
@@ -0,0 +1,74 @@
+package clinktest_test
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+// fakeT records the failures of assertions expected to fail.
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Error(args ...any) {
+	f.failures = append(f.failures, fmt.Sprint(args...))
+}
+
+func TestRecorder(t *testing.T) {
+	server, recorder := clinktest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	c := clink.NewClient(clink.WithHeader("X-Tenant", "acme"), clink.WithClient(server.Client()))
+	resp, err := c.Post(server.URL+"/users?notify=true", strings.NewReader(`{"name":"alice","roles":["admin"]}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected the handler to answer, got %d", resp.StatusCode)
+	}
+
+	recorder.AssertCalledTimes(t, 1)
+	req := recorder.Last(t)
+	req.AssertHeader(t, "X-Tenant", "acme")
+	req.AssertJSONBody(t,
+		clinktest.JSONEquals(map[string]any{"roles": []string{"admin"}, "name": "alice"}),
+		clinktest.JSONHas("roles.0", "admin"),
+	)
+	req.AssertGolden(t, filepath.Join("testdata", "create_user.golden"), "User-Agent")
+
+	tests := []struct {
+		name   string
+		assert func(tb testing.TB)
+	}{
+		{name: "called times", assert: func(tb testing.TB) { recorder.AssertCalledTimes(tb, 2) }},
+		{name: "header value", assert: func(tb testing.TB) { req.AssertHeader(tb, "X-Tenant", "other") }},
+		{name: "missing header", assert: func(tb testing.TB) { req.AssertHeader(tb, "X-Missing", "") }},
+		{name: "json equals", assert: func(tb testing.TB) { req.AssertJSONBody(tb, clinktest.JSONEquals(map[string]any{"name": "bob"})) }},
+		{name: "json path", assert: func(tb testing.TB) { req.AssertJSONBody(tb, clinktest.JSONHas("roles.1", "admin")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeT{TB: t}
+			tt.assert(fake)
+			if len(fake.failures) != 1 {
+				t.Errorf("expected the assertion to fail once, got %v", fake.failures)
+			}
+		})
+	}
+}
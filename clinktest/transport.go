@@ -0,0 +1,307 @@
+package clinktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+// Response is a scripted response of a mock Transport.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Err, when set, is returned by the transport instead of a response, to simulate network errors.
+	Err error
+}
+
+// Reply returns a response with the given status code and body.
+func Reply(status int, body string) Response {
+	return Response{Status: status, Header: make(http.Header), Body: []byte(body)}
+}
+
+// ReplyJSON returns a response with the given status code and the JSON encoding of v as body.
+func ReplyJSON(status int, v any) Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("clinktest: failed to encode JSON reply: %v", err))
+	}
+
+	resp := Response{Status: status, Header: make(http.Header), Body: body}
+	resp.Header.Set("Content-Type", "application/json")
+
+	return resp
+}
+
+// Fail returns a response failing with err, such as a connection reset.
+func Fail(err error) Response {
+	return Response{Err: err}
+}
+
+// WithHeader returns a copy of the response with the header set.
+func (r Response) WithHeader(key, value string) Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(key, value)
+	r.Header = header
+
+	return r
+}
+
+// WithRetryAfter returns a copy of the response with a Retry-After header of d, rounded to seconds.
+func (r Response) WithRetryAfter(d time.Duration) Response {
+	return r.WithHeader("Retry-After", strconv.Itoa(int(d.Round(time.Second)/time.Second)))
+}
+
+// Route answers the requests matching its method and pattern with its scripted responses, in order.
+type Route struct {
+	method    string
+	pattern   string
+	mu        sync.Mutex
+	responses []Response
+	calls     int
+	latency   time.Duration
+	bandwidth int
+}
+
+// Respond appends responses to the script of the route. Each request gets the next response,
+// and the last one is repeated once the script is exhausted.
+func (r *Route) Respond(responses ...Response) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.responses = append(r.responses, responses...)
+
+	return r
+}
+
+// Latency delays every response of the route by d, as the time to first byte of a remote server.
+// Requests whose context is done while waiting fail with the context error.
+func (r *Route) Latency(d time.Duration) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latency = d
+
+	return r
+}
+
+// Bandwidth limits the rate at which response bodies of the route are read to bytesPerSecond.
+func (r *Route) Bandwidth(bytesPerSecond int) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bandwidth = bytesPerSecond
+
+	return r
+}
+
+// Calls returns how many requests the route answered.
+func (r *Route) Calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.calls
+}
+
+// matches reports whether the route answers the request. Patterns starting with "/" match the path of the URL,
+// with the syntax of path.Match, and other patterns are matched with clink.MatchURL.
+func (r *Route) matches(req *http.Request) bool {
+	if r.method != "" && !strings.EqualFold(r.method, req.Method) {
+		return false
+	}
+
+	if strings.HasPrefix(r.pattern, "/") {
+		matched, _ := path.Match(r.pattern, req.URL.Path)
+		return matched
+	}
+
+	return clink.MatchURL(r.pattern, req.URL)
+}
+
+// next returns the next scripted response, with the latency and bandwidth of the route.
+func (r *Route) next() (Response, time.Duration, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	if len(r.responses) == 0 {
+		return Reply(http.StatusOK, ""), r.latency, r.bandwidth
+	}
+
+	i := min(r.calls, len(r.responses)) - 1
+
+	return r.responses[i], r.latency, r.bandwidth
+}
+
+// Transport is an http.RoundTripper answering requests with the scripted responses of its routes, without network.
+// It records the requests it receives, so that the Recorder assertions can be used on it.
+type Transport struct {
+	Recorder
+
+	// Clock is used to simulate latency and bandwidth, defaulting to clink.SystemClock.
+	// Using a clinktest.Clock makes slow routes instantaneous in tests.
+	Clock clink.Clock
+
+	mu     sync.Mutex
+	routes []*Route
+}
+
+// NewTransport returns a transport without routes.
+func NewTransport() *Transport {
+	return &Transport{}
+}
+
+// On adds a route answering the requests with the given method, or any method when empty, whose URL matches the pattern,
+// such as "/users/*" or "api.example.com/v1/*". Routes are matched in the order they were added.
+func (t *Transport) On(method, pattern string) *Route {
+	route := &Route{method: method, pattern: pattern}
+
+	t.mu.Lock()
+	t.routes = append(t.routes, route)
+	t.mu.Unlock()
+
+	return route
+}
+
+// Client returns an http client using the transport, to be passed to clink.WithClient.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Do implements clink.Doer, answering the request without a client, so that the transport can stand in for
+// a *clink.Client in code depending on clink.Doer.
+func (t *Transport) Do(req *http.Request) (*http.Response, error) {
+	return t.RoundTrip(req)
+}
+
+// RoundTrip implements http.RoundTripper. Requests matching no route fail.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, err := t.Record(req); err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	var route *Route
+	for _, r := range t.routes {
+		if r.matches(req) {
+			route = r
+			break
+		}
+	}
+	t.mu.Unlock()
+
+	if route == nil {
+		return nil, fmt.Errorf("clinktest: no route for %s %s", req.Method, req.URL)
+	}
+
+	scripted, latency, bandwidth := route.next()
+	if err := wait(req.Context(), t.clock(), latency); err != nil {
+		return nil, err
+	}
+	if scripted.Err != nil {
+		return nil, scripted.Err
+	}
+
+	resp := scripted.response(req)
+	if bandwidth > 0 {
+		resp.Body = &throttledBody{
+			ReadCloser: resp.Body,
+			ctx:        req.Context(),
+			clock:      t.clock(),
+			bandwidth:  bandwidth,
+		}
+	}
+
+	return resp, nil
+}
+
+// clock returns the clock of the transport.
+func (t *Transport) clock() clink.Clock {
+	if t.Clock == nil {
+		return clink.SystemClock
+	}
+
+	return t.Clock
+}
+
+// wait waits for d on the clock or until the context is done.
+func wait(ctx context.Context, clock clink.Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	after := clock.After
+	if c, ok := clock.(*Clock); ok {
+		after = func(d time.Duration) <-chan time.Time { return c.AfterContext(ctx, d) }
+	}
+
+	select {
+	case <-after(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledBody is a response body read at a limited rate.
+type throttledBody struct {
+	io.ReadCloser
+	ctx       context.Context
+	clock     clink.Clock
+	bandwidth int
+}
+
+// Read reads at most a tenth of a second worth of bandwidth, waiting for the time it takes to transfer it.
+func (b *throttledBody) Read(p []byte) (int, error) {
+	chunk := max(b.bandwidth/10, 1)
+	if len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := wait(b.ctx, b.clock, time.Duration(n)*time.Second/time.Duration(b.bandwidth)); werr != nil {
+			return 0, werr
+		}
+	}
+
+	return n, err
+}
+
+// response returns the http response for the request.
+func (r Response) response(req *http.Request) *http.Response {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,171 @@
+package clinktest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestTransport_Script(t *testing.T) {
+	transport := clinktest.NewTransport()
+	users := transport.On(http.MethodGet, "/users").Respond(
+		clinktest.Reply(http.StatusInternalServerError, ""),
+		clinktest.Reply(http.StatusTooManyRequests, "").WithRetryAfter(time.Second),
+		clinktest.Fail(syscall.ECONNRESET),
+		clinktest.ReplyJSON(http.StatusOK, []map[string]string{{"name": "alice"}}),
+	)
+	transport.On("", "api.example.com/health").Respond(clinktest.Reply(http.StatusNoContent, ""))
+
+	var delays []time.Duration
+	c := clink.NewClient(
+		clink.WithRetryPolicy(clink.RetryPolicy{
+			MaxRetries:  3,
+			ShouldRetry: clink.RetryOnStatus(http.StatusTooManyRequests, http.StatusInternalServerError),
+			Backoff: func(attempt int, resp *http.Response) time.Duration {
+				delay, _ := clink.RetryAfter(resp)
+				delays = append(delays, delay)
+				return 0
+			},
+		}),
+		clink.WithClient(transport.Client()),
+	)
+
+	resp, err := c.Get("https://api.example.com/users")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	var got []map[string]string
+	if err := clink.ResponseToJson(resp, &got); err != nil || len(got) != 1 || got[0]["name"] != "alice" {
+		t.Errorf("expected the final scripted response, got %v %v", got, err)
+	}
+
+	if users.Calls() != 4 {
+		t.Errorf("expected 4 calls, got %d", users.Calls())
+	}
+	if len(delays) != 3 || delays[1] != time.Second {
+		t.Errorf("expected the Retry-After of the 429 to be seen by the backoff, got %v", delays)
+	}
+	transport.AssertCalledTimes(t, 4)
+
+	resp, err = c.Get("https://api.example.com/users")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || users.Calls() != 5 {
+		t.Errorf("expected the last response to repeat, got %d after %d calls", resp.StatusCode, users.Calls())
+	}
+
+	resp, err = c.Get("https://api.example.com/health")
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected the URL pattern route to answer, got %v", err)
+	}
+
+	if _, err := c.Get("https://api.example.com/missing"); err == nil || errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("expected requests without route to fail, got %v", err)
+	}
+}
+
+func TestTransport_Latency(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		{name: "response within timeout", latency: 10 * time.Millisecond, timeout: time.Second},
+		{name: "response after timeout", latency: time.Second, timeout: 20 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := clinktest.NewTransport()
+			transport.On("", "/*").Latency(tt.latency)
+
+			c := clink.NewClient(clink.WithClient(transport.Client()))
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.com/slow", nil)
+			resp, err := c.Do(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		})
+	}
+}
+
+func TestTransport_LatencyWithClock(t *testing.T) {
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	transport := clinktest.NewTransport()
+	transport.Clock = clock
+	transport.On("", "/*").Latency(time.Hour)
+
+	c := clink.NewClient(clink.WithClient(transport.Client()))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Get("https://api.example.com/slow")
+		done <- err
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	if err := <-done; err != nil {
+		t.Errorf("expected the response after advancing the clock, got %v", err)
+	}
+}
+
+func TestTransport_Bandwidth(t *testing.T) {
+	transport := clinktest.NewTransport()
+	transport.On("", "/*").Bandwidth(2000).Respond(clinktest.Reply(http.StatusOK, strings.Repeat("x", 200)))
+
+	c := clink.NewClient(clink.WithClient(transport.Client()))
+
+	start := time.Now()
+	resp, err := c.Get("https://api.example.com/download")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || len(body) != 200 {
+		t.Fatalf("expected the full body, got %d bytes: %v", len(body), err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected reading 200 bytes at 2000 B/s to take 100ms, took %v", elapsed)
+	}
+}
+
+func TestTransport_Do(t *testing.T) {
+	transport := clinktest.NewTransport()
+	transport.On(http.MethodGet, "/users/1").Respond(clinktest.Reply(http.StatusOK, "alice"))
+
+	var doer clink.Doer = transport
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/users/1", nil)
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "alice" {
+		t.Errorf("expected alice, got: %s", body)
+	}
+}
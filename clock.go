@@ -0,0 +1,104 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Clock is the source of time of the client, used to wait between retries, space rate limited requests,
+// compute the age of cached responses and roll quotas. It can be replaced in tests to advance time synthetically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After sends the current time on the returned channel once the duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the clock reading the system time, used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements Clock.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithClock sets the clock of the client. Limiters created with NewLimiter and set with WithLimiter use it too.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.timeSource = clock
+	}
+}
+
+type clockKey struct{}
+
+// requestClock returns the clock of the client that sent the request, or SystemClock.
+func requestClock(req *http.Request) Clock {
+	if clock, ok := requestValue[Clock](req, clockKey{}); ok {
+		return clock
+	}
+
+	return SystemClock
+}
+
+// contextClock is implemented by clocks that stop waiting once a context is done, such as the clinktest clock,
+// so that it does not count the waits given up on.
+type contextClock interface {
+	AfterContext(ctx context.Context, d time.Duration) <-chan time.Time
+}
+
+// clockAfter returns clock.After(d), letting clocks implementing contextClock stop waiting once ctx is done.
+func clockAfter(ctx context.Context, clock Clock, d time.Duration) <-chan time.Time {
+	if clock, ok := clock.(contextClock); ok {
+		return clock.AfterContext(ctx, d)
+	}
+
+	return clock.After(d)
+}
+
+// clockSetter is implemented by limiters that can use the clock of the client.
+type clockSetter interface {
+	setClock(Clock)
+}
+
+// clock returns the clock of the client.
+func (c *Client) clock() Clock {
+	if c.timeSource == nil {
+		return SystemClock
+	}
+
+	return c.timeSource
+}
+
+// sleepContext waits for d on the clock or until the context is done.
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	if clock == SystemClock {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case <-clockAfter(ctx, clock, d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestClient_ClockRetries(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Now())
+	c := clink.NewClient(
+		clink.WithClock(clock),
+		clink.WithRetryPolicy(clink.RetryPolicy{
+			MaxRetries:  2,
+			ShouldRetry: clink.RetryOnStatus(http.StatusServiceUnavailable),
+			Backoff:     func(int, *http.Response) time.Duration { return time.Hour },
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Errorf("request failed: %v", err)
+		}
+		done <- resp
+	}()
+
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+	}
+
+	if resp := <-done; resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the request to succeed after two synthetic backoffs")
+	}
+}
+
+func TestClient_ClockRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Now())
+	c := clink.NewClient(
+		clink.WithClock(clock),
+		clink.WithLimiter(clink.NewLimiter(clink.FixedWindow, 1, time.Minute, 0)),
+		clink.WithClient(server.Client()),
+	)
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	done := make(chan error)
+	go func() {
+		resp, err := c.Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	clock.BlockUntil(1)
+	select {
+	case <-done:
+		t.Fatalf("expected the second request to wait for the next window")
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	if err := <-done; err != nil {
+		t.Errorf("request failed: %v", err)
+	}
+}
+
+func TestClient_ClockCache(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+	}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Now())
+	c := clink.NewClient(
+		clink.WithClock(clock),
+		clink.WithCache(clink.NewMemoryCache()),
+		clink.WithClient(server.Client()),
+	)
+
+	for _, advance := range []time.Duration{0, 30 * time.Second, 31 * time.Second} {
+		clock.Advance(advance)
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if hits.Load() != 2 {
+		t.Errorf("expected the cached response to expire after 60 synthetic seconds, got %d hits", hits.Load())
+	}
+}
+
+func TestClient_ClockRetryAfter(t *testing.T) {
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", clock.Now().Add(time.Minute).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClock(clock), clink.WithClient(server.Client()))
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if delay, ok := clink.RetryAfter(resp); !ok || delay != time.Minute {
+		t.Errorf("expected a delay of 1m on the client clock, got %v", delay)
+	}
+	if delay, ok := clink.RetryAfterAt(resp, clock.Now().Add(30*time.Second)); !ok || delay != 30*time.Second {
+		t.Errorf("expected a delay of 30s, got %v", delay)
+	}
+}
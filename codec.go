@@ -0,0 +1,54 @@
+package clink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type jsonCodec struct {
+	marshal   func(any) ([]byte, error)
+	unmarshal func([]byte, any) error
+}
+
+type jsonCodecKey struct{}
+
+// WithJSONCodec sets the functions used to encode JSON request bodies and decode JSON responses,
+// so that libraries such as jsoniter, go-json or sonic can replace encoding/json.
+// Responses returned by the client are decoded with unmarshal by ResponseToJson; UseNumber and DisallowUnknownFields do not apply to it.
+func WithJSONCodec(marshal func(any) ([]byte, error), unmarshal func([]byte, any) error) Option {
+	return func(c *Client) {
+		c.jsonCodec = &jsonCodec{marshal: marshal, unmarshal: unmarshal}
+	}
+}
+
+// JSONBody sets the body of the request to the JSON encoding of v, using the client's JSON codec.
+// The Content-Type header is set to application/json unless the request already has one.
+func JSONBody(v any) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.body = func(c *Client, req *http.Request) error {
+			return c.setJSONBody(req, v, "application/json")
+		}
+	}
+}
+
+// setJSONBody encodes v with the client's codec and sets it as the request body.
+func (c *Client) setJSONBody(req *http.Request, v any, contentType string) error {
+	data, err := c.marshalJSON(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	setBody(req, data, contentType)
+
+	return nil
+}
+
+// marshalJSON encodes v with the client's codec.
+func (c *Client) marshalJSON(v any) ([]byte, error) {
+	if c.jsonCodec != nil && c.jsonCodec.marshal != nil {
+		return c.jsonCodec.marshal(v)
+	}
+
+	return json.Marshal(v)
+}
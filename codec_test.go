@@ -0,0 +1,72 @@
+package clink_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestJSONCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	var marshalled, unmarshalled int
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithJSONCodec(
+			func(v any) ([]byte, error) {
+				marshalled++
+				return json.Marshal(v)
+			},
+			func(data []byte, v any) error {
+				unmarshalled++
+				return json.Unmarshal(data, v)
+			},
+		),
+	)
+
+	resp, err := client.Post(server.URL, nil, clink.JSONBody(map[string]string{"key": "value"}))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if resp.Header.Get("X-Content-Type") != "application/json" {
+		t.Errorf("expected json content type, got: %s", resp.Header.Get("X-Content-Type"))
+	}
+
+	var target map[string]string
+	if err := clink.ResponseToJson(resp, &target); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if target["key"] != "value" || marshalled != 1 || unmarshalled != 1 {
+		t.Errorf("expected codec to be used, got target %v, marshalled %d, unmarshalled %d", target, marshalled, unmarshalled)
+	}
+}
+
+func TestJSONBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := client.Put(server.URL, nil, clink.JSONBody([]int{1, 2, 3}))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "[1,2,3]" {
+		t.Errorf("expected encoded body, got: %s", body)
+	}
+}
@@ -0,0 +1,219 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ConcurrencyAlgorithm selects how the limit of WithAdaptiveConcurrency adapts to the upstream.
+type ConcurrencyAlgorithm int
+
+const (
+	// AIMD grows the limit by one per limit successful requests, and cuts it by 10% on overload:
+	// network errors, 429, 503 and 504 responses.
+	AIMD ConcurrencyAlgorithm = iota
+	// Gradient sizes the limit from the ratio of the long term average latency to the latest one, shrinking it
+	// as latency rises above its average, and cuts it by 10% on overload like AIMD.
+	Gradient
+)
+
+// AdaptiveConcurrency configures WithAdaptiveConcurrency.
+type AdaptiveConcurrency struct {
+	Algorithm ConcurrencyAlgorithm
+	// InitialLimit is the number of requests in flight allowed at first. Defaults to 20.
+	InitialLimit int
+	// MinLimit is the lowest the limit goes. Defaults to 1.
+	MinLimit int
+	// MaxLimit is the highest the limit goes. Defaults to 200.
+	MaxLimit int
+}
+
+// WithAdaptiveConcurrency limits the number of request attempts in flight, adjusting the limit to the latency and
+// errors of the responses with the algorithm of the config, so that a struggling upstream gets fewer concurrent
+// requests. The limit only grows while at least half of it is in use. Attempts over the limit wait for one in flight
+// to get its response, or for their context to be done, and low priority ones are shed, see WithPriority.
+func WithAdaptiveConcurrency(config AdaptiveConcurrency) Option {
+	return func(c *Client) {
+		if config.MinLimit <= 0 {
+			config.MinLimit = 1
+		}
+		if config.MaxLimit <= 0 {
+			config.MaxLimit = 200
+		}
+		if config.MaxLimit < config.MinLimit {
+			config.MaxLimit = config.MinLimit
+		}
+		if config.InitialLimit <= 0 {
+			config.InitialLimit = 20
+		}
+		config.InitialLimit = min(max(config.InitialLimit, config.MinLimit), config.MaxLimit)
+
+		c.concurrency = &concurrencyLimiter{config: config, limit: float64(config.InitialLimit)}
+	}
+}
+
+// ConcurrencyLimit returns the current limit set with WithAdaptiveConcurrency and the number of attempts in flight,
+// or zeros when concurrency is not limited.
+func (c *Client) ConcurrencyLimit() (limit, inFlight int) {
+	if c.concurrency == nil {
+		return 0, 0
+	}
+
+	c.concurrency.mu.Lock()
+	defer c.concurrency.mu.Unlock()
+
+	return int(c.concurrency.limit), c.concurrency.inFlight
+}
+
+const (
+	// concurrencyBackoff is the factor the limit is multiplied by on overload.
+	concurrencyBackoff = 0.9
+	// concurrencySmoothing is the weight of each new limit computed by the Gradient algorithm.
+	concurrencySmoothing = 0.2
+	// concurrencyLongRTTWeight is the weight of each latency sample in the long term average of the Gradient algorithm.
+	concurrencyLongRTTWeight = 0.05
+)
+
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	config   AdaptiveConcurrency
+	limit    float64
+	inFlight int
+	waiters  []concurrencyWaiter
+	longRTT  float64
+}
+
+type concurrencyWaiter struct {
+	ready    chan struct{}
+	priority Priority
+}
+
+// acquire waits until the attempt may be sent, or the context is done. Attempts wait in order of priority, except
+// low priority ones which are shed when no slot is free.
+func (l *concurrencyLimiter) acquire(ctx context.Context, priority Priority) error {
+	l.mu.Lock()
+	if l.inFlight < int(l.limit) && len(l.waiters) == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+		return nil
+	}
+
+	if priority < PriorityNormal {
+		l.mu.Unlock()
+		return ErrLoadShed
+	}
+
+	ready := make(chan struct{})
+	position := len(l.waiters)
+	for position > 0 && l.waiters[position-1].priority < priority {
+		position--
+	}
+	l.waiters = append(l.waiters, concurrencyWaiter{})
+	copy(l.waiters[position+1:], l.waiters[position:])
+	l.waiters[position] = concurrencyWaiter{ready: ready, priority: priority}
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i, waiter := range l.waiters {
+			if waiter.ready == ready {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				return ctx.Err()
+			}
+		}
+
+		// The slot was granted while the context was done, hand it over.
+		l.inFlight--
+		l.admit()
+		return ctx.Err()
+	}
+}
+
+// release records the outcome of an attempt, adjusts the limit and admits waiting attempts.
+func (l *concurrencyLimiter) release(resp *http.Response, err error, rtt time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	used := l.inFlight
+	l.inFlight--
+
+	switch {
+	case errors.Is(err, context.Canceled):
+	case isOverload(resp, err):
+		l.limit *= concurrencyBackoff
+	case float64(used*2) < l.limit:
+		// The limit is not what holds requests back, there is nothing to learn about it.
+	case l.config.Algorithm == Gradient:
+		sample := float64(rtt)
+		if l.longRTT == 0 {
+			l.longRTT = sample
+		}
+		l.longRTT += (sample - l.longRTT) * concurrencyLongRTTWeight
+
+		gradient := 1.0
+		if sample > 0 {
+			gradient = math.Max(0.5, math.Min(1, l.longRTT/sample))
+		}
+		target := l.limit*gradient + math.Sqrt(l.limit)
+		l.limit += (target - l.limit) * concurrencySmoothing
+	default:
+		l.limit += 1 / l.limit
+	}
+	l.limit = math.Max(float64(l.config.MinLimit), math.Min(float64(l.config.MaxLimit), l.limit))
+
+	l.admit()
+}
+
+// admit hands free slots to the waiting attempts, in order.
+func (l *concurrencyLimiter) admit() {
+	for len(l.waiters) > 0 && l.inFlight < int(l.limit) {
+		l.inFlight++
+		close(l.waiters[0].ready)
+		l.waiters = l.waiters[1:]
+	}
+}
+
+// isOverload reports whether the outcome of an attempt shows the upstream is overloaded.
+func isOverload(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// acquireConcurrency waits for the adaptive concurrency limit, if any, and returns the function releasing the slot
+// with the outcome of the attempt.
+func (c *Client) acquireConcurrency(req *http.Request) (func(*http.Response, error), error) {
+	if c.concurrency == nil {
+		return func(*http.Response, error) {}, nil
+	}
+
+	if err := c.concurrency.acquire(req.Context(), requestConfigFrom(req).priority); err != nil {
+		if errors.Is(err, ErrLoadShed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to wait for concurrency limit: %w", err)
+	}
+
+	start := c.clock().Now()
+
+	return func(resp *http.Response, err error) {
+		c.concurrency.release(resp, err, c.clock().Now().Sub(start))
+	}, nil
+}
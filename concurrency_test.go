@@ -0,0 +1,102 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithAdaptiveConcurrency(t *testing.T) {
+	var active, peak atomic.Int32
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-unblock
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithAdaptiveConcurrency(clink.AdaptiveConcurrency{InitialLimit: 2, MaxLimit: 2}),
+		clink.WithClient(server.Client()),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.Get(server.URL); err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+
+	for active.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	if limit, inFlight := client.ConcurrencyLimit(); limit != 2 || inFlight != 2 {
+		t.Errorf("expected limit 2 with 2 in flight, got: %d and %d", limit, inFlight)
+	}
+
+	close(unblock)
+	wg.Wait()
+
+	if peak.Load() != 2 {
+		t.Errorf("expected at most 2 requests in flight, got: %d", peak.Load())
+	}
+}
+
+func TestAdaptiveConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm clink.ConcurrencyAlgorithm
+		initial   int
+		status    int
+		want      int
+	}{
+		{"aimd grows while in use", clink.AIMD, 1, http.StatusOK, 2},
+		{"aimd holds while idle", clink.AIMD, 10, http.StatusOK, 10},
+		{"aimd shrinks on overload", clink.AIMD, 10, http.StatusServiceUnavailable, 3},
+		{"gradient grows while in use", clink.Gradient, 1, http.StatusOK, 2},
+		{"gradient holds while idle", clink.Gradient, 10, http.StatusOK, 10},
+		{"gradient shrinks on overload", clink.Gradient, 10, http.StatusTooManyRequests, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(
+				clink.WithAdaptiveConcurrency(clink.AdaptiveConcurrency{Algorithm: tt.algorithm, InitialLimit: tt.initial}),
+				clink.WithClient(server.Client()),
+			)
+
+			for i := 0; i < 10; i++ {
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatalf("failed to make request: %v", err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			if limit, inFlight := client.ConcurrencyLimit(); limit != tt.want || inFlight != 0 {
+				t.Errorf("expected limit %d with nothing in flight, got: %d with %d in flight", tt.want, limit, inFlight)
+			}
+		})
+	}
+}
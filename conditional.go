@@ -0,0 +1,58 @@
+package clink
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrPreconditionFailed is matched by the *StatusError returned when a conditional request fails with 412 Precondition Failed,
+// meaning the resource was modified since its ETag was read.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// IfMatch only applies the request if the resource still has the given ETag, for optimistic concurrency.
+func IfMatch(etag string) RequestOption {
+	return WithRequestHeader("If-Match", etag)
+}
+
+// IfNoneMatch only applies the request if the resource does not have the given ETag.
+// Use "*" to only create a resource that does not exist yet.
+func IfNoneMatch(etag string) RequestOption {
+	return WithRequestHeader("If-None-Match", etag)
+}
+
+// WithRequestHeader sets a header on the request, taking precedence over the client headers unless
+// ClientHeadersFirst is set.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(cfg *requestConfig) {
+		headers := make(map[string]string, len(cfg.headers)+1)
+		for k, v := range cfg.headers {
+			headers[k] = v
+		}
+		headers[key] = value
+		cfg.headers = headers
+	}
+}
+
+// ETag returns the ETag header of the response.
+func ETag(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+
+	return resp.Header.Get("ETag")
+}
+
+// checkPrecondition returns a *StatusError, closing the response body, when a conditional request fails with 412.
+func checkPrecondition(req *http.Request, resp *http.Response) error {
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		return nil
+	}
+
+	for _, header := range []string{"If-Match", "If-None-Match", "If-Unmodified-Since"} {
+		if req.Header.Get(header) != "" {
+			return newStatusError(req, resp)
+		}
+	}
+
+	return nil
+}
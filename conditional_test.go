@@ -0,0 +1,64 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_IfMatch(t *testing.T) {
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", etag)
+		case http.MethodPut:
+			if r.Header.Get("If-Match") != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			etag = `"v2"`
+			w.Header().Set("ETag", etag)
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	read := clink.ETag(resp)
+	_ = resp.Body.Close()
+
+	testCases := []struct {
+		name string
+		etag string
+		err  error
+	}{
+		{name: "applies the update with the current etag", etag: read},
+		{name: "fails with a stale etag", etag: read, err: clink.ErrPreconditionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := c.Put(server.URL, strings.NewReader("{}"), clink.IfMatch(tc.etag))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			var statusErr *clink.StatusError
+			if tc.err != nil && (!errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusPreconditionFailed) {
+				t.Errorf("expected a 412 status error, got %v", err)
+			}
+		})
+	}
+}
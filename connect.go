@@ -0,0 +1,232 @@
+package clink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ConnectProtocol is the wire protocol used to call a Connect or gRPC-Web unary procedure.
+type ConnectProtocol int
+
+const (
+	// ConnectUnary is the Connect unary protocol, sending the JSON message as the body of a POST request.
+	ConnectUnary ConnectProtocol = iota
+	// GRPCWeb is the gRPC-Web protocol, sending the JSON message in a length-prefixed frame and
+	// receiving the status in a trailers frame at the end of the body.
+	GRPCWeb
+)
+
+const (
+	grpcWebContentType  = "application/grpc-web+json"
+	grpcWebTrailersFlag = 0x80
+	// connectMaxMessage is the size above which gRPC-Web frames are rejected.
+	connectMaxMessage = 32 << 20
+)
+
+// connectCodes are the Connect error codes, indexed by gRPC status code.
+var connectCodes = []string{
+	"ok", "canceled", "unknown", "invalid_argument", "deadline_exceeded", "not_found", "already_exists",
+	"permission_denied", "resource_exhausted", "failed_precondition", "aborted", "out_of_range",
+	"unimplemented", "internal", "unavailable", "data_loss", "unauthenticated",
+}
+
+// ConnectError is returned when decoding a Connect or gRPC-Web response holding an error.
+type ConnectError struct {
+	// Code is the Connect name of the error code, such as "not_found".
+	Code    string
+	Message string
+	// Details holds the raw error details sent with Connect errors.
+	Details []json.RawMessage
+}
+
+// Error implements the error interface.
+func (e *ConnectError) Error() string {
+	if e.Message == "" {
+		return "connect: " + e.Code
+	}
+
+	return fmt.Sprintf("connect: %s: %s", e.Code, e.Message)
+}
+
+// ConnectBody sets the body of the request to the JSON encoding of message, using the client's JSON codec,
+// framed and with the headers of the protocol. Requests must be POST requests to the /package.Service/Method
+// path of the procedure.
+func ConnectBody(protocol ConnectProtocol, message any) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.body = func(c *Client, req *http.Request) error {
+			data, err := c.marshalJSON(message)
+			if err != nil {
+				return fmt.Errorf("failed to encode connect message: %w", err)
+			}
+
+			if protocol == GRPCWeb {
+				frame := make([]byte, 5, 5+len(data))
+				binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+				req.Header.Set("X-Grpc-Web", "1")
+				setBody(req, append(frame, data...), grpcWebContentType)
+				return nil
+			}
+
+			req.Header.Set("Connect-Protocol-Version", "1")
+			setBody(req, data, "application/json")
+
+			return nil
+		}
+	}
+}
+
+// DecodeConnect decodes the message of a Connect unary or gRPC-Web response into the target.
+// The protocol is detected from the Content-Type of the response, and errors are returned as a *ConnectError.
+func DecodeConnect[T any](response *http.Response, target *T) error {
+	if response == nil {
+		return ErrNilResponse
+	}
+
+	if response.Body == nil {
+		return ErrNilBody
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	cfg := responseDecodeConfig(response, nil)
+
+	if strings.HasPrefix(strings.ToLower(cfg.contentType), "application/grpc-web") {
+		return decodeGRPCWeb(cfg, response, target)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return connectError(response)
+	}
+
+	if err := cfg.decode(response.Body, target); err != nil {
+		return &DecodeError{Content: "connect message", Err: err}
+	}
+
+	return nil
+}
+
+// decodeGRPCWeb reads the frames of a gRPC-Web response, decoding the message into the target once the
+// trailers report success. Trailers-only responses carry the status in the headers.
+func decodeGRPCWeb[T any](cfg *decodeConfig, response *http.Response, target *T) error {
+	if response.StatusCode != http.StatusOK {
+		return &ConnectError{Code: connectCodeFromHTTP(response.StatusCode), Message: response.Status}
+	}
+
+	trailers := response.Header
+	var message []byte
+	for {
+		var prefix [5]byte
+		if _, err := io.ReadFull(response.Body, prefix[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("failed to read grpc-web frame: %w", err)
+		}
+
+		size := binary.BigEndian.Uint32(prefix[1:])
+		if size > connectMaxMessage {
+			return fmt.Errorf("grpc-web frame of %d bytes exceeds the limit of %d bytes: %w", size, connectMaxMessage, ErrBodyTooLarge)
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(response.Body, data); err != nil {
+			return fmt.Errorf("failed to read grpc-web frame: %w", err)
+		}
+
+		if prefix[0]&grpcWebTrailersFlag == 0 {
+			message = data
+			continue
+		}
+
+		header, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(append(data, "\r\n"...)))).ReadMIMEHeader()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read grpc-web trailers: %w", err)
+		}
+		trailers = http.Header(header)
+	}
+
+	if err := grpcStatusError(trailers); err != nil {
+		return err
+	}
+
+	if message == nil {
+		return &DecodeError{Content: "connect message", Err: ErrNoContent}
+	}
+
+	if err := cfg.decode(bytes.NewReader(message), target); err != nil {
+		return &DecodeError{Content: "connect message", Err: err}
+	}
+
+	return nil
+}
+
+// grpcStatusError returns the *ConnectError of the grpc-status and grpc-message trailers, or nil on success.
+func grpcStatusError(trailers http.Header) error {
+	value := trailers.Get("Grpc-Status")
+	if value == "" {
+		return &ConnectError{Code: "unknown", Message: "missing grpc-status trailer"}
+	}
+
+	status, err := strconv.Atoi(value)
+	if err != nil || status < 0 || status >= len(connectCodes) {
+		return &ConnectError{Code: "unknown", Message: "invalid grpc-status " + value}
+	}
+
+	if status == 0 {
+		return nil
+	}
+
+	message := trailers.Get("Grpc-Message")
+	if unescaped, err := url.PathUnescape(message); err == nil {
+		message = unescaped
+	}
+
+	return &ConnectError{Code: connectCodes[status], Message: message}
+}
+
+// connectError returns the *ConnectError of a Connect unary error response. Responses without a JSON error
+// get a code derived from their HTTP status.
+func connectError(response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+
+	var envelope struct {
+		Code    string            `json:"code"`
+		Message string            `json:"message"`
+		Details []json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Code == "" {
+		return &ConnectError{Code: connectCodeFromHTTP(response.StatusCode), Message: response.Status}
+	}
+
+	return &ConnectError{Code: envelope.Code, Message: envelope.Message, Details: envelope.Details}
+}
+
+// connectCodeFromHTTP maps an HTTP status to a Connect error code, as specified by the Connect protocol.
+func connectCodeFromHTTP(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "internal"
+	case http.StatusUnauthorized:
+		return "unauthenticated"
+	case http.StatusForbidden:
+		return "permission_denied"
+	case http.StatusNotFound:
+		return "unimplemented"
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,133 @@
+package clink_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+type greetResponse struct {
+	Greeting string `json:"greeting"`
+}
+
+func grpcWebFrame(flag byte, data []byte) []byte {
+	frame := make([]byte, 5, 5+len(data))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+
+	return append(frame, data...)
+}
+
+func TestConnect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.Header.Get("Content-Type") == "application/grpc-web+json" {
+			var req greetRequest
+			if r.Header.Get("X-Grpc-Web") != "1" || len(body) < 5 || json.Unmarshal(body[5:], &req) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/grpc-web+json")
+			if req.Name == "" {
+				_, _ = w.Write(grpcWebFrame(0x80, []byte("grpc-status: 3\r\ngrpc-message: name%20is%20required\r\n")))
+				return
+			}
+
+			message, _ := json.Marshal(greetResponse{Greeting: "Hello, " + req.Name})
+			_, _ = w.Write(grpcWebFrame(0, message))
+			_, _ = w.Write(grpcWebFrame(0x80, []byte("grpc-status: 0\r\n")))
+			return
+		}
+
+		var req greetRequest
+		if r.Header.Get("Connect-Protocol-Version") != "1" || json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.Name == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"invalid_argument","message":"name is required","details":[{"type":"x"}]}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(greetResponse{Greeting: "Hello, " + req.Name})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		protocol clink.ConnectProtocol
+		request  greetRequest
+		want     string
+		wantCode string
+		wantMsg  string
+	}{
+		{name: "connect unary", protocol: clink.ConnectUnary, request: greetRequest{Name: "alice"}, want: "Hello, alice"},
+		{name: "connect error", protocol: clink.ConnectUnary, wantCode: "invalid_argument", wantMsg: "name is required"},
+		{name: "grpc-web", protocol: clink.GRPCWeb, request: greetRequest{Name: "bob"}, want: "Hello, bob"},
+		{name: "grpc-web error", protocol: clink.GRPCWeb, wantCode: "invalid_argument", wantMsg: "name is required"},
+	}
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Post(server.URL+"/greet.v1.GreetService/Greet", nil, clink.ConnectBody(tt.protocol, tt.request))
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			var got greetResponse
+			err = clink.DecodeConnect(resp, &got)
+
+			var connectErr *clink.ConnectError
+			if tt.wantCode != "" {
+				if !errors.As(err, &connectErr) || connectErr.Code != tt.wantCode || connectErr.Message != tt.wantMsg {
+					t.Fatalf("expected connect error %s: %s, got %v", tt.wantCode, tt.wantMsg, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got.Greeting != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got.Greeting)
+			}
+		})
+	}
+}
+
+func TestConnect_HTTPStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := client.Post(server.URL+"/greet.v1.GreetService/Greet", nil, clink.ConnectBody(clink.ConnectUnary, greetRequest{}))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	var got greetResponse
+	var connectErr *clink.ConnectError
+	if err := clink.DecodeConnect(resp, &got); !errors.As(err, &connectErr) || connectErr.Code != "unavailable" {
+		t.Errorf("expected an unavailable connect error, got %v", err)
+	}
+}
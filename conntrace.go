@@ -0,0 +1,48 @@
+package clink
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// ConnectionHooks are called on connection events of the requests sent by the client.
+type ConnectionHooks struct {
+	// Connected is called when a new connection is established, or fails to be.
+	Connected func(network, addr string, err error)
+	// TLSHandshake is called when a TLS handshake completes, state holding the negotiated version and cipher suite.
+	TLSHandshake func(state tls.ConnectionState, err error)
+	// Acquired is called when a request gets a connection, reporting whether it was reused and how long it was idle.
+	Acquired func(addr string, reused bool, idle time.Duration)
+}
+
+// WithConnectionHooks sets hooks called on the connection events of every request.
+func WithConnectionHooks(hooks ConnectionHooks) Option {
+	return func(c *Client) {
+		c.connectionHooks = &hooks
+	}
+}
+
+// traceConnections attaches the connection hooks of the client to the request.
+func (c *Client) traceConnections(req *http.Request) *http.Request {
+	hooks := c.connectionHooks
+	if hooks == nil {
+		return req
+	}
+
+	trace := &httptrace.ClientTrace{}
+	if hooks.Connected != nil {
+		trace.ConnectDone = hooks.Connected
+	}
+	if hooks.TLSHandshake != nil {
+		trace.TLSHandshakeDone = hooks.TLSHandshake
+	}
+	if hooks.Acquired != nil {
+		trace.GotConn = func(info httptrace.GotConnInfo) {
+			hooks.Acquired(info.Conn.RemoteAddr().String(), info.Reused, info.IdleTime)
+		}
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
@@ -0,0 +1,62 @@
+package clink_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_ConnectionHooks(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var connects, handshakes int
+	var reused []bool
+	var version uint16
+
+	c := clink.NewClient(clink.WithConnectionHooks(clink.ConnectionHooks{
+		Connected: func(network, addr string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			connects++
+		},
+		TLSHandshake: func(state tls.ConnectionState, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			handshakes++
+			version = state.Version
+		},
+		Acquired: func(addr string, isReused bool, idle time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			reused = append(reused, isReused)
+		},
+	}), clink.WithClient(server.Client()))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if connects != 1 || handshakes != 1 {
+		t.Errorf("expected one connection and handshake, got %d and %d", connects, handshakes)
+	}
+	if version < tls.VersionTLS12 {
+		t.Errorf("expected a TLS 1.2+ connection, got %s", tls.VersionName(version))
+	}
+	if len(reused) != 2 || reused[0] || !reused[1] {
+		t.Errorf("expected the second request to reuse the connection, got %v", reused)
+	}
+}
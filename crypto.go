@@ -0,0 +1,95 @@
+package clink
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrUnapprovedAlgorithm is returned when an algorithm that is not FIPS approved is used in FIPS mode.
+var ErrUnapprovedAlgorithm = errors.New("algorithm not approved in FIPS mode")
+
+// HashAlgorithm is a hash function used by the signing and digest features of the client.
+type HashAlgorithm string
+
+const (
+	HashMD5    HashAlgorithm = "MD5"
+	HashSHA1   HashAlgorithm = "SHA-1"
+	HashSHA256 HashAlgorithm = "SHA-256"
+	HashSHA384 HashAlgorithm = "SHA-384"
+	HashSHA512 HashAlgorithm = "SHA-512"
+)
+
+// Approved reports whether the algorithm is FIPS approved for signing and digests. MD5 and SHA-1 are not.
+func (a HashAlgorithm) Approved() bool {
+	switch a {
+	case HashSHA256, HashSHA384, HashSHA512:
+		return true
+	}
+
+	return false
+}
+
+// newFunc returns the constructor of the hash.
+func (a HashAlgorithm) newFunc() (func() hash.Hash, error) {
+	switch a {
+	case HashMD5:
+		return md5.New, nil
+	case HashSHA1:
+		return sha1.New, nil
+	case HashSHA256:
+		return sha256.New, nil
+	case HashSHA384:
+		return sha512.New384, nil
+	case HashSHA512:
+		return sha512.New, nil
+	}
+
+	return nil, fmt.Errorf("unsupported hash algorithm %q", a)
+}
+
+// WithFIPSMode rejects algorithms that are not FIPS approved in the signing and digest features of the client.
+// It is enabled by default in binaries built with the fips or boringcrypto build tag.
+func WithFIPSMode(enabled bool) Option {
+	return func(c *Client) {
+		c.fipsMode = enabled
+	}
+}
+
+// FIPSMode reports whether the client rejects algorithms that are not FIPS approved.
+func (c *Client) FIPSMode() bool {
+	return c.fipsMode
+}
+
+// NewHash returns a new hash for the algorithm, failing with ErrUnapprovedAlgorithm if it is not approved in FIPS mode.
+func (c *Client) NewHash(algorithm HashAlgorithm) (hash.Hash, error) {
+	newHash, err := c.hashFunc(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return newHash(), nil
+}
+
+// NewHMAC returns a new HMAC using the algorithm and key, failing with ErrUnapprovedAlgorithm if it is not approved in FIPS mode.
+func (c *Client) NewHMAC(algorithm HashAlgorithm, key []byte) (hash.Hash, error) {
+	newHash, err := c.hashFunc(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return hmac.New(newHash, key), nil
+}
+
+func (c *Client) hashFunc(algorithm HashAlgorithm) (func() hash.Hash, error) {
+	if c.fipsMode && !algorithm.Approved() {
+		return nil, fmt.Errorf("%w: %s", ErrUnapprovedAlgorithm, algorithm)
+	}
+
+	return algorithm.newFunc()
+}
@@ -0,0 +1,48 @@
+package clink_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_NewHash(t *testing.T) {
+	testCases := []struct {
+		name      string
+		fips      bool
+		algorithm clink.HashAlgorithm
+		expected  string
+		err       error
+	}{
+		{name: "md5", algorithm: clink.HashMD5, expected: "900150983cd24fb0d6963f7d28e17f72"},
+		{name: "sha256", algorithm: clink.HashSHA256, expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{name: "sha256 in fips mode", fips: true, algorithm: clink.HashSHA256, expected: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{name: "md5 in fips mode", fips: true, algorithm: clink.HashMD5, err: clink.ErrUnapprovedAlgorithm},
+		{name: "sha1 in fips mode", fips: true, algorithm: clink.HashSHA1, err: clink.ErrUnapprovedAlgorithm},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(clink.WithFIPSMode(tc.fips))
+
+			h, err := c.NewHash(tc.algorithm)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			h.Write([]byte("abc"))
+			if sum := hex.EncodeToString(h.Sum(nil)); sum != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, sum)
+			}
+
+			if _, err := c.NewHMAC(tc.algorithm, []byte("key")); err != nil {
+				t.Errorf("expected an hmac, got %v", err)
+			}
+		})
+	}
+}
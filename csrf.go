@@ -0,0 +1,130 @@
+package clink
+
+import (
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// CSRF configures where the client finds CSRF tokens in responses and how it sends them back.
+type CSRF struct {
+	// Cookie is the name of the cookie holding the token, such as "XSRF-TOKEN".
+	Cookie string
+	// Header is the name of the response header holding the token, such as "X-CSRF-Token".
+	Header string
+	// Meta is the name of the HTML meta tag holding the token in its content attribute, such as "csrf-token".
+	Meta string
+	// RequestHeader is the header set on mutating requests. Defaults to "X-CSRF-Token".
+	RequestHeader string
+}
+
+// maxCSRFPeek bounds how much of an HTML body is searched for the CSRF meta tag.
+const maxCSRFPeek = 64 << 10
+
+var (
+	metaTagPattern   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attributePattern = regexp.MustCompile(`(?s)([\w-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+type csrfTokens struct {
+	CSRF
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// WithCSRF extracts CSRF tokens from responses and sets the latest token of a host on the POST, PUT, PATCH and DELETE
+// requests sent to it, unless they already have the request header.
+func WithCSRF(csrf CSRF) Option {
+	return func(c *Client) {
+		if csrf.RequestHeader == "" {
+			csrf.RequestHeader = "X-CSRF-Token"
+		}
+		c.csrf = &csrfTokens{CSRF: csrf, tokens: make(map[string]string)}
+	}
+}
+
+// ExtractCSRFToken returns the CSRF token of the response, looking at the cookie, the header and the HTML meta tag
+// configured in csrf, in that order. The body is left readable.
+func ExtractCSRFToken(resp *http.Response, csrf CSRF) (string, bool) {
+	if resp == nil {
+		return "", false
+	}
+
+	if csrf.Cookie != "" {
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == csrf.Cookie && cookie.Value != "" {
+				return cookie.Value, true
+			}
+		}
+	}
+
+	if csrf.Header != "" {
+		if token := resp.Header.Get(csrf.Header); token != "" {
+			return token, true
+		}
+	}
+
+	if csrf.Meta != "" && resp.Body != nil && strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return metaContent(peekBody(resp, maxCSRFPeek), csrf.Meta)
+	}
+
+	return "", false
+}
+
+// metaContent returns the content attribute of the meta tag with the given name.
+func metaContent(body []byte, name string) (string, bool) {
+	for _, tag := range metaTagPattern.FindAll(body, -1) {
+		attributes := make(map[string]string)
+		for _, m := range attributePattern.FindAllSubmatch(tag, -1) {
+			attributes[strings.ToLower(string(m[1]))] = string(m[2]) + string(m[3]) + string(m[4])
+		}
+
+		if strings.EqualFold(attributes["name"], name) {
+			if content := html.UnescapeString(attributes["content"]); content != "" {
+				return content, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// extractCSRF stores the CSRF token of the response for its host.
+func (c *Client) extractCSRF(req *http.Request, resp *http.Response) {
+	if c.csrf == nil || resp == nil {
+		return
+	}
+
+	token, ok := ExtractCSRFToken(resp, c.csrf.CSRF)
+	if !ok {
+		return
+	}
+
+	c.csrf.mu.Lock()
+	c.csrf.tokens[req.URL.Host] = token
+	c.csrf.mu.Unlock()
+}
+
+// injectCSRF sets the CSRF token of the host on mutating requests.
+func (c *Client) injectCSRF(req *http.Request) {
+	if c.csrf == nil || req.Header.Get(c.csrf.RequestHeader) != "" {
+		return
+	}
+
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return
+	}
+
+	c.csrf.mu.Lock()
+	token, ok := c.csrf.tokens[req.URL.Host]
+	c.csrf.mu.Unlock()
+
+	if ok {
+		req.Header.Set(c.csrf.RequestHeader, token)
+	}
+}
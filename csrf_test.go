@@ -0,0 +1,112 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_CSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		csrf   clink.CSRF
+		header string
+	}{
+		{
+			name:   "cookie",
+			csrf:   clink.CSRF{Cookie: "XSRF-TOKEN", RequestHeader: "X-XSRF-Token"},
+			header: "X-XSRF-Token",
+		},
+		{
+			name:   "header",
+			csrf:   clink.CSRF{Header: "X-CSRF-Token"},
+			header: "X-CSRF-Token",
+		},
+		{
+			name:   "meta tag",
+			csrf:   clink.CSRF{Meta: "csrf-token"},
+			header: "X-CSRF-Token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					http.SetCookie(w, &http.Cookie{Name: "XSRF-TOKEN", Value: "token"})
+					w.Header().Set("X-CSRF-Token", "token")
+					w.Header().Set("Content-Type", "text/html; charset=utf-8")
+					_, _ = w.Write([]byte(`<html><head><meta content="token" name="csrf-token"></head><body>form</body></html>`))
+					return
+				}
+				received = append(received, r.Header.Get(tt.header))
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithCSRF(tt.csrf), clink.WithClient(server.Client()))
+
+			resp, err := c.Post(server.URL, strings.NewReader("before"))
+			if err != nil {
+				t.Fatalf("failed to post: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			resp, err = c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to get: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if !strings.Contains(string(body), "form") {
+				t.Errorf("expected the body to stay readable, got %q", body)
+			}
+
+			resp, err = c.Post(server.URL, strings.NewReader("after"))
+			if err != nil {
+				t.Fatalf("failed to post: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if len(received) != 2 || received[0] != "" || received[1] != "token" {
+				t.Errorf("expected the token on the second post only, got %q", received)
+			}
+		})
+	}
+}
+
+func TestExtractCSRFToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		token string
+		ok    bool
+	}{
+		{name: "name first", body: `<meta name="csrf-token" content="abc">`, token: "abc", ok: true},
+		{name: "single quotes", body: `<META content='a&amp;b' NAME='csrf-token' />`, token: "a&b", ok: true},
+		{name: "other meta", body: `<meta name="viewport" content="width=device-width"><meta name="csrf-token" content="abc">`, token: "abc", ok: true},
+		{name: "missing", body: `<meta name="viewport" content="width=device-width">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{"Content-Type": {"text/html"}},
+				Body:   io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			token, ok := clink.ExtractCSRFToken(resp, clink.CSRF{Meta: "csrf-token"})
+			if token != tt.token || ok != tt.ok {
+				t.Errorf("expected %q %v, got %q %v", tt.token, tt.ok, token, ok)
+			}
+
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+				t.Errorf("expected the body to stay readable, got %q", body)
+			}
+		})
+	}
+}
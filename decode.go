@@ -0,0 +1,183 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// ErrNoContent is returned when decoding a response that has no content.
+var ErrNoContent = errors.New("response has no content")
+
+// DecodeOption configures how response bodies are decoded.
+type DecodeOption func(*decodeConfig)
+
+type decodeConfig struct {
+	allowNoContent        bool
+	useNumber             bool
+	disallowUnknownFields bool
+	unmarshal             func([]byte, any) error
+	sniffContent          bool
+	strictContentType     bool
+	contentType           string
+}
+
+type decodeOptionsKey struct{}
+
+func newDecodeConfig(opts []DecodeOption) *decodeConfig {
+	cfg := &decodeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// AllowNoContent decodes 204 and 205 responses and empty bodies into the zero value of the target instead of failing.
+func AllowNoContent() DecodeOption {
+	return func(cfg *decodeConfig) {
+		cfg.allowNoContent = true
+	}
+}
+
+// UseNumber decodes JSON numbers into json.Number instead of float64 so large integers keep their precision
+// when decoding into interface values such as map[string]any.
+func UseNumber() DecodeOption {
+	return func(cfg *decodeConfig) {
+		cfg.useNumber = true
+	}
+}
+
+// DisallowUnknownFields fails decoding when the body contains object keys that do not match a field of the target struct.
+func DisallowUnknownFields() DecodeOption {
+	return func(cfg *decodeConfig) {
+		cfg.disallowUnknownFields = true
+	}
+}
+
+// responseDecodeConfig returns the decode configuration of the response, applying the decode options
+// of the client that sent it before opts.
+func responseDecodeConfig(response *http.Response, opts []DecodeOption) *decodeConfig {
+	if defaults, ok := requestValue[[]DecodeOption](response.Request, decodeOptionsKey{}); ok {
+		opts = append(append([]DecodeOption(nil), defaults...), opts...)
+	}
+
+	cfg := newDecodeConfig(opts)
+	if codec, ok := requestValue[*jsonCodec](response.Request, jsonCodecKey{}); ok {
+		cfg.unmarshal = codec.unmarshal
+	}
+	cfg.contentType = response.Header.Get("Content-Type")
+
+	return cfg
+}
+
+// newDecoder returns a JSON decoder for r configured with the decode options.
+func (cfg *decodeConfig) newDecoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	if cfg.useNumber {
+		decoder.UseNumber()
+	}
+	if cfg.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	return decoder
+}
+
+// decode decodes r into target, returning io.EOF when r has no content.
+// A custom unmarshal function, set by WithJSONCodec, is used instead of the streaming decoder.
+func (cfg *decodeConfig) decode(r io.Reader, target any) error {
+	if cfg.sniffContent {
+		sniffed, err := sniffJSON(r, cfg.contentType)
+		if err != nil {
+			return err
+		}
+		r = sniffed
+	}
+
+	if cfg.unmarshal == nil {
+		return cfg.newDecoder(r).Decode(target)
+	}
+
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return io.EOF
+	}
+
+	return cfg.unmarshal(buf.Bytes(), target)
+}
+
+// noContent handles a response without content, resetting the target to its zero value when allowed.
+func noContent[T any](cfg *decodeConfig, target *T) error {
+	if !cfg.allowNoContent {
+		return &DecodeError{Content: "response", Err: ErrNoContent}
+	}
+
+	var zero T
+	*target = zero
+
+	return nil
+}
+
+// DecodeByStatus decodes the response body into the target registered for its status code, such as
+// map[int]any{200: &user, 422: &validationErrors}. A nil target discards the body.
+// Responses whose status code has no target return a *StatusError.
+func DecodeByStatus(response *http.Response, targets map[int]any, opts ...DecodeOption) error {
+	if response == nil {
+		return ErrNilResponse
+	}
+
+	target, ok := targets[response.StatusCode]
+	if !ok {
+		return newStatusError(response.Request, response)
+	}
+
+	if target == nil {
+		discardBody(response)
+		return nil
+	}
+
+	if response.Body == nil {
+		return ErrNilBody
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	cfg := responseDecodeConfig(response, opts)
+
+	err := io.EOF
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusResetContent {
+		if err := cfg.checkContentType("application/json"); err != nil {
+			return err
+		}
+		err = cfg.decode(response.Body, target)
+	}
+
+	if errors.Is(err, io.EOF) {
+		if !cfg.allowNoContent {
+			return &DecodeError{Content: "response", Err: ErrNoContent}
+		}
+
+		if v := reflect.ValueOf(target); v.Kind() == reflect.Pointer && !v.IsNil() {
+			v.Elem().SetZero()
+		}
+		return nil
+	}
+
+	if err != nil {
+		return &DecodeError{Content: "response", Err: err}
+	}
+
+	return nil
+}
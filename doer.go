@@ -0,0 +1,46 @@
+package clink
+
+import (
+	"io"
+	"net/http"
+)
+
+// Doer sends requests. *Client, *http.Client and Handler implement it, so that packages can depend on it
+// and be given a client, a mock such as clinktest.Transport, or a decorated client.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Requester is a Doer with the request helpers of *Client.
+type Requester interface {
+	Doer
+	Head(url string, opts ...RequestOption) (*http.Response, error)
+	Options(url string, opts ...RequestOption) (*http.Response, error)
+	Get(url string, opts ...RequestOption) (*http.Response, error)
+	Post(url string, body io.Reader, opts ...RequestOption) (*http.Response, error)
+	Put(url string, body io.Reader, opts ...RequestOption) (*http.Response, error)
+	Patch(url string, body io.Reader, opts ...RequestOption) (*http.Response, error)
+	Delete(url string, opts ...RequestOption) (*http.Response, error)
+}
+
+var (
+	_ Requester = (*Client)(nil)
+	_ Doer      = (*http.Client)(nil)
+	_ Doer      = Handler(nil)
+)
+
+// Do implements Doer by calling the handler.
+func (h Handler) Do(req *http.Request) (*http.Response, error) {
+	return h(req)
+}
+
+// Decorate returns a Doer sending the requests to d through the middleware, the first one running first.
+// Unlike WithMiddleware, the middleware runs once per call rather than once per attempt.
+func Decorate(d Doer, middleware ...Middleware) Doer {
+	handler := Handler(d.Do)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
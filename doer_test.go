@@ -0,0 +1,75 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+// fetchName depends on clink.Doer rather than on *clink.Client.
+func fetchName(doer clink.Doer, url string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+
+	return string(body), err
+}
+
+func TestDoer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("alice " + r.Header.Get("X-Trace")))
+	}))
+	defer server.Close()
+
+	transport := clinktest.NewTransport()
+	transport.On(http.MethodGet, "/users/1").Respond(clinktest.Reply(http.StatusOK, "mock"))
+
+	traced := func(next clink.Handler) clink.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Trace", "1")
+			return next(req)
+		}
+	}
+
+	var requester clink.Requester = clink.NewClient(clink.WithClient(server.Client()))
+
+	tests := []struct {
+		name string
+		doer clink.Doer
+		url  string
+		want string
+	}{
+		{"client", requester, server.URL, "alice "},
+		{"decorated client", clink.Decorate(requester, traced), server.URL, "alice 1"},
+		{"mock", transport, "https://api.example.com/users/1", "mock"},
+		{"handler", clink.Handler(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("stub"))}, nil
+		}), server.URL, "stub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := fetchName(tt.doer, tt.url)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			if name != tt.want {
+				t.Errorf("expected %q, got: %q", tt.want, name)
+			}
+		})
+	}
+}
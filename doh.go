@@ -0,0 +1,141 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// DoHCloudflare is the Cloudflare DNS over HTTPS endpoint.
+	DoHCloudflare = "https://1.1.1.1/dns-query"
+	// DoHGoogle is the Google DNS over HTTPS endpoint.
+	DoHGoogle = "https://8.8.8.8/dns-query"
+	// DoHQuad9 is the Quad9 DNS over HTTPS endpoint.
+	DoHQuad9 = "https://9.9.9.9:5053/dns-query"
+)
+
+const dnsMessageContentType = "application/dns-message"
+
+// WithDNSOverHTTPS resolves host names using the given RFC 8484 DNS over HTTPS endpoint instead of the system resolver.
+// The endpoint should use an IP address (as the DoH* constants do) so that it can be reached without plain DNS.
+func WithDNSOverHTTPS(endpoint string) Option {
+	return func(c *Client) {
+		c.netDialer().Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return &dohConn{
+					ctx:      ctx,
+					client:   &http.Client{Timeout: 10 * time.Second},
+					endpoint: endpoint,
+				}, nil
+			},
+		}
+	}
+}
+
+// dohConn is a net.Conn that sends length prefixed DNS messages written to it to a DoH endpoint
+// and makes the length prefixed answers available for reading.
+type dohConn struct {
+	ctx      context.Context
+	client   *http.Client
+	endpoint string
+	deadline time.Time
+	query    bytes.Buffer
+	answer   bytes.Buffer
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	c.query.Write(b)
+
+	for c.query.Len() >= 2 {
+		buf := c.query.Bytes()
+		size := int(buf[0])<<8 | int(buf[1])
+		if len(buf) < size+2 {
+			break
+		}
+
+		msg := make([]byte, size)
+		copy(msg, buf[2:size+2])
+		c.query.Next(size + 2)
+
+		answer, err := c.exchange(msg)
+		if err != nil {
+			return 0, err
+		}
+
+		c.answer.Write([]byte{byte(len(answer) >> 8), byte(len(answer))})
+		c.answer.Write(answer)
+	}
+
+	return len(b), nil
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.answer.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	return c.answer.Read(b)
+}
+
+// exchange posts the DNS message to the DoH endpoint and returns the answer message.
+func (c *dohConn) exchange(msg []byte) ([]byte, error) {
+	ctx := c.ctx
+	if !c.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, c.deadline)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(msg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dns request: %w", err)
+	}
+	req.Header.Set("Content-Type", dnsMessageContentType)
+	req.Header.Set("Accept", dnsMessageContentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do dns request: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected dns response status: %d", resp.StatusCode)
+	}
+
+	answer, err := io.ReadAll(io.LimitReader(resp.Body, 65535))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dns response: %w", err)
+	}
+
+	if len(answer) == 0 {
+		return nil, errors.New("empty dns response")
+	}
+
+	return answer, nil
+}
+
+func (c *dohConn) Close() error                       { return nil }
+func (c *dohConn) LocalAddr() net.Addr                { return dohAddr(c.endpoint) }
+func (c *dohConn) RemoteAddr() net.Addr               { return dohAddr(c.endpoint) }
+func (c *dohConn) SetReadDeadline(time.Time) error    { return nil }
+func (c *dohConn) SetWriteDeadline(t time.Time) error { return c.SetDeadline(t) }
+
+func (c *dohConn) SetDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+type dohAddr string
+
+func (a dohAddr) Network() string { return "https" }
+func (a dohAddr) String() string  { return string(a) }
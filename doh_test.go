@@ -0,0 +1,82 @@
+package clink_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+// dnsAnswer builds a minimal DNS answer to the query, resolving A questions to ip and leaving other types empty.
+func dnsAnswer(query []byte, ip net.IP) []byte {
+	// Skip the question name to find the question type.
+	offset := 12
+	for query[offset] != 0 {
+		offset += int(query[offset]) + 1
+	}
+	questionEnd := offset + 5
+	qtype := int(query[offset+1])<<8 | int(query[offset+2])
+
+	answer := make([]byte, questionEnd)
+	copy(answer, query[:questionEnd])
+	answer[2] |= 0x80 // QR
+	answer[3] = 0x80  // RA, NOERROR
+	answer[6], answer[7] = 0, 0
+	answer[8], answer[9], answer[10], answer[11] = 0, 0, 0, 0
+
+	if qtype == 1 {
+		answer[7] = 1
+		answer = append(answer, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+		answer = append(answer, ip.To4()...)
+	}
+
+	return answer
+}
+
+func TestDNSOverHTTPS(t *testing.T) {
+	var queries atomic.Int32
+	doh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/dns-message" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		query, _ := io.ReadAll(r.Body)
+		queries.Add(1)
+
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(dnsAnswer(query, net.ParseIP("127.0.0.1")))
+	}))
+	defer doh.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+
+	client := clink.NewClient(
+		clink.WithClient(&http.Client{}),
+		clink.WithDNSOverHTTPS(doh.URL),
+	)
+
+	resp, err := client.Get("http://clink.invalid:" + port)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "clink.invalid:"+port {
+		t.Errorf("expected host to be preserved, got: %s", body)
+	}
+
+	if queries.Load() == 0 {
+		t.Errorf("expected the DoH endpoint to be queried")
+	}
+}
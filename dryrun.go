@@ -0,0 +1,49 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type dryRunKey struct{}
+
+// errDryRun is returned in place of a response by the handler ending the middleware chain of dry runs.
+var errDryRun = errors.New("dry run")
+
+// DryRun runs a copy of the request through the pipeline of the client, as Do would, and returns the request that
+// would be sent to the transport, after its headers, body, endpoint, replay protection headers, CSRF token and
+// middleware such as signing have been applied. The rate limiter, retries and proxies are not involved, and the endpoint
+// rotation, replay protection timestamps and the source set with WithRandom are left untouched.
+// A middleware returning without calling the next handler fails the dry run. The original request is left unchanged.
+func (c *Client) DryRun(req *http.Request) (*http.Request, error) {
+	prepared, err := c.prepare(req.Clone(context.WithValue(req.Context(), dryRunKey{}, true)))
+	if err != nil {
+		return nil, err
+	}
+
+	c.stampRequest(prepared)
+	c.injectCSRF(prepared)
+	c.useEndpoint(prepared)
+
+	var sent *http.Request
+	_, err = c.handleWith(prepared, func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return nil, errDryRun
+	})
+	if sent == nil {
+		if err == nil || errors.Is(err, errDryRun) {
+			err = errors.New("middleware returned without sending the request")
+		}
+		return nil, err
+	}
+
+	return sent, nil
+}
+
+// isDryRun reports whether the request is run by DryRun, and must not change the state of the client.
+func isDryRun(req *http.Request) bool {
+	dryRun, _ := requestValue[bool](req, dryRunKey{})
+
+	return dryRun
+}
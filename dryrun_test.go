@@ -0,0 +1,123 @@
+package clink_test
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestDryRun(t *testing.T) {
+	client := clink.NewClient(
+		clink.WithClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			t.Fatal("expected the request not to be sent")
+			return nil, nil
+		})}),
+		clink.WithBearerAuth("token"),
+		clink.WithEndpoints("https://api.example.com"),
+	)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://placeholder/users", nil)
+	req = clink.WithRequestOptions(req, clink.JSONBody(map[string]string{"name": "yumi"}))
+
+	prepared, err := client.DryRun(req)
+	if err != nil {
+		t.Fatalf("failed to prepare request: %v", err)
+	}
+
+	if prepared.Header.Get("Authorization") != "Bearer token" || prepared.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected client headers to be applied, got: %v", prepared.Header)
+	}
+
+	if prepared.URL.String() != "https://api.example.com/users" {
+		t.Errorf("expected endpoint to be applied, got: %s", prepared.URL)
+	}
+
+	body, _ := io.ReadAll(prepared.Body)
+	if string(body) != `{"name":"yumi"}` {
+		t.Errorf("expected encoded body, got: %s", body)
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("expected original request to be left unchanged")
+	}
+}
+
+// countingSource is a rand.Source counting the values drawn from it.
+type countingSource struct {
+	rand.Source
+	draws int
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.Source.Int63()
+}
+
+func TestDryRun_Pipeline(t *testing.T) {
+	var hosts []string
+	source := &countingSource{Source: rand.NewSource(1)}
+	client := clink.NewClient(
+		clink.WithClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			hosts = append(hosts, req.URL.Host)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+		})}),
+		clink.WithEndpoints("https://a.example.com", "https://b.example.com"),
+		clink.WithRandom(source),
+		clink.WithTrafficSplit("https://a.example.com", "https://canary.example.com", 50),
+		clink.WithReplayProtection(clink.ReplayProtection{}),
+		clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Signature", "signed")
+				return next(req)
+			}
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "https://a.example.com/users", nil)
+		prepared, err := client.DryRun(req)
+		if err != nil {
+			t.Fatalf("failed to prepare request: %v", err)
+		}
+
+		if prepared.Header.Get("X-Signature") != "signed" {
+			t.Errorf("expected the middleware to run, got: %v", prepared.Header)
+		}
+		if prepared.Header.Get("X-Timestamp") == "" || prepared.Header.Get("X-Nonce") == "" {
+			t.Errorf("expected replay protection headers, got: %v", prepared.Header)
+		}
+		if prepared.URL.Host != "a.example.com" {
+			t.Errorf("expected the first endpoint on every dry run, got: %s", prepared.URL.Host)
+		}
+	}
+
+	if len(hosts) != 0 {
+		t.Errorf("expected dry runs not to send requests, got: %v", hosts)
+	}
+	if source.draws != 0 {
+		t.Errorf("expected dry runs not to draw from the random source, got %d draws", source.draws)
+	}
+
+	if _, err := client.Get("https://a.example.com/users"); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0] != "a.example.com" {
+		t.Errorf("expected dry runs not to advance the endpoint rotation, got: %v", hosts)
+	}
+}
+
+func TestDryRun_ShortCircuit(t *testing.T) {
+	client := clink.NewClient(clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if _, err := client.DryRun(req); err == nil {
+		t.Error("expected an error when the middleware does not send the request")
+	}
+}
@@ -0,0 +1,232 @@
+package clink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// HealthCheck configures how endpoints set with WithEndpoints are checked and ejected from rotation.
+type HealthCheck struct {
+	// Path is requested on every endpoint at each Interval to actively check its health.
+	// Active checks are disabled when Path is empty.
+	Path     string
+	Interval time.Duration
+	// FailureThreshold is the number of consecutive failures after which an endpoint is ejected. Defaults to 3.
+	FailureThreshold int
+	// IsHealthy reports whether a response or error indicates a healthy endpoint.
+	// Defaults to a response without error and with a status code below 500.
+	IsHealthy func(*http.Response, error) bool
+	// OnChange is called when an endpoint is ejected or added back to the rotation.
+	OnChange func(endpoint string, healthy bool)
+}
+
+// WithEndpoints sends requests to the given endpoints in turn, replacing the scheme and host of the request URL.
+// Each retry attempt uses the next endpoint, and endpoints failing repeatedly are ejected until they recover.
+func WithEndpoints(endpoints ...string) Option {
+	return func(c *Client) {
+		pool := &endpointPool{}
+		for _, endpoint := range endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil || u.Host == "" {
+				continue
+			}
+			pool.endpoints = append(pool.endpoints, &endpointState{url: u, healthy: true})
+		}
+
+		if len(pool.endpoints) > 0 {
+			c.endpoints = pool
+		}
+	}
+}
+
+// WithHealthCheck configures passive and active health checking of the endpoints set with WithEndpoints.
+func WithHealthCheck(check HealthCheck) Option {
+	return func(c *Client) {
+		c.healthCheck = check
+
+		if check.Path != "" && check.Interval > 0 {
+			c.background = append(c.background, c.checkEndpoints)
+		}
+	}
+}
+
+// Endpoints returns the endpoints currently in rotation.
+func (c *Client) Endpoints() []string {
+	if c.endpoints == nil {
+		return nil
+	}
+
+	c.endpoints.mu.Lock()
+	defer c.endpoints.mu.Unlock()
+
+	var healthy []string
+	for _, e := range c.endpoints.endpoints {
+		if e.healthy {
+			healthy = append(healthy, e.url.String())
+		}
+	}
+
+	return healthy
+}
+
+type endpointState struct {
+	url      *url.URL
+	healthy  bool
+	failures int
+}
+
+type endpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpointState
+	next      int
+}
+
+// pick returns the next healthy endpoint, or the next endpoint and false if none are healthy.
+// The rotation only moves forward when advance is set.
+func (p *endpointPool) pick(advance bool) (*endpointState, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := 0; i < len(p.endpoints); i++ {
+		e := p.endpoints[(p.next+i)%len(p.endpoints)]
+		if e.healthy {
+			if advance {
+				p.next = (p.next + i + 1) % len(p.endpoints)
+			}
+			return e, true
+		}
+	}
+
+	e := p.endpoints[p.next]
+	if advance {
+		p.next = (p.next + 1) % len(p.endpoints)
+	}
+
+	return e, false
+}
+
+// useEndpoint points the request at the next endpoint and returns it, or nil when no endpoints are configured.
+// Dry runs leave the rotation unchanged.
+func (c *Client) useEndpoint(req *http.Request) *endpointState {
+	if c.endpoints == nil {
+		return nil
+	}
+
+	e, healthy := c.endpoints.pick(!isDryRun(req))
+	if stats, ok := requestValue[*requestStats](req, requestStatsKey{}); ok {
+		stats.circuitOpen = !healthy
+	}
+
+	u := *req.URL
+	u.Scheme = e.url.Scheme
+	u.Host = e.url.Host
+	req.URL = &u
+	if requestConfigFrom(req).host == "" {
+		req.Host = ""
+	}
+
+	return e
+}
+
+// reportEndpoint records the outcome of a request to the endpoint, ejecting or restoring it as needed.
+// The request is nil for active health checks.
+func (c *Client) reportEndpoint(req *http.Request, e *endpointState, resp *http.Response, err error) {
+	if e == nil {
+		return
+	}
+
+	isHealthy := c.healthCheck.IsHealthy
+	if isHealthy == nil {
+		isHealthy = defaultIsHealthy
+	}
+
+	threshold := c.healthCheck.FailureThreshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+
+	c.endpoints.mu.Lock()
+	wasHealthy := e.healthy
+	if isHealthy(resp, err) {
+		e.failures = 0
+		e.healthy = true
+	} else {
+		e.failures++
+		if e.failures >= threshold {
+			e.healthy = false
+		}
+	}
+	changed := wasHealthy != e.healthy
+	healthy := e.healthy
+	c.endpoints.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if c.healthCheck.OnChange != nil {
+		func() {
+			defer recordPanic(req)
+			c.healthCheck.OnChange(e.url.String(), healthy)
+		}()
+	}
+
+	event := StateEvent{Kind: EndpointEjected, Request: req, Target: e.url.String(), Err: err}
+	if healthy {
+		event.Kind = EndpointRestored
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+	c.emitState(event)
+}
+
+// checkEndpoints actively probes every endpoint until the context is cancelled.
+func (c *Client) checkEndpoints(ctx context.Context) {
+	if c.endpoints == nil {
+		return
+	}
+
+	ticker := time.NewTicker(c.healthCheck.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, e := range c.endpoints.endpoints {
+			c.probeEndpoint(ctx, e)
+		}
+	}
+}
+
+// probeEndpoint requests the health check path on the endpoint and reports the outcome.
+func (c *Client) probeEndpoint(ctx context.Context, e *endpointState) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url.JoinPath(c.healthCheck.Path).String(), nil)
+	if err != nil {
+		return
+	}
+
+	resp, err := c.HttpClient.Do(req)
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.reportEndpoint(nil, e, resp, err)
+
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
+
+func defaultIsHealthy(resp *http.Response, err error) bool {
+	return err == nil && resp != nil && resp.StatusCode < http.StatusInternalServerError
+}
@@ -0,0 +1,80 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestEndpoints(t *testing.T) {
+	var mu sync.Mutex
+	healthy := true
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("X-Server", "bad")
+	}))
+	defer bad.Close()
+
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server", "good")
+	}))
+	defer good.Close()
+
+	changes := make(chan bool, 10)
+	client := clink.NewClient(
+		clink.WithClient(good.Client()),
+		clink.WithEndpoints(bad.URL, good.URL),
+		clink.WithHealthCheck(clink.HealthCheck{
+			Path:             "/health",
+			Interval:         20 * time.Millisecond,
+			FailureThreshold: 1,
+			OnChange: func(endpoint string, healthy bool) {
+				changes <- healthy
+			},
+		}),
+	)
+	defer client.Close()
+
+	mu.Lock()
+	healthy = false
+	mu.Unlock()
+
+	if ejected := <-changes; ejected {
+		t.Fatalf("expected bad endpoint to be ejected")
+	}
+
+	if endpoints := client.Endpoints(); len(endpoints) != 1 || endpoints[0] != good.URL {
+		t.Errorf("expected only the good endpoint in rotation, got: %v", endpoints)
+	}
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get("http://placeholder/resource")
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		if resp.Header.Get("X-Server") != "good" {
+			t.Errorf("expected request to be sent to the good endpoint")
+		}
+	}
+
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+
+	if restored := <-changes; !restored {
+		t.Fatalf("expected bad endpoint to be restored")
+	}
+
+	if endpoints := client.Endpoints(); len(endpoints) != 2 {
+		t.Errorf("expected both endpoints in rotation, got: %v", endpoints)
+	}
+}
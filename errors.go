@@ -0,0 +1,121 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNilResponse is returned by the helpers given a nil response.
+	ErrNilResponse = errors.New("response is nil")
+	// ErrNilBody is returned by the helpers given a response with a nil body.
+	ErrNilBody = errors.New("response body is nil")
+	// ErrDecode is matched by the *DecodeError returned when a response cannot be decoded.
+	ErrDecode = errors.New("decode failed")
+	// ErrBodyTooLarge is matched by errors of bodies exceeding the size a helper accepts.
+	ErrBodyTooLarge = errors.New("body too large")
+	// ErrRetriesExhausted is matched by errors of requests whose last retry failed with a retryable error.
+	ErrRetriesExhausted = errors.New("retries exhausted")
+	// ErrCanceled is matched by errors of requests whose context was canceled.
+	ErrCanceled = errors.New("canceled")
+	// ErrTimeout is matched by errors of requests that timed out, by deadline or network timeout.
+	ErrTimeout = errors.New("timeout")
+	// ErrCircuitOpen is matched by errors of requests whose last attempt was sent while every endpoint set with
+	// WithEndpoints was ejected.
+	ErrCircuitOpen = errors.New("circuit open")
+)
+
+// DecodeError is returned when a response, or a part of it, cannot be decoded.
+type DecodeError struct {
+	// Content is what failed to decode, such as "response" or "soap envelope".
+	Content string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %s: %v", e.Content, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is match ErrDecode.
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// Error is returned by Do for failed requests, wrapping the cause with the context of the request.
+// errors.Is matches ErrTimeout, ErrCanceled, ErrCircuitOpen, ErrRetriesExhausted, and ErrRateLimited for 429
+// responses as well as rate limiter rejections, on top of the errors wrapped by the cause.
+type Error struct {
+	Method string
+	URL    string
+	// Attempts is the number of attempts sent, zero when the request failed before being sent.
+	Attempts int
+	// Duration is how long the request took, including waits and retries.
+	Duration time.Duration
+	Err      error
+
+	circuitOpen      bool
+	retriesExhausted bool
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Method, e.URL, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is match ErrTimeout, ErrCanceled, ErrCircuitOpen, ErrRetriesExhausted and ErrRateLimited.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrCanceled:
+		return errors.Is(e.Err, context.Canceled)
+	case ErrRetriesExhausted:
+		return e.retriesExhausted
+	case ErrTimeout:
+		var netErr net.Error
+		return errors.Is(e.Err, context.DeadlineExceeded) || errors.As(e.Err, &netErr) && netErr.Timeout()
+	case ErrCircuitOpen:
+		return e.circuitOpen
+	case ErrRateLimited:
+		var statusErr *StatusError
+		return errors.As(e.Err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+type requestStatsKey struct{}
+
+// requestStats is filled in by the client while it sends a request, for the *Error it may return.
+type requestStats struct {
+	attempts         int
+	circuitOpen      bool
+	retriesExhausted bool
+	panic            *PanicError
+}
+
+// newError wraps the error of the request with its context.
+func newError(req *http.Request, stats *requestStats, duration time.Duration, err error) *Error {
+	return &Error{
+		Method:           req.Method,
+		URL:              req.URL.Redacted(),
+		Attempts:         stats.attempts,
+		Duration:         duration,
+		Err:              err,
+		circuitOpen:      stats.circuitOpen,
+		retriesExhausted: stats.retriesExhausted,
+	}
+}
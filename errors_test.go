@@ -0,0 +1,131 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(100 * time.Millisecond)
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/hangup":
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		opts     []clink.Option
+		path     string
+		timeout  time.Duration
+		canceled bool
+		target   error
+		attempts int
+	}{
+		{
+			name:     "timeout",
+			path:     "/slow",
+			timeout:  10 * time.Millisecond,
+			target:   clink.ErrTimeout,
+			attempts: 1,
+		},
+		{
+			name:     "canceled",
+			path:     "/",
+			canceled: true,
+			target:   clink.ErrCanceled,
+			attempts: 1,
+		},
+		{
+			name: "retries exhausted",
+			opts: []clink.Option{
+				clink.WithRetries(2, clink.RetryOnNetworkErrors),
+				clink.WithBackoff(func(int, *http.Response) time.Duration { return 0 }),
+			},
+			path:     "/hangup",
+			target:   clink.ErrRetriesExhausted,
+			attempts: 3,
+		},
+		{
+			name:     "rate limited",
+			opts:     []clink.Option{clink.WithStatusErrors()},
+			path:     "/limited",
+			target:   clink.ErrRateLimited,
+			attempts: 1,
+		},
+		{
+			name: "circuit open",
+			opts: []clink.Option{
+				clink.WithEndpoints(server.URL),
+				clink.WithHealthCheck(clink.HealthCheck{FailureThreshold: 1}),
+				clink.WithRetries(1, clink.RetryOnStatus(http.StatusInternalServerError)),
+				clink.WithBackoff(func(int, *http.Response) time.Duration { return 0 }),
+				clink.WithStatusErrors(),
+			},
+			path:     "/broken",
+			target:   clink.ErrCircuitOpen,
+			attempts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := clink.NewClient(append(tt.opts, clink.WithClient(server.Client()))...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if tt.canceled {
+				cancel()
+			}
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+tt.path, nil)
+			_, err := client.Do(req)
+
+			var clinkErr *clink.Error
+			if !errors.As(err, &clinkErr) {
+				t.Fatalf("expected a *clink.Error, got: %v", err)
+			}
+			if !errors.Is(err, tt.target) {
+				t.Errorf("expected error to match %v, got: %v", tt.target, err)
+			}
+			if clinkErr.Method != http.MethodGet || !strings.HasSuffix(clinkErr.URL, tt.path) ||
+				clinkErr.Attempts != tt.attempts || clinkErr.Duration <= 0 {
+				t.Errorf("unexpected error context: %+v", clinkErr)
+			}
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	var target map[string]string
+	err := clink.ResponseToJson(&http.Response{Body: io.NopCloser(strings.NewReader(`{"key": "value`))}, &target)
+
+	var decodeErr *clink.DecodeError
+	if !errors.As(err, &decodeErr) || !errors.Is(err, clink.ErrDecode) || decodeErr.Content != "response" {
+		t.Errorf("expected a *clink.DecodeError matching ErrDecode, got: %v", err)
+	}
+
+	if err := clink.ResponseToJson(nil, &target); !errors.Is(err, clink.ErrNilResponse) {
+		t.Errorf("expected ErrNilResponse, got: %v", err)
+	}
+}
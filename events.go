@@ -0,0 +1,104 @@
+package clink
+
+import (
+	"net/http"
+	"time"
+)
+
+// StateEventKind is the kind of a StateEvent.
+type StateEventKind int
+
+const (
+	// LimiterSaturated is emitted when requests start waiting for the rate limiter, or being rejected by it
+	// in non-blocking mode, after passing it without waiting.
+	LimiterSaturated StateEventKind = iota
+	// LimiterRecovered is emitted when a request passes the saturated rate limiter without waiting.
+	LimiterRecovered
+	// EndpointEjected is emitted when an endpoint set with WithEndpoints is ejected from rotation,
+	// opening its circuit.
+	EndpointEjected
+	// EndpointRestored is emitted when an ejected endpoint is back in rotation, closing its circuit.
+	EndpointRestored
+	// ProxyEjected is emitted when a proxy of the pool set with WithProxyPool is left out of the rotation.
+	ProxyEjected
+	// RetriesExhausted is emitted when the last retry of a request fails with a retryable outcome.
+	RetriesExhausted
+	// SLOBurning is emitted when an endpoint burns the error budget set with WithSLO too fast and becomes degraded.
+	SLOBurning
+	// SLORecovered is emitted when a degraded endpoint burns its error budget no faster than allowed again.
+	SLORecovered
+)
+
+// String returns the name of the event kind.
+func (k StateEventKind) String() string {
+	switch k {
+	case LimiterSaturated:
+		return "limiter_saturated"
+	case LimiterRecovered:
+		return "limiter_recovered"
+	case EndpointEjected:
+		return "endpoint_ejected"
+	case EndpointRestored:
+		return "endpoint_restored"
+	case ProxyEjected:
+		return "proxy_ejected"
+	case RetriesExhausted:
+		return "retries_exhausted"
+	case SLOBurning:
+		return "slo_burning"
+	case SLORecovered:
+		return "slo_recovered"
+	default:
+		return "unknown"
+	}
+}
+
+// StateEvent reports a change of state of the client explaining why outbound traffic slowed down or failed.
+type StateEvent struct {
+	Kind StateEventKind
+	// Request is the request that caused the event, nil for events caused by active health checks.
+	Request *http.Request
+	// Target is the URL of the endpoint or proxy of endpoint and proxy events, and the endpoint of SLO events.
+	Target string
+	// Wait is how long the request waited for the limiter, for limiter events.
+	Wait time.Duration
+	// Attempts is the number of attempts of the request, for RetriesExhausted events.
+	Attempts int
+	// Err is the error of the last attempt, if any.
+	Err error
+	// StatusCode is the status of the last response, if any.
+	StatusCode int
+}
+
+// WithStateHook sets a function called with the state events of the client: limiter saturation, endpoint and proxy
+// ejections, exhausted retries and SLO burns. It is called synchronously and must not block.
+func WithStateHook(hook func(StateEvent)) Option {
+	return func(c *Client) {
+		c.stateHook = hook
+	}
+}
+
+// emitState calls the state hook, if any.
+func (c *Client) emitState(event StateEvent) {
+	if c.stateHook != nil {
+		defer recordPanic(event.Request)
+		c.stateHook(event)
+	}
+}
+
+// reportLimiterState emits limiter events when requests start or stop waiting for the limiter.
+func (c *Client) reportLimiterState(req *http.Request, waited time.Duration, saturated bool) {
+	if c.stateHook == nil {
+		return
+	}
+
+	if c.limiterSaturated.Swap(saturated) == saturated {
+		return
+	}
+
+	kind := LimiterRecovered
+	if saturated {
+		kind = LimiterSaturated
+	}
+	c.emitState(StateEvent{Kind: kind, Request: req, Wait: waited})
+}
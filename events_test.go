@@ -0,0 +1,105 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+type stateEvents struct {
+	mu     sync.Mutex
+	events []clink.StateEvent
+}
+
+func (s *stateEvents) hook(event clink.StateEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *stateEvents) kinds() []clink.StateEventKind {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kinds := make([]clink.StateEventKind, 0, len(s.events))
+	for _, event := range s.events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	return kinds
+}
+
+func TestWithStateHook_Limiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	events := &stateEvents{}
+	client := clink.NewClient(
+		clink.WithClock(clock),
+		clink.WithLimiter(clink.NewLimiter(clink.FixedWindow, 1, time.Minute, 0)),
+		clink.WithNonBlockingRateLimit(),
+		clink.WithStateHook(events.hook),
+		clink.WithClient(server.Client()),
+	)
+
+	for i, advance := range []time.Duration{0, 0, 0, time.Minute} {
+		clock.Advance(advance)
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		} else if i == 0 || i == 3 {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	want := []clink.StateEventKind{clink.LimiterSaturated, clink.LimiterRecovered}
+	if got := events.kinds(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected events %v, got %v", want, got)
+	}
+}
+
+func TestWithStateHook_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	events := &stateEvents{}
+	client := clink.NewClient(
+		clink.WithEndpoints(server.URL),
+		clink.WithHealthCheck(clink.HealthCheck{FailureThreshold: 2}),
+		clink.WithRetryPolicy(clink.RetryPolicy{
+			MaxRetries:  2,
+			ShouldRetry: clink.RetryOnStatus(http.StatusServiceUnavailable),
+			Backoff:     func(int, *http.Response) time.Duration { return 0 },
+		}),
+		clink.WithStateHook(events.hook),
+		clink.WithClient(server.Client()),
+	)
+
+	resp, err := client.Get(server.URL + "/orders")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	want := []clink.StateEventKind{clink.EndpointEjected, clink.RetriesExhausted}
+	if got := events.kinds(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+
+	ejected, exhausted := events.events[0], events.events[1]
+	if ejected.Target != server.URL || ejected.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("unexpected ejection event %+v", ejected)
+	}
+	if exhausted.Attempts != 3 || exhausted.StatusCode != http.StatusServiceUnavailable || exhausted.Request == nil {
+		t.Errorf("unexpected exhaustion event %+v", exhausted)
+	}
+}
@@ -0,0 +1,100 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// everyJitter is the fraction of the interval by which periodic runs are randomly delayed,
+// so that many clients polling the same endpoint do not synchronize.
+const everyJitter = 0.1
+
+// PeriodicRunner sends a request periodically, see Every.
+type PeriodicRunner struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Every sends a request built by newRequest right away and then every interval, delayed by a random jitter of up
+// to a tenth of the interval, passing its response or error to handle. The response body is closed once handle returns.
+// Runs never overlap: ticks missed while a run is in progress are skipped. The requests are canceled and the runner
+// stops when it is stopped or the client is closed. Requests that newRequest fails to build are passed to handle as errors.
+// Panics of handle are recovered and dropped, the next runs going ahead. Nothing is run if the interval is not
+// positive, the runner returned being already stopped.
+func (c *Client) Every(interval time.Duration, newRequest func(ctx context.Context) (*http.Request, error), handle func(*http.Response, error)) *PeriodicRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &PeriodicRunner{cancel: cancel, done: make(chan struct{})}
+	if interval <= 0 {
+		cancel()
+		close(r.done)
+		return r
+	}
+	closing := c.closing()
+
+	c.wg.Add(2)
+	go func() {
+		defer c.wg.Done()
+
+		select {
+		case <-closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	go func() {
+		defer c.wg.Done()
+		defer close(r.done)
+
+		next := c.clock().Now()
+		for {
+			c.runPeriodic(ctx, newRequest, handle)
+
+			next = next.Add(interval)
+			now := c.clock().Now()
+			for !next.After(now) {
+				next = next.Add(interval)
+			}
+
+			delay := next.Sub(now) + time.Duration(c.random.Float64()*everyJitter*float64(interval))
+			if err := sleepContext(ctx, c.clock(), delay); err != nil {
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// runPeriodic sends one request of a periodic runner.
+func (c *Client) runPeriodic(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error), handle func(*http.Response, error)) {
+	req, err := newRequest(ctx)
+	if err != nil {
+		callPeriodicHandle(handle, nil, err)
+		return
+	}
+
+	resp, err := c.Do(req)
+	if ctx.Err() != nil && err != nil {
+		return
+	}
+	defer discardBody(resp)
+
+	callPeriodicHandle(handle, resp, err)
+}
+
+// callPeriodicHandle passes the result of a run to handle, recovering its panics, as there is no caller to return them to.
+func callPeriodicHandle(handle func(*http.Response, error), resp *http.Response, err error) {
+	defer func() { _ = recover() }()
+
+	handle(resp, err)
+}
+
+// Stop stops the runner, canceling the request in progress, and waits for it to return.
+func (r *PeriodicRunner) Stop() {
+	r.once.Do(r.cancel)
+	<-r.done
+}
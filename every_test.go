@@ -0,0 +1,132 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestClient_Every(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clinktest.NewClock(start)
+	client := clink.NewClient(clink.WithClock(clock), clink.WithClient(server.Client()))
+
+	handled := make(chan int, 10)
+	runner := client.Every(time.Minute, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/health", nil)
+	}, func(resp *http.Response, err error) {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			return
+		}
+		handled <- resp.StatusCode
+	})
+
+	for i := 0; i < 3; i++ {
+		if status := <-handled; status != http.StatusNoContent {
+			t.Errorf("unexpected status %d", status)
+		}
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Minute + 6*time.Second)
+	}
+
+	if status := <-handled; status != http.StatusNoContent || calls.Load() != 4 {
+		t.Errorf("expected 4 runs, got %d", calls.Load())
+	}
+
+	runner.Stop()
+	if err := client.Close(); err != nil {
+		t.Errorf("failed to close client: %v", err)
+	}
+}
+
+func TestClient_Every_Close(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	handled := atomic.Int32{}
+	client.Every(time.Hour, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	}, func(resp *http.Response, err error) {
+		handled.Add(1)
+	})
+
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		_ = client.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected closing the client to cancel the request in progress")
+	}
+
+	if handled.Load() != 0 {
+		t.Error("expected canceled runs not to be handled")
+	}
+}
+
+func TestClient_Every_HandlePanic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := clink.NewClient(clink.WithClock(clock), clink.WithClient(server.Client()))
+	defer client.Close()
+
+	handled := make(chan struct{}, 2)
+	runner := client.Every(time.Minute, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	}, func(resp *http.Response, err error) {
+		handled <- struct{}{}
+		panic("boom")
+	})
+	defer runner.Stop()
+
+	<-handled
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Minute)
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the runner to keep running after handle panicked")
+	}
+}
+
+func TestClient_Every_NonPositiveInterval(t *testing.T) {
+	client := clink.NewClient()
+	defer client.Close()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		runner := client.Every(interval, func(ctx context.Context) (*http.Request, error) {
+			t.Error("expected no request to be built")
+			return nil, nil
+		}, func(*http.Response, error) {})
+		runner.Stop()
+	}
+}
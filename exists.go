@@ -0,0 +1,120 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// existsConcurrency is the number of existence checks of Exists running at once.
+const existsConcurrency = 8
+
+// ExistsResult is the outcome of the existence check of a URL.
+type ExistsResult struct {
+	// Exists is true for 2xx responses, after redirects.
+	Exists bool
+	// Size is the size of the resource in bytes, -1 when unknown.
+	Size int64
+	ETag string
+	// StatusCode is the status of the final response, zero when the request failed.
+	StatusCode int
+	// Err is the error of failed requests. Missing resources are not errors.
+	Err error
+}
+
+// Exists checks whether the URLs exist with HEAD requests, 8 at once, and returns the result of each URL.
+// Servers rejecting HEAD with 405 or 501 are asked for the first byte with a ranged GET request instead.
+// The requests go through the client, so its headers, rate limiting and retries apply.
+func (c *Client) Exists(ctx context.Context, urls ...string) map[string]ExistsResult {
+	results := make(map[string]ExistsResult, len(urls))
+
+	queue := make(chan string)
+	go func() {
+		defer close(queue)
+		for _, u := range urls {
+			select {
+			case queue <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < min(existsConcurrency, len(urls)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				result := c.exists(ctx, u)
+
+				mu.Lock()
+				results[u] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, u := range urls {
+		if _, ok := results[u]; !ok {
+			results[u] = ExistsResult{Size: -1, Err: ctx.Err()}
+		}
+	}
+
+	return results
+}
+
+// exists checks whether the URL exists.
+func (c *Client) exists(ctx context.Context, url string) ExistsResult {
+	resp, err := c.head(ctx, url)
+	if err != nil {
+		return ExistsResult{Size: -1, Err: err}
+	}
+	discardBody(resp)
+
+	result := ExistsResult{
+		Exists:     resp.StatusCode >= 200 && resp.StatusCode < 300,
+		Size:       resp.ContentLength,
+		ETag:       resp.Header.Get("ETag"),
+		StatusCode: resp.StatusCode,
+	}
+
+	if resp.StatusCode == http.StatusPartialContent {
+		result.Size = -1
+		// Content-Range: bytes 0-0/1234
+		if _, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/"); ok {
+			if size, err := strconv.ParseInt(total, 10, 64); err == nil {
+				result.Size = size
+			}
+		}
+	}
+
+	return result
+}
+
+// head sends a HEAD request for the URL, or a GET request for the first byte of the resource when the server
+// rejects HEAD with 405 or 501.
+func (c *Client) head(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil || (resp.StatusCode != http.StatusMethodNotAllowed && resp.StatusCode != http.StatusNotImplemented) {
+		return resp, err
+	}
+	discardBody(resp)
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	return c.Do(req)
+}
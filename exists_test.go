@@ -0,0 +1,68 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/asset.js":
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", "10")
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			if r.Header.Get("Range") != "bytes=0-0" {
+				t.Errorf("expected a ranged request, got: %q", r.Header.Get("Range"))
+			}
+			w.Header().Set("Content-Range", "bytes 0-0/42")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write([]byte("x"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	tests := []struct {
+		url    string
+		exists bool
+		size   int64
+		etag   string
+		status int
+		err    bool
+	}{
+		{url: server.URL + "/asset.js", exists: true, size: 10, etag: `"v1"`, status: http.StatusOK},
+		{url: server.URL + "/no-head", exists: true, size: 42, status: http.StatusPartialContent},
+		{url: server.URL + "/missing", size: 19, status: http.StatusNotFound},
+		{url: "http://[::1", size: -1, err: true},
+	}
+
+	urls := make([]string, 0, len(tests))
+	for _, tt := range tests {
+		urls = append(urls, tt.url)
+	}
+
+	results := client.Exists(context.Background(), urls...)
+	if len(results) != len(tests) {
+		t.Fatalf("expected %d results, got: %v", len(tests), results)
+	}
+
+	for _, tt := range tests {
+		result := results[tt.url]
+		if result.Exists != tt.exists || result.Size != tt.size || result.ETag != tt.etag ||
+			result.StatusCode != tt.status || (result.Err != nil) != tt.err {
+			t.Errorf("unexpected result for %s: %+v", tt.url, result)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package clink
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrUnsafeArchivePath is returned when an archive entry would be extracted outside of the destination directory.
+	ErrUnsafeArchivePath = errors.New("unsafe archive path")
+	// ErrArchiveTooLarge is returned when an archive exceeds the limits of its ExtractOptions.
+	ErrArchiveTooLarge = errors.New("archive too large")
+	// ErrUnsupportedArchive is returned when a response is neither a zip, a tar nor a gzipped tar archive.
+	ErrUnsupportedArchive = errors.New("unsupported archive format")
+)
+
+// ExtractOptions limits the archives extracted by DownloadAndExtract. Zero limits are unlimited.
+type ExtractOptions struct {
+	// MaxFileSize is the maximum size of an extracted file.
+	MaxFileSize int64
+	// MaxTotalSize is the maximum size of all extracted files.
+	MaxTotalSize int64
+	// MaxFiles is the maximum number of entries of the archive.
+	MaxFiles int
+	// MaxArchiveSize is the maximum size of the downloaded archive. Zip archives, which are downloaded to a temporary
+	// file before being extracted, default to MaxTotalSize, so archives of uncompressed entries need a larger value.
+	MaxArchiveSize int64
+	// StripComponents removes that many leading path elements from the entries, as tar --strip-components does.
+	StripComponents int
+}
+
+// DownloadAndExtract downloads a zip, tar or gzipped tar archive and extracts it into destDir, which is created if needed.
+// The format is detected from the content of the response. Entries escaping destDir fail with ErrUnsafeArchivePath,
+// symbolic and hard links are skipped, and archives exceeding the limits of opts fail with ErrArchiveTooLarge.
+// Tar archives are extracted as they are downloaded, while zip archives are downloaded to a temporary file first.
+// It returns the paths of the extracted files.
+func (c *Client) DownloadAndExtract(ctx context.Context, url, destDir string, opts ExtractOptions) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newStatusError(req, resp)
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	x := &extractor{dest: destDir, opts: opts}
+	body := bufio.NewReader(limitArchive(resp.Body, opts.MaxArchiveSize))
+	magic, _ := body.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")), bytes.HasPrefix(magic, []byte("PK\x05\x06")):
+		err = x.zip(body)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(body); err == nil {
+			err = x.tar(gz)
+		}
+	default:
+		if header, _ := body.Peek(262); len(header) == 262 && string(header[257:262]) == "ustar" {
+			err = x.tar(body)
+		} else {
+			err = ErrUnsupportedArchive
+		}
+	}
+	if err != nil {
+		return x.files, fmt.Errorf("failed to extract archive: %w", err)
+	}
+
+	return x.files, nil
+}
+
+// extractor extracts archive entries into a directory, enforcing the limits of the options.
+type extractor struct {
+	dest    string
+	opts    ExtractOptions
+	entries int
+	total   int64
+	files   []string
+}
+
+// tar extracts a tar stream.
+func (x *extractor) tar(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = x.dir(header.Name)
+		case tar.TypeReg:
+			err = x.file(header.Name, header.FileInfo().Mode(), header.Size, tr)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// zip spools the archive to a temporary file, as zip archives are read from their end, and extracts it.
+func (x *extractor) zip(r io.Reader) error {
+	file, err := os.CreateTemp("", "clink-archive-*.zip")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	if x.opts.MaxArchiveSize <= 0 {
+		r = limitArchive(r, x.opts.MaxTotalSize)
+	}
+
+	size, err := io.Copy(file, r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(file, size)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range zr.File {
+		mode := entry.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.dir(entry.Name)
+		case mode.IsRegular():
+			var rc io.ReadCloser
+			if rc, err = entry.Open(); err == nil {
+				err = x.file(entry.Name, mode, int64(entry.UncompressedSize64), rc)
+				_ = rc.Close()
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// limitArchive returns a reader failing with ErrArchiveTooLarge once more than limit bytes are read from r.
+// A limit of zero or less is unlimited.
+func limitArchive(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+
+	return &archiveReader{r: r, remaining: limit, limit: limit}
+}
+
+type archiveReader struct {
+	r         io.Reader
+	remaining int64
+	limit     int64
+}
+
+func (a *archiveReader) Read(p []byte) (int, error) {
+	if a.remaining < 0 {
+		return 0, fmt.Errorf("%w: more than %d bytes downloaded", ErrArchiveTooLarge, a.limit)
+	}
+
+	// Reading one byte past the limit tells archives of exactly limit bytes apart from larger ones.
+	if int64(len(p)) > a.remaining+1 {
+		p = p[:a.remaining+1]
+	}
+	n, err := a.r.Read(p)
+	a.remaining -= int64(n)
+	if a.remaining < 0 {
+		return n + int(a.remaining), fmt.Errorf("%w: more than %d bytes downloaded", ErrArchiveTooLarge, a.limit)
+	}
+
+	return n, err
+}
+
+// target returns the path of the entry in the destination directory, or an empty path for entries stripped entirely.
+func (x *extractor) target(name string) (string, error) {
+	x.entries++
+	if x.opts.MaxFiles > 0 && x.entries > x.opts.MaxFiles {
+		return "", fmt.Errorf("%w: more than %d entries", ErrArchiveTooLarge, x.opts.MaxFiles)
+	}
+
+	name = strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsafeArchivePath, name)
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafeArchivePath, name)
+	}
+
+	parts := strings.Split(cleaned, "/")
+	if len(parts) <= x.opts.StripComponents || cleaned == "." {
+		return "", nil
+	}
+
+	return filepath.Join(x.dest, filepath.FromSlash(path.Join(parts[x.opts.StripComponents:]...))), nil
+}
+
+// dir creates the directory of an entry.
+func (x *extractor) dir(name string) error {
+	target, err := x.target(name)
+	if err != nil || target == "" {
+		return err
+	}
+
+	return os.MkdirAll(target, 0o755)
+}
+
+// file extracts a regular file entry of the given size, failing if more than the declared size is read.
+func (x *extractor) file(name string, mode fs.FileMode, size int64, r io.Reader) error {
+	target, err := x.target(name)
+	if err != nil || target == "" {
+		return err
+	}
+
+	if x.opts.MaxFileSize > 0 && size > x.opts.MaxFileSize {
+		return fmt.Errorf("%w: %s is %d bytes", ErrArchiveTooLarge, name, size)
+	}
+	if x.opts.MaxTotalSize > 0 && x.total+size > x.opts.MaxTotalSize {
+		return fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, x.opts.MaxTotalSize)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0o600)
+	if err != nil {
+		return err
+	}
+
+	written, err := io.Copy(file, io.LimitReader(r, size+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written > size {
+		return fmt.Errorf("%w: %s is larger than declared", ErrArchiveTooLarge, name)
+	}
+
+	x.total += written
+	x.files = append(x.files, target)
+
+	return nil
+}
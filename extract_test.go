@@ -0,0 +1,164 @@
+package clink_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type archiveEntry struct {
+	name    string
+	content string
+}
+
+func tarGz(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tw.Write([]byte(e.content))
+	}
+	_ = tw.WriteHeader(&tar.Header{Name: "release/link", Linkname: "/etc/passwd", Typeflag: tar.TypeSymlink})
+	_ = tw.Close()
+	_ = gz.Close()
+
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(e.content))
+	}
+	_ = zw.Close()
+
+	return buf.Bytes()
+}
+
+func TestClient_DownloadAndExtract(t *testing.T) {
+	release := []archiveEntry{{name: "release/bin/tool", content: "binary"}, {name: "release/README", content: "readme"}}
+
+	tests := []struct {
+		name      string
+		archive   func(*testing.T, []archiveEntry) []byte
+		entries   []archiveEntry
+		opts      clink.ExtractOptions
+		wantFiles map[string]string
+		wantErr   error
+	}{
+		{
+			name:      "tar.gz",
+			archive:   tarGz,
+			entries:   release,
+			wantFiles: map[string]string{"release/bin/tool": "binary", "release/README": "readme"},
+		},
+		{
+			name:      "zip with stripped components",
+			archive:   zipArchive,
+			entries:   release,
+			opts:      clink.ExtractOptions{StripComponents: 1},
+			wantFiles: map[string]string{"bin/tool": "binary", "README": "readme"},
+		},
+		{
+			name:    "path traversal",
+			archive: zipArchive,
+			entries: []archiveEntry{{name: "../../evil", content: "x"}},
+			wantErr: clink.ErrUnsafeArchivePath,
+		},
+		{
+			name:    "absolute path",
+			archive: tarGz,
+			entries: []archiveEntry{{name: "/etc/evil", content: "x"}},
+			wantErr: clink.ErrUnsafeArchivePath,
+		},
+		{
+			name:    "file too large",
+			archive: tarGz,
+			entries: release,
+			opts:    clink.ExtractOptions{MaxFileSize: 4},
+			wantErr: clink.ErrArchiveTooLarge,
+		},
+		{
+			name:    "too many files",
+			archive: zipArchive,
+			entries: release,
+			opts:    clink.ExtractOptions{MaxFiles: 1},
+			wantErr: clink.ErrArchiveTooLarge,
+		},
+		{
+			name:    "archive too large",
+			archive: tarGz,
+			entries: release,
+			opts:    clink.ExtractOptions{MaxArchiveSize: 16},
+			wantErr: clink.ErrArchiveTooLarge,
+		},
+		{
+			name:    "zip larger than total size",
+			archive: zipArchive,
+			entries: []archiveEntry{{name: "big", content: string(bytes.Repeat([]byte("x"), 1024))}},
+			opts:    clink.ExtractOptions{MaxTotalSize: 256},
+			wantErr: clink.ErrArchiveTooLarge,
+		},
+		{
+			name:    "not an archive",
+			archive: func(*testing.T, []archiveEntry) []byte { return []byte("plain text") },
+			wantErr: clink.ErrUnsupportedArchive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := tt.archive(t, tt.entries)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(archive)
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(clink.WithClient(server.Client()))
+			dest := filepath.Join(t.TempDir(), "out")
+
+			files, err := client.DownloadAndExtract(context.Background(), server.URL, dest, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(files) != len(tt.wantFiles) {
+				t.Errorf("expected %d files, got %v", len(tt.wantFiles), files)
+			}
+			for name, content := range tt.wantFiles {
+				data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(name)))
+				if err != nil || string(data) != content {
+					t.Errorf("expected %s to hold %q, got %q: %v", name, content, data, err)
+				}
+			}
+			if _, err := os.Lstat(filepath.Join(dest, "release", "link")); !os.IsNotExist(err) {
+				t.Error("expected symbolic links to be skipped")
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !fips && !boringcrypto
+
+package clink
+
+// fipsDefault enables FIPS mode by default in FIPS builds.
+const fipsDefault = false
@@ -2,4 +2,4 @@ module github.com/davesavic/clink
 
 go 1.21.4
 
-require golang.org/x/time v0.5.0 // indirect
+require golang.org/x/time v0.5.0
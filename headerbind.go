@@ -0,0 +1,169 @@
+package clink
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeHeaders sets the fields of the struct pointed to by target tagged `header:"Name"` from the response headers,
+// such as a pagination cursor or rate limit information:
+//
+//	type ListUsersResponse struct {
+//		Users      []User    `json:"users"`
+//		NextCursor string    `header:"X-Next-Cursor" json:"-"`
+//		Remaining  int       `header:"X-RateLimit-Remaining" json:"-"`
+//		Reset      time.Time `header:"X-RateLimit-Reset" json:"-"`
+//	}
+//
+// Fields of missing headers are left untouched, slices get every value of the header, and times are parsed as HTTP
+// dates, RFC 3339 or Unix seconds. Durations are parsed as seconds or Go durations. DoStruct binds the headers of the
+// response into its target along with the body.
+func DecodeHeaders(resp *http.Response, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("invalid header target %T: not a pointer to a struct", target)
+	}
+	v = v.Elem()
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("header")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setHeaderField(v.Field(i), values); err != nil {
+			return &DecodeError{Content: "header " + name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// hasHeaderFields reports whether target points to a struct with header tagged fields.
+func hasHeaderFields(target any) bool {
+	t := reflect.TypeOf(target)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.Elem().NumField(); i++ {
+		if _, ok := t.Elem().Field(i).Tag.Lookup("header"); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setHeaderField sets the field from the values of its header.
+func setHeaderField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setHeaderField(v.Elem(), values)
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setHeaderField(slice.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	value := strings.TrimSpace(values[0])
+
+	if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok && v.Type() != reflect.TypeOf(time.Time{}) {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch v.Interface().(type) {
+	case time.Time:
+		parsed, err := parseHeaderTime(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(parsed))
+		return nil
+	case time.Duration:
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			v.SetInt(int64(seconds * float64(time.Second)))
+			return nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(parsed)
+	case reflect.Slice:
+		v.SetBytes([]byte(value))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// parseHeaderTime parses an HTTP date, an RFC 3339 time or Unix seconds.
+func parseHeaderTime(value string) (time.Time, error) {
+	if t, err := http.ParseTime(value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+
+	return time.Unix(seconds, 0), nil
+}
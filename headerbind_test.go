@@ -0,0 +1,66 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+type listUsersRequest struct {
+	_ struct{} `clink:"GET /users"`
+}
+
+type listUsersResponse struct {
+	Users      []string      `json:"users"`
+	NextCursor string        `header:"X-Next-Cursor" json:"-"`
+	Remaining  *int          `header:"X-RateLimit-Remaining" json:"-"`
+	Reset      time.Time     `header:"X-RateLimit-Reset" json:"-"`
+	RetryAfter time.Duration `header:"Retry-After" json:"-"`
+	Links      []string      `header:"Link" json:"-"`
+	Missing    string        `header:"X-Missing" json:"-"`
+}
+
+func TestDecodeHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Next-Cursor", "abc")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Header().Set("Retry-After", "30")
+		w.Header().Add("Link", "</users?page=2>; rel=next")
+		w.Header().Add("Link", "</users?page=9>; rel=last")
+		_, _ = w.Write([]byte(`{"users":["ada","grace"]}`))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithBaseURL(server.URL), clink.WithClient(server.Client()))
+
+	got := listUsersResponse{Missing: "kept"}
+	if err := client.DoStruct(context.Background(), &listUsersRequest{}, &got); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	remaining := 42
+	want := listUsersResponse{
+		Users:      []string{"ada", "grace"},
+		NextCursor: "abc",
+		Remaining:  &remaining,
+		Reset:      time.Unix(1700000000, 0),
+		RetryAfter: 30 * time.Second,
+		Links:      []string{"</users?page=2>; rel=next", "</users?page=9>; rel=last"},
+		Missing:    "kept",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got: %+v", want, got)
+	}
+
+	resp := &http.Response{Header: http.Header{"X-Ratelimit-Remaining": {"many"}}}
+	if err := clink.DecodeHeaders(resp, &listUsersResponse{}); err == nil {
+		t.Error("expected an error for an invalid header value")
+	}
+}
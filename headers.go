@@ -0,0 +1,65 @@
+package clink
+
+import "net/http"
+
+// HeaderPrecedence decides whether client headers or request headers win when both set the same header.
+type HeaderPrecedence int
+
+const (
+	// RequestHeadersFirst keeps headers already set on the request, client headers only fill in missing ones.
+	RequestHeadersFirst HeaderPrecedence = iota
+	// ClientHeadersFirst overwrites headers set on the request with the client headers.
+	ClientHeadersFirst
+)
+
+// WithHeaderPrecedence sets whether client headers or request headers win when both set the same header.
+func WithHeaderPrecedence(precedence HeaderPrecedence) Option {
+	return func(c *Client) {
+		c.headerPrecedence = precedence
+	}
+}
+
+// WithForcedHeaders sets headers that always overwrite the headers set on the request, whatever the header precedence.
+func WithForcedHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		if c.forcedHeaders == nil {
+			c.forcedHeaders = make(map[string]string, len(headers))
+		}
+		for key, value := range headers {
+			c.forcedHeaders[key] = value
+		}
+	}
+}
+
+// WithDefaultContentType sets the Content-Type header of requests that have a body but no Content-Type.
+func WithDefaultContentType(contentType string) Option {
+	return func(c *Client) {
+		c.defaultContentType = contentType
+	}
+}
+
+// applyHeaders merges the client headers into the request headers.
+func (c *Client) applyHeaders(req *http.Request) {
+	if req.Header == nil {
+		req.Header = make(http.Header, len(c.Headers)+len(c.forcedHeaders))
+	}
+
+	for key, value := range requestConfigFrom(req).headers {
+		req.Header.Set(key, value)
+	}
+
+	for key, value := range c.Headers {
+		if c.headerPrecedence == RequestHeadersFirst && req.Header.Get(key) != "" {
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	for key, value := range c.forcedHeaders {
+		req.Header.Set(key, value)
+	}
+
+	if c.defaultContentType != "" && req.Body != nil && req.Body != http.NoBody && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", c.defaultContentType)
+	}
+}
@@ -0,0 +1,98 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestHeaderMerge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Key", r.Header.Get("key"))
+		w.Header().Set("X-Other", r.Header.Get("other"))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		resultFunc func(*http.Response) bool
+	}{
+		{
+			name: "request headers win by default",
+			opts: []clink.Option{clink.WithHeaders(map[string]string{"key": "client", "other": "client"})},
+			resultFunc: func(resp *http.Response) bool {
+				return resp.Header.Get("X-Key") == "request" && resp.Header.Get("X-Other") == "client"
+			},
+		},
+		{
+			name: "client headers win with client precedence",
+			opts: []clink.Option{
+				clink.WithHeader("key", "client"),
+				clink.WithHeaderPrecedence(clink.ClientHeadersFirst),
+			},
+			resultFunc: func(resp *http.Response) bool {
+				return resp.Header.Get("X-Key") == "client"
+			},
+		},
+		{
+			name: "forced headers always win",
+			opts: []clink.Option{
+				clink.WithHeader("other", "client"),
+				clink.WithForcedHeaders(map[string]string{"key": "forced", "other": "forced"}),
+			},
+			resultFunc: func(resp *http.Response) bool {
+				return resp.Header.Get("X-Key") == "forced" && resp.Header.Get("X-Other") == "forced"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append(tc.opts, clink.WithClient(server.Client()))
+			client := clink.NewClient(opts...)
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			req.Header.Set("key", "request")
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if !tc.resultFunc(resp) {
+				t.Errorf("unexpected headers: key=%q other=%q", resp.Header.Get("X-Key"), resp.Header.Get("X-Other"))
+			}
+		})
+	}
+}
+
+func TestClient_HeadersChangedAfterCreation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Echo", r.Header.Get("X-A"))
+	}))
+	defer server.Close()
+
+	clients := map[string]*clink.Client{
+		"changed after NewClient": clink.NewClient(clink.WithClient(server.Client())),
+		"struct literal":          {HttpClient: server.Client(), Headers: map[string]string{}},
+	}
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			client.Headers["X-A"] = "1"
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.Header.Get("X-Echo") != "1" {
+				t.Errorf("expected the client header to be sent, got %q", resp.Header.Get("X-Echo"))
+			}
+		})
+	}
+}
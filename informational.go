@@ -0,0 +1,54 @@
+package clink
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+)
+
+// InformationalHook is called for each informational (1xx) response received before the final response to a request,
+// such as 100 Continue or 103 Early Hints.
+type InformationalHook func(req *http.Request, code int, header http.Header)
+
+// WithInformationalHook adds a hook called for the informational responses of every request.
+// 100 Continue is only received for requests with an "Expect: 100-continue" header, when the transport has an ExpectContinueTimeout.
+func WithInformationalHook(hook InformationalHook) Option {
+	return func(c *Client) {
+		c.informationalHooks = append(c.informationalHooks, hook)
+	}
+}
+
+// WithEarlyHints adds a hook called with the links of the 103 Early Hints responses of every request,
+// resolved against the request URL, so that the resources they announce can be preconnected to or prefetched.
+func WithEarlyHints(hook func(req *http.Request, links Links)) Option {
+	return WithInformationalHook(func(req *http.Request, code int, header http.Header) {
+		if code == http.StatusEarlyHints {
+			hook(req, ResponseLinks(&http.Response{Header: header, Request: req}))
+		}
+	})
+}
+
+// traceInformational attaches the informational hooks of the client to the request.
+func (c *Client) traceInformational(req *http.Request) *http.Request {
+	if len(c.informationalHooks) == 0 {
+		return req
+	}
+
+	hooks := c.informationalHooks
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) (err error) {
+			defer func() {
+				if value := recover(); value != nil {
+					err = newPanicError(value)
+				}
+			}()
+
+			for _, hook := range hooks {
+				hook(req, code, http.Header(header))
+			}
+			return nil
+		},
+	}
+
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
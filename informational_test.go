@@ -0,0 +1,49 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_InformationalResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", "</style.css>; rel=preload; as=style")
+		w.Header().Add("Link", "<https://cdn.example.com>; rel=preconnect")
+		w.WriteHeader(http.StatusEarlyHints)
+		w.Header().Del("Link")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var codes []int
+	var links clink.Links
+	c := clink.NewClient(
+		clink.WithInformationalHook(func(req *http.Request, code int, header http.Header) {
+			codes = append(codes, code)
+		}),
+		clink.WithEarlyHints(func(req *http.Request, l clink.Links) {
+			links = l
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if len(codes) != 1 || codes[0] != http.StatusEarlyHints {
+		t.Errorf("expected a 103 informational response, got %v", codes)
+	}
+
+	if link, ok := links.Get("preload"); !ok || link.Href != server.URL+"/style.css" {
+		t.Errorf("expected the preload link resolved against the request, got %v", links)
+	}
+	if link, ok := links.Get("preconnect"); !ok || link.Href != "https://cdn.example.com" {
+		t.Errorf("expected the preconnect link, got %v", links)
+	}
+}
@@ -0,0 +1,194 @@
+package clink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents.
+const JSONAPIMediaType = "application/vnd.api+json"
+
+// JSONAPIErrorObject is an error object of a JSON:API document.
+type JSONAPIErrorObject struct {
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// JSONAPIError is returned when decoding a JSON:API document holding errors.
+type JSONAPIError struct {
+	Errors []JSONAPIErrorObject
+}
+
+// Error implements the error interface.
+func (e *JSONAPIError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, obj := range e.Errors {
+		message := obj.Title
+		if obj.Detail != "" {
+			message += ": " + obj.Detail
+		}
+		messages = append(messages, strings.TrimPrefix(message, ": "))
+	}
+
+	return "jsonapi: " + strings.Join(messages, "; ")
+}
+
+type jsonAPIResource struct {
+	Type          string                     `json:"type"`
+	ID            string                     `json:"id,omitempty"`
+	Attributes    map[string]json.RawMessage `json:"attributes,omitempty"`
+	Relationships map[string]struct {
+		Data json.RawMessage `json:"data"`
+	} `json:"relationships,omitempty"`
+}
+
+type jsonAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// DecodeJSONAPI decodes a JSON:API document into the target, which may be a struct or a slice for collections.
+// Each resource is flattened before being decoded with encoding/json: its id, type and attributes become top level fields,
+// and each relationship becomes a field holding the flattened included resources, or their identifiers when not included.
+// A document holding errors is returned as a *JSONAPIError.
+func DecodeJSONAPI[T any](response *http.Response, target *T) error {
+	var doc struct {
+		Data     json.RawMessage      `json:"data"`
+		Included []jsonAPIResource    `json:"included"`
+		Errors   []JSONAPIErrorObject `json:"errors"`
+	}
+	if err := ResponseToJson(response, &doc); err != nil {
+		return err
+	}
+
+	if len(doc.Errors) > 0 {
+		return &JSONAPIError{Errors: doc.Errors}
+	}
+
+	included := make(map[jsonAPIIdentifier]jsonAPIResource, len(doc.Included))
+	for _, r := range doc.Included {
+		included[jsonAPIIdentifier{Type: r.Type, ID: r.ID}] = r
+	}
+
+	var flat any
+	if trimmed := strings.TrimSpace(string(doc.Data)); strings.HasPrefix(trimmed, "[") {
+		var resources []jsonAPIResource
+		if err := json.Unmarshal(doc.Data, &resources); err != nil {
+			return &DecodeError{Content: "jsonapi data", Err: err}
+		}
+
+		items := make([]map[string]any, 0, len(resources))
+		for _, r := range resources {
+			items = append(items, flattenJSONAPI(r, included, 0))
+		}
+		flat = items
+	} else if trimmed != "" && trimmed != "null" {
+		var resource jsonAPIResource
+		if err := json.Unmarshal(doc.Data, &resource); err != nil {
+			return &DecodeError{Content: "jsonapi data", Err: err}
+		}
+		flat = flattenJSONAPI(resource, included, 0)
+	}
+
+	data, err := json.Marshal(flat)
+	if err != nil {
+		return fmt.Errorf("failed to flatten jsonapi data: %w", err)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return &DecodeError{Content: "jsonapi data", Err: err}
+	}
+
+	return nil
+}
+
+// maxJSONAPIDepth bounds how deep included resources are embedded, to stop on circular relationships.
+const maxJSONAPIDepth = 3
+
+// flattenJSONAPI flattens the resource into a single object, embedding its included relationships.
+func flattenJSONAPI(r jsonAPIResource, included map[jsonAPIIdentifier]jsonAPIResource, depth int) map[string]any {
+	flat := make(map[string]any, len(r.Attributes)+len(r.Relationships)+2)
+	for key, value := range r.Attributes {
+		flat[key] = value
+	}
+	flat["id"] = r.ID
+	flat["type"] = r.Type
+
+	resolve := func(id jsonAPIIdentifier) any {
+		if res, ok := included[id]; ok && depth < maxJSONAPIDepth {
+			return flattenJSONAPI(res, included, depth+1)
+		}
+		return map[string]any{"id": id.ID, "type": id.Type}
+	}
+
+	for name, rel := range r.Relationships {
+		var many []jsonAPIIdentifier
+		var one jsonAPIIdentifier
+		switch {
+		case len(rel.Data) == 0 || string(rel.Data) == "null":
+			flat[name] = nil
+		case json.Unmarshal(rel.Data, &many) == nil:
+			items := make([]any, 0, len(many))
+			for _, id := range many {
+				items = append(items, resolve(id))
+			}
+			flat[name] = items
+		case json.Unmarshal(rel.Data, &one) == nil && one.Type != "":
+			flat[name] = resolve(one)
+		default:
+			flat[name] = nil
+		}
+	}
+
+	return flat
+}
+
+// JSONAPIBody sets the body of the request to a JSON:API document holding v as a resource of the given type.
+// The "id" field of v becomes the resource id and its other fields become attributes.
+func JSONAPIBody(resourceType string, v any) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.body = func(c *Client, req *http.Request) error {
+			return c.setJSONBody(req, jsonAPIDocument{resourceType: resourceType, value: v}, JSONAPIMediaType)
+		}
+	}
+}
+
+// jsonAPIDocument encodes a flat value as a JSON:API document.
+type jsonAPIDocument struct {
+	resourceType string
+	value        any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d jsonAPIDocument) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(d.value)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, fmt.Errorf("jsonapi resource must encode to an object: %w", err)
+	}
+
+	resource := jsonAPIResource{Type: d.resourceType}
+	if id, ok := attributes["id"]; ok {
+		var s string
+		if json.Unmarshal(id, &s) != nil {
+			s = string(id)
+		}
+		resource.ID = s
+		delete(attributes, "id")
+	}
+	delete(attributes, "type")
+	resource.Attributes = attributes
+
+	return json.Marshal(struct {
+		Data jsonAPIResource `json:"data"`
+	}{Data: resource})
+}
@@ -0,0 +1,98 @@
+package clink_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type jsonAPIAuthor struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type jsonAPIArticle struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	Author   jsonAPIAuthor   `json:"author"`
+	Comments []jsonAPIAuthor `json:"comments"`
+}
+
+func TestDecodeJSONAPI(t *testing.T) {
+	document := `{
+		"data": [{
+			"type": "articles",
+			"id": "1",
+			"attributes": {"title": "JSON:API"},
+			"relationships": {
+				"author": {"data": {"type": "people", "id": "9"}},
+				"comments": {"data": [{"type": "comments", "id": "5"}]}
+			}
+		}],
+		"included": [{"type": "people", "id": "9", "attributes": {"name": "Dan"}}]
+	}`
+
+	var articles []jsonAPIArticle
+	err := clink.DecodeJSONAPI(&http.Response{Body: io.NopCloser(strings.NewReader(document))}, &articles)
+	if err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+
+	if len(articles) != 1 {
+		t.Fatalf("expected 1 article, got %d", len(articles))
+	}
+
+	article := articles[0]
+	if article.ID != "1" || article.Title != "JSON:API" || article.Author.Name != "Dan" ||
+		len(article.Comments) != 1 || article.Comments[0].ID != "5" {
+		t.Errorf("unexpected article: %+v", article)
+	}
+}
+
+func TestDecodeJSONAPIErrors(t *testing.T) {
+	document := `{"errors": [{"status": "422", "title": "Invalid Attribute", "detail": "title is required"}]}`
+
+	var article jsonAPIArticle
+	err := clink.DecodeJSONAPI(&http.Response{Body: io.NopCloser(strings.NewReader(document))}, &article)
+
+	var apiErr *clink.JSONAPIError
+	if !errors.As(err, &apiErr) || apiErr.Errors[0].Status != "422" {
+		t.Errorf("expected jsonapi error, got: %v", err)
+	}
+}
+
+func TestJSONAPIBody(t *testing.T) {
+	var body []byte
+	var contentType string
+	client := clink.NewClient(clink.WithClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ = io.ReadAll(req.Body)
+		contentType = req.Header.Get("Content-Type")
+		return &http.Response{StatusCode: http.StatusCreated, Body: http.NoBody}, nil
+	})}))
+
+	_, err := client.Post("http://localhost/articles", nil, clink.JSONAPIBody("articles", jsonAPIArticle{ID: "1", Title: "Hello"}))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	var doc struct {
+		Data struct {
+			Type       string         `json:"type"`
+			ID         string         `json:"id"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+
+	if contentType != clink.JSONAPIMediaType || doc.Data.Type != "articles" || doc.Data.ID != "1" ||
+		doc.Data.Attributes["title"] != "Hello" || doc.Data.Attributes["id"] != nil {
+		t.Errorf("unexpected document: %s (%s)", body, contentType)
+	}
+}
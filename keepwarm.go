@@ -0,0 +1,86 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithKeepWarm periodically sends a HEAD request to the given URL so that connections and TLS sessions to its host stay warm.
+// The requests bypass the rate limiter and retries, and stop when the client is closed. The option is ignored when
+// interval is not positive.
+func WithKeepWarm(url string, interval time.Duration) Option {
+	return func(c *Client) {
+		if interval <= 0 {
+			return
+		}
+
+		c.background = append(c.background, func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				_ = c.warm(ctx, url)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		})
+	}
+}
+
+// Preconnect resolves and connects to the given hosts, completing TLS handshakes, by sending a HEAD request
+// to the root of each of them concurrently, so that the first requests to them reuse warm connections.
+// Hosts are host names, optionally with a port, or URLs; host names are connected to over https.
+// The requests bypass the rate limiter and retries. The errors of the hosts that failed are joined.
+func (c *Client) Preconnect(ctx context.Context, hosts ...string) error {
+	errs := make([]error, len(hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+
+			target := host
+			if !strings.Contains(host, "://") {
+				target = "https://" + host
+			}
+
+			if err := c.warm(ctx, target+"/"); err != nil {
+				errs[i] = fmt.Errorf("failed to preconnect to %s: %w", host, err)
+			}
+		}(i, strings.TrimSuffix(host, "/"))
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// warm sends a HEAD request to the given URL, discarding the response.
+func (c *Client) warm(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+
+	c.applyHeaders(req)
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return nil
+}
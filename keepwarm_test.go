@@ -0,0 +1,102 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestKeepWarm(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			pings.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithKeepWarm(server.URL, 20*time.Millisecond),
+	)
+
+	time.Sleep(100 * time.Millisecond)
+	_ = client.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	count := pings.Load()
+	if count < 2 {
+		t.Errorf("expected at least 2 pings, got %d", count)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if pings.Load() != count {
+		t.Errorf("expected pings to stop after close")
+	}
+}
+
+func TestKeepWarm_InvalidInterval(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithKeepWarm(server.URL, 0))
+	time.Sleep(20 * time.Millisecond)
+	_ = client.Close()
+
+	if pings.Load() != 0 {
+		t.Errorf("expected no pings for a zero interval, got %d", pings.Load())
+	}
+}
+
+func TestClient_Preconnect(t *testing.T) {
+	var pings atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.URL.Path == "/" {
+			pings.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	var reused atomic.Int32
+	client := clink.NewClient(
+		clink.WithConnectionHooks(clink.ConnectionHooks{
+			Acquired: func(addr string, r bool, idle time.Duration) {
+				if r {
+					reused.Add(1)
+				}
+			},
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	if err := client.Preconnect(context.Background(), host); err != nil {
+		t.Fatalf("failed to preconnect: %v", err)
+	}
+	if pings.Load() != 1 {
+		t.Errorf("expected one preconnect request, got %d", pings.Load())
+	}
+
+	resp, err := client.Get(server.URL + "/data")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if reused.Load() != 1 {
+		t.Errorf("expected the request to reuse the preconnected connection")
+	}
+
+	if err := client.Preconnect(context.Background(), "http://127.0.0.1:1"); err == nil {
+		t.Errorf("expected an error for an unreachable host")
+	}
+}
@@ -0,0 +1,616 @@
+package clink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter delays requests to respect a rate limit. *rate.Limiter implements it.
+type Limiter interface {
+	// Wait blocks until a request may be sent or the context is done.
+	Wait(ctx context.Context) error
+}
+
+// WithLimiter sets the limiter the client waits for before each request, in place of the one set with WithRateLimit.
+func WithLimiter(limiter Limiter) Option {
+	return func(c *Client) {
+		c.Limiter = limiter
+	}
+}
+
+// TryLimiter is a Limiter that can admit a request without blocking.
+// The limiters created by NewLimiter and NewRedisLimiter implement it.
+type TryLimiter interface {
+	Limiter
+	// TryAcquire admits a request if it may be sent now, or returns how long it would have to wait.
+	TryAcquire() (wait time.Duration, ok bool)
+}
+
+// ErrRateLimited is matched by the *RateLimitError returned in non-blocking rate limit mode.
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError is returned instead of waiting for the limiter when WithNonBlockingRateLimit is set.
+type RateLimitError struct {
+	// Wait is how long the request would have had to wait.
+	Wait time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry in %v", e.Wait)
+}
+
+// Is makes errors.Is match ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// WithNonBlockingRateLimit makes requests that would wait for the limiter fail with a *RateLimitError instead.
+// Limiters that do not implement TryLimiter are still waited for.
+func WithNonBlockingRateLimit() Option {
+	return func(c *Client) {
+		c.rateLimitNoWait = true
+	}
+}
+
+// WithRateLimitWaitHook sets a function called with how long each request waited for the limiter.
+func WithRateLimitWaitHook(hook func(req *http.Request, waited time.Duration)) Option {
+	return func(c *Client) {
+		c.rateLimitWaitHook = hook
+	}
+}
+
+// waitLimiter waits for the limiter of the client, or fails if it would have to in non-blocking mode
+// or for low priority requests.
+func (c *Client) waitLimiter(req *http.Request) error {
+	limiter := c.limiter()
+	if limiter == nil {
+		return nil
+	}
+
+	shed := requestConfigFrom(req).priority < PriorityNormal
+	if try, ok := limiter.(TryLimiter); ok && (c.rateLimitNoWait || shed) {
+		if wait, ok := try.TryAcquire(); !ok {
+			c.reportLimiterState(req, 0, true)
+			if shed {
+				return ErrLoadShed
+			}
+			return &RateLimitError{Wait: wait}
+		}
+		c.reportLimiterWait(req, 0)
+		return nil
+	}
+
+	start := c.clock().Now()
+	if err := limiter.Wait(req.Context()); err != nil {
+		return fmt.Errorf("failed to wait for rate limiter: %w", err)
+	}
+	c.reportLimiterWait(req, c.clock().Now().Sub(start))
+
+	return nil
+}
+
+func (c *Client) reportLimiterWait(req *http.Request, waited time.Duration) {
+	if c.rateLimitWaitHook != nil {
+		func() {
+			defer recordPanic(req)
+			c.rateLimitWaitHook(req, waited)
+		}()
+	}
+	c.reportLimiterState(req, waited, waited > 0)
+}
+
+// limiter returns the limiter of the client, or nil when requests are not rate limited.
+func (c *Client) limiter() Limiter {
+	if c.Limiter != nil {
+		return c.Limiter
+	}
+
+	if c.RateLimiter != nil {
+		return &tokenBucket{Limiter: c.RateLimiter, clock: c.clock()}
+	}
+
+	return nil
+}
+
+// RateLimitAlgorithm selects how a limiter created with NewLimiter spaces requests.
+type RateLimitAlgorithm int
+
+const (
+	// TokenBucket allows bursts of up to burst requests, refilled at the configured rate.
+	TokenBucket RateLimitAlgorithm = iota
+	// LeakyBucket spaces requests evenly, never sending two within per/limit of each other.
+	LeakyBucket
+	// FixedWindow allows limit requests per window, the windows starting with their first request.
+	FixedWindow
+	// SlidingWindow allows limit requests within any period of length per.
+	SlidingWindow
+)
+
+// NewLimiter creates a limiter allowing limit requests per period using the given algorithm.
+// burst is the bucket size of TokenBucket limiters, defaulting to 1, and is ignored by the other algorithms.
+// A limit below 1 is treated as 1, and a per of zero or less does not limit requests.
+func NewLimiter(algorithm RateLimitAlgorithm, limit int, per time.Duration, burst int) Limiter {
+	limit = max(limit, 1)
+	per = max(per, 0)
+
+	switch algorithm {
+	case LeakyBucket:
+		return &leakyBucket{clock: SystemClock, interval: per / time.Duration(limit)}
+	case FixedWindow:
+		return &fixedWindow{clock: SystemClock, limit: limit, window: per}
+	case SlidingWindow:
+		return &slidingWindow{clock: SystemClock, limit: limit, window: per}
+	default:
+		if burst < 1 {
+			burst = 1
+		}
+		return &tokenBucket{Limiter: rate.NewLimiter(rate.Every(per/time.Duration(limit)), burst), clock: SystemClock}
+	}
+}
+
+// tokenBucket adapts a *rate.Limiter to TryLimiter, reading the time from its clock.
+type tokenBucket struct {
+	*rate.Limiter
+	clock Clock
+}
+
+// Wait implements Limiter.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if b.clock == SystemClock {
+		return b.Limiter.Wait(ctx)
+	}
+
+	now := b.clock.Now()
+	r := b.ReserveN(now, 1)
+	if !r.OK() {
+		return fmt.Errorf("rate: Wait(n=1) exceeds limiter's burst %d", b.Burst())
+	}
+
+	if err := sleepContext(ctx, b.clock, r.DelayFrom(now)); err != nil {
+		r.CancelAt(now)
+		return err
+	}
+
+	return nil
+}
+
+// TryAcquire implements TryLimiter.
+func (b *tokenBucket) TryAcquire() (time.Duration, bool) {
+	now := b.clock.Now()
+	r := b.ReserveN(now, 1)
+	if !r.OK() {
+		return 0, false
+	}
+
+	if wait := r.DelayFrom(now); wait > 0 {
+		r.CancelAt(now)
+		return wait, false
+	}
+
+	return 0, true
+}
+
+func (b *tokenBucket) setClock(clock Clock) {
+	b.clock = clock
+}
+
+type leakyBucket struct {
+	mu       sync.Mutex
+	clock    Clock
+	interval time.Duration
+	next     time.Time
+}
+
+// Wait implements Limiter.
+func (b *leakyBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := b.clock.Now()
+	slot := b.next
+	if slot.Before(now) {
+		slot = now
+	}
+	b.next = slot.Add(b.interval)
+	b.mu.Unlock()
+
+	return sleepContext(ctx, b.clock, slot.Sub(now))
+}
+
+// TryAcquire implements TryLimiter.
+func (b *leakyBucket) TryAcquire() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	if b.next.After(now) {
+		return b.next.Sub(now), false
+	}
+	b.next = now.Add(b.interval)
+
+	return 0, true
+}
+
+func (b *leakyBucket) setClock(clock Clock) {
+	b.clock = clock
+}
+
+type fixedWindow struct {
+	mu     sync.Mutex
+	clock  Clock
+	limit  int
+	window time.Duration
+	start  time.Time
+	count  int
+}
+
+// Wait implements Limiter.
+func (w *fixedWindow) Wait(ctx context.Context) error {
+	for {
+		wait, ok := w.TryAcquire()
+		if ok {
+			return nil
+		}
+
+		if err := sleepContext(ctx, w.clock, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// TryAcquire implements TryLimiter.
+func (w *fixedWindow) TryAcquire() (time.Duration, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.clock.Now()
+	if now.Sub(w.start) >= w.window {
+		w.start, w.count = now, 0
+	}
+
+	if w.count < w.limit {
+		w.count++
+		return 0, true
+	}
+
+	return w.start.Add(w.window).Sub(now), false
+}
+
+func (w *fixedWindow) setClock(clock Clock) {
+	w.clock = clock
+}
+
+type slidingWindow struct {
+	mu     sync.Mutex
+	clock  Clock
+	limit  int
+	window time.Duration
+	sent   []time.Time
+}
+
+// Wait implements Limiter.
+func (w *slidingWindow) Wait(ctx context.Context) error {
+	for {
+		wait, ok := w.TryAcquire()
+		if ok {
+			return nil
+		}
+
+		if err := sleepContext(ctx, w.clock, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// TryAcquire implements TryLimiter.
+func (w *slidingWindow) TryAcquire() (time.Duration, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.clock.Now()
+	expired := 0
+	for expired < len(w.sent) && now.Sub(w.sent[expired]) >= w.window {
+		expired++
+	}
+	w.sent = w.sent[expired:]
+
+	if len(w.sent) < w.limit {
+		w.sent = append(w.sent, now)
+		return 0, true
+	}
+
+	return w.sent[0].Add(w.window).Sub(now), false
+}
+
+func (w *slidingWindow) setClock(clock Clock) {
+	w.clock = clock
+}
+
+// redisWindowScript counts a request in the current window and returns the count and the time left in the window.
+const redisWindowScript = `local count = redis.call("INCR", KEYS[1])
+if count == 1 then redis.call("PEXPIRE", KEYS[1], ARGV[1]) end
+return {count, redis.call("PTTL", KEYS[1])}`
+
+// RedisLimiter is a Limiter allowing a number of requests per window across every process sharing a Redis key.
+// It talks to Redis directly and supports redis:// URLs with a username, a password and a database number.
+type RedisLimiter struct {
+	// FailOpen admits requests when Redis cannot be reached, instead of failing Wait with the error and making
+	// TryAcquire wait for a window. It must be set before the limiter is used.
+	FailOpen bool
+
+	addr     string
+	username string
+	password string
+	db       int
+	key      string
+	limit    int
+	window   time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// redisHandshakeTimeout bounds connecting to Redis, authenticating and selecting the database.
+const redisHandshakeTimeout = 5 * time.Second
+
+// NewRedisLimiter creates a limiter allowing limit requests per window, counted in Redis under key.
+// The port defaults to 6379, and limit must be positive.
+func NewRedisLimiter(redisURL, key string, limit int, window time.Duration) (*RedisLimiter, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("invalid redis limit %d", limit)
+	}
+
+	u, err := url.Parse(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis url: %w", err)
+	}
+
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("unsupported redis url scheme %q", u.Scheme)
+	}
+
+	l := &RedisLimiter{addr: u.Host, key: key, limit: limit, window: window}
+	if _, _, err := net.SplitHostPort(l.addr); err != nil {
+		l.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	if u.User != nil {
+		l.username = u.User.Username()
+		l.password, _ = u.User.Password()
+	}
+
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if l.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+	}
+
+	return l, nil
+}
+
+// Wait implements Limiter.
+func (l *RedisLimiter) Wait(ctx context.Context) error {
+	for {
+		wait, ok, err := l.acquire(ctx)
+		if err != nil {
+			if l.FailOpen && ctx.Err() == nil {
+				return nil
+			}
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		if err := sleepContext(ctx, SystemClock, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// TryAcquire implements TryLimiter. When Redis cannot be reached, requests are admitted if FailOpen is set,
+// and wait for a window otherwise.
+func (l *RedisLimiter) TryAcquire() (time.Duration, bool) {
+	wait, ok, err := l.acquire(context.Background())
+	if err != nil {
+		if l.FailOpen {
+			return 0, true
+		}
+		return l.window, false
+	}
+
+	return wait, ok
+}
+
+// acquire counts a request in the current window and reports whether it is within the limit,
+// or how long until the window ends.
+func (l *RedisLimiter) acquire(ctx context.Context) (time.Duration, bool, error) {
+	reply, err := l.do(ctx, "EVAL", redisWindowScript, "1", l.key, strconv.FormatInt(l.window.Milliseconds(), 10))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to acquire redis rate limit: %w", err)
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 2 {
+		return 0, false, fmt.Errorf("unexpected redis reply %v", reply)
+	}
+
+	if count, _ := values[0].(int64); count <= int64(l.limit) {
+		return 0, true, nil
+	}
+
+	wait, _ := values[1].(int64)
+	if wait <= 0 {
+		wait = 1
+	}
+
+	return time.Duration(wait) * time.Millisecond, false, nil
+}
+
+// Close closes the connection to Redis.
+func (l *RedisLimiter) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	err := l.conn.Close()
+	l.conn, l.rw = nil, nil
+
+	return err
+}
+
+// do sends a command to Redis, connecting first if needed, and returns its reply.
+// The connection is dropped on errors so that the next command reconnects.
+func (l *RedisLimiter) do(ctx context.Context, args ...string) (any, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		if err := l.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = l.conn.SetDeadline(deadline)
+	} else {
+		_ = l.conn.SetDeadline(time.Time{})
+	}
+
+	reply, err := l.command(args...)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			l.disconnect()
+		}
+		return nil, err
+	}
+
+	return reply, nil
+}
+
+// connect dials Redis, authenticates and selects the database, within redisHandshakeTimeout or the deadline of
+// the context if sooner, as the lock is held meanwhile.
+func (l *RedisLimiter) connect(ctx context.Context) error {
+	deadline := time.Now().Add(redisHandshakeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	conn, err := (&net.Dialer{Deadline: deadline}).DialContext(ctx, "tcp", l.addr)
+	if err != nil {
+		return err
+	}
+
+	l.conn = conn
+	l.rw = bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	_ = conn.SetDeadline(deadline)
+
+	if l.password != "" {
+		auth := []string{"AUTH", l.password}
+		if l.username != "" {
+			auth = []string{"AUTH", l.username, l.password}
+		}
+		if _, err := l.command(auth...); err != nil {
+			l.disconnect()
+			return err
+		}
+	}
+
+	if l.db != 0 {
+		if _, err := l.command("SELECT", strconv.Itoa(l.db)); err != nil {
+			l.disconnect()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// disconnect closes the connection so that the next command reconnects. The caller must hold l.mu.
+func (l *RedisLimiter) disconnect() {
+	_ = l.conn.Close()
+	l.conn, l.rw = nil, nil
+}
+
+// command writes the command in the Redis protocol and reads its reply.
+func (l *RedisLimiter) command(args ...string) (any, error) {
+	fmt.Fprintf(l.rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(l.rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if err := l.rw.Flush(); err != nil {
+		return nil, err
+	}
+
+	return readRedisReply(l.rw.Reader)
+}
+
+// redisError is an error reply from Redis.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// readRedisReply reads a reply in the Redis protocol.
+func readRedisReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("malformed redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]any, n)
+		for i := range values {
+			if values[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("malformed redis reply %q", line)
+}
@@ -0,0 +1,367 @@
+package clink_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+// fakeRedis serves the fixed window script of the redis limiter, counting requests per key.
+type fakeRedis struct {
+	mu       sync.Mutex
+	counts   map[string]int
+	commands []string
+	// password is the only password accepted by AUTH when set.
+	password string
+	// username is the only username accepted by AUTH when set.
+	username string
+}
+
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	f := &fakeRedis{counts: make(map[string]int)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	return f, ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+		args := make([]string, n)
+		for i := range args {
+			header, _ := r.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			arg := make([]byte, size+2)
+			if _, err := io.ReadFull(r, arg); err != nil {
+				return
+			}
+			args[i] = string(arg[:size])
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, args[0])
+		switch {
+		case args[0] == "AUTH" && f.password != "" && args[len(args)-1] != f.password,
+			args[0] == "AUTH" && f.username != "" && (len(args) != 3 || args[1] != f.username):
+			fmt.Fprint(conn, "-WRONGPASS invalid username-password pair\r\n")
+		case args[0] == "EVAL":
+			f.counts[args[3]]++
+			fmt.Fprintf(conn, "*2\r\n:%d\r\n:%d\r\n", f.counts[args[3]], 50)
+		default:
+			fmt.Fprint(conn, "+OK\r\n")
+		}
+		f.mu.Unlock()
+	}
+}
+
+func TestRedisLimiter(t *testing.T) {
+	redis, addr := startFakeRedis(t)
+
+	limiter, err := clink.NewRedisLimiter("redis://:secret@"+addr+"/2", "api", 2, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+	defer limiter.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithLimiter(limiter), clink.WithClient(server.Client()))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(server.URL); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := c.Do(req); err == nil {
+		t.Error("expected the third request to wait for the window")
+	}
+
+	redis.mu.Lock()
+	defer redis.mu.Unlock()
+	if strings.Join(redis.commands[:2], " ") != "AUTH SELECT" {
+		t.Errorf("expected the limiter to authenticate and select the database, got %v", redis.commands)
+	}
+}
+
+func TestRedisLimiter_AuthFailure(t *testing.T) {
+	redis, addr := startFakeRedis(t)
+	redis.mu.Lock()
+	redis.password = "secret"
+	redis.mu.Unlock()
+
+	limiter, err := clink.NewRedisLimiter("redis://:wrong@"+addr, "api", 2, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+	defer limiter.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2; i++ {
+			if err := limiter.Wait(context.Background()); err == nil {
+				t.Errorf("expected attempt %d to fail authentication", i)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected failed authentications not to block the limiter")
+	}
+}
+
+func TestRedisLimiter_Username(t *testing.T) {
+	redis, addr := startFakeRedis(t)
+	redis.mu.Lock()
+	redis.username, redis.password = "limiter", "secret"
+	redis.mu.Unlock()
+
+	limiter, err := clink.NewRedisLimiter("redis://limiter:secret@"+addr, "api", 2, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+	defer limiter.Close()
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("expected the limiter to authenticate with its username, got %v", err)
+	}
+}
+
+func TestRedisLimiter_HandshakeDeadline(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	limiter, err := clink.NewRedisLimiter("redis://:secret@"+ln.Addr().String(), "api", 2, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create limiter: %v", err)
+	}
+	defer limiter.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Wait(ctx) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the unanswered authentication to fail")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the authentication to respect the deadline of the context")
+	}
+}
+
+func TestRedisLimiter_FailOpen(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	tests := []struct {
+		name     string
+		failOpen bool
+	}{
+		{name: "fail closed", failOpen: false},
+		{name: "fail open", failOpen: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := clink.NewRedisLimiter("redis://"+addr, "api", 2, time.Second)
+			if err != nil {
+				t.Fatalf("failed to create limiter: %v", err)
+			}
+			limiter.FailOpen = tt.failOpen
+
+			if err := limiter.Wait(context.Background()); (err == nil) != tt.failOpen {
+				t.Errorf("expected Wait to admit the request: %v, got %v", tt.failOpen, err)
+			}
+
+			if wait, ok := limiter.TryAcquire(); ok != tt.failOpen || (!ok && wait != time.Second) {
+				t.Errorf("expected TryAcquire to admit the request: %v, got %v after %v", tt.failOpen, ok, wait)
+			}
+		})
+	}
+}
+
+func TestNewRedisLimiter_InvalidURL(t *testing.T) {
+	for _, u := range []string{"http://localhost", "redis://localhost/db"} {
+		if _, err := clink.NewRedisLimiter(u, "api", 1, time.Second); err == nil {
+			t.Errorf("expected an error for %q", u)
+		}
+	}
+
+	for _, limit := range []int{0, -1} {
+		if _, err := clink.NewRedisLimiter("redis://localhost", "api", limit, time.Second); err == nil {
+			t.Errorf("expected an error for limit %d", limit)
+		}
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		algorithm clink.RateLimitAlgorithm
+		burst     int
+		// minimum is the least time five requests may take with a limit of 2 per 40ms.
+		minimum time.Duration
+	}{
+		{name: "token bucket", algorithm: clink.TokenBucket, burst: 2, minimum: 60 * time.Millisecond},
+		{name: "leaky bucket", algorithm: clink.LeakyBucket, minimum: 80 * time.Millisecond},
+		{name: "fixed window", algorithm: clink.FixedWindow, minimum: 80 * time.Millisecond},
+		{name: "sliding window", algorithm: clink.SlidingWindow, minimum: 80 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := clink.NewLimiter(tc.algorithm, 2, 40*time.Millisecond, tc.burst)
+
+			start := time.Now()
+			for i := 0; i < 5; i++ {
+				if err := limiter.Wait(context.Background()); err != nil {
+					t.Fatalf("wait failed: %v", err)
+				}
+			}
+
+			if elapsed := time.Since(start); elapsed < tc.minimum-5*time.Millisecond {
+				t.Errorf("expected five requests to take at least %v, took %v", tc.minimum, elapsed)
+			}
+		})
+	}
+}
+
+func TestNewLimiter_InvalidLimit(t *testing.T) {
+	for _, algorithm := range []clink.RateLimitAlgorithm{clink.TokenBucket, clink.LeakyBucket, clink.FixedWindow, clink.SlidingWindow} {
+		for _, limit := range []int{0, -1} {
+			limiter := clink.NewLimiter(algorithm, limit, -time.Second, 0)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			for i := 0; i < 3; i++ {
+				if err := limiter.Wait(ctx); err != nil {
+					t.Errorf("expected algorithm %d with limit %d not to limit requests, got %v", algorithm, limit, err)
+				}
+			}
+			cancel()
+		}
+	}
+}
+
+func TestClient_NonBlockingRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var waits []time.Duration
+	c := clink.NewClient(
+		clink.WithRateLimit(60),
+		clink.WithNonBlockingRateLimit(),
+		clink.WithRateLimitWaitHook(func(req *http.Request, waited time.Duration) {
+			waits = append(waits, waited)
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	if _, err := c.Get(server.URL); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+
+	_, err := c.Get(server.URL)
+	if !errors.Is(err, clink.ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	var rateErr *clink.RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.Wait <= 0 || rateErr.Wait > time.Second {
+		t.Errorf("expected a wait of up to a second, got %v", err)
+	}
+
+	if len(waits) != 1 || waits[0] != 0 {
+		t.Errorf("expected the hook to report one request without wait, got %v", waits)
+	}
+}
+
+func TestClient_RateLimitWaitHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var waits []time.Duration
+	c := clink.NewClient(
+		clink.WithLimiter(clink.NewLimiter(clink.LeakyBucket, 1, 30*time.Millisecond, 0)),
+		clink.WithRateLimitWaitHook(func(req *http.Request, waited time.Duration) {
+			waits = append(waits, waited)
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(server.URL); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	if len(waits) != 2 || waits[1] < 20*time.Millisecond {
+		t.Errorf("expected the second request to wait, got %v", waits)
+	}
+}
@@ -0,0 +1,318 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxLinkCheckPage is the size of the largest page parsed for links by the LinkChecker.
+const maxLinkCheckPage = 5 << 20
+
+// LinkStatus classifies the outcome of the check of a link.
+type LinkStatus int
+
+const (
+	// LinkOK is a link answered with a 2xx status.
+	LinkOK LinkStatus = iota
+	// LinkRedirected is a link answered with a 2xx status after redirects.
+	LinkRedirected
+	// LinkBroken is a link answered with a 4xx status, or with a redirect that could not be followed.
+	LinkBroken
+	// LinkServerError is a link answered with a 5xx status.
+	LinkServerError
+	// LinkUnreachable is a link whose request failed, such as on DNS, connection or TLS errors and timeouts.
+	LinkUnreachable
+	// LinkDisallowed is a link disallowed by robots.txt, with WithRobots.
+	LinkDisallowed
+)
+
+// String returns the name of the link status.
+func (s LinkStatus) String() string {
+	switch s {
+	case LinkOK:
+		return "ok"
+	case LinkRedirected:
+		return "redirected"
+	case LinkBroken:
+		return "broken"
+	case LinkServerError:
+		return "server_error"
+	case LinkUnreachable:
+		return "unreachable"
+	case LinkDisallowed:
+		return "disallowed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so that reports encode statuses by name.
+func (s LinkStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// LinkResult is the outcome of the check of a link.
+type LinkResult struct {
+	URL    string     `json:"url"`
+	Status LinkStatus `json:"status"`
+	// StatusCode is the status of the final response, zero when the request failed.
+	StatusCode int `json:"status_code,omitempty"`
+	// FinalURL is the URL the link redirects to, if it does.
+	FinalURL string `json:"final_url,omitempty"`
+	// Sources are the pages linking to the URL, empty for the URLs the check started from.
+	Sources []string `json:"sources,omitempty"`
+	// Error is the error of failed requests.
+	Error string `json:"error,omitempty"`
+}
+
+// OK reports whether the link works, possibly after redirects.
+func (r LinkResult) OK() bool {
+	return r.Status == LinkOK || r.Status == LinkRedirected
+}
+
+// LinkReport is the outcome of a link check, to be encoded as JSON or inspected.
+type LinkReport struct {
+	// Results are the results of every checked URL, sorted by URL.
+	Results []LinkResult `json:"results"`
+}
+
+// Broken returns the results of the links that do not work.
+func (r *LinkReport) Broken() []LinkResult {
+	var broken []LinkResult
+	for _, result := range r.Results {
+		if !result.OK() {
+			broken = append(broken, result)
+		}
+	}
+
+	return broken
+}
+
+// LinkChecker checks the links of web pages. The pages and links are requested through its client, so its
+// rate limiting, retries and, with WithRobots, robots.txt rules and crawl delays apply.
+type LinkChecker struct {
+	client *Client
+
+	// MaxDepth is how many levels of links are followed from the start pages: pages on the hosts of the start URLs
+	// found up to MaxDepth links away are parsed for more links to check. Zero only checks the links of the start pages.
+	MaxDepth int
+	// Concurrency is the number of links checked at once. Defaults to 8.
+	Concurrency int
+	// OnResult is called with the result of each link as soon as it is checked, such as to report progress.
+	// It is called concurrently, and the sources of the result may not be complete yet.
+	OnResult func(LinkResult)
+}
+
+// NewLinkChecker returns a link checker sending its requests through the client.
+func NewLinkChecker(c *Client) *LinkChecker {
+	return &LinkChecker{client: c}
+}
+
+// linkPattern matches the URL attributes of the elements linking to other resources.
+var linkPattern = regexp.MustCompile(`(?is)<(?:a|area|link|img|script|iframe|source|video|audio|embed)\b[^>]*?\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+// Check checks the URLs and the links found on them, and returns the report of every URL checked.
+// Pages are fetched with GET requests and parsed when they are HTML documents, their links being resolved against
+// their final URL. Other links are checked with HEAD requests, or ranged GET requests when servers reject HEAD.
+// It only fails when the context is done.
+func (lc *LinkChecker) Check(ctx context.Context, urls ...string) (*LinkReport, error) {
+	hosts := make(map[string]bool)
+	results := make(map[string]*LinkResult)
+
+	var level []string
+	for _, u := range urls {
+		if _, ok := results[u]; ok {
+			continue
+		}
+		results[u] = &LinkResult{URL: u}
+		level = append(level, u)
+		if parsed, err := url.Parse(u); err == nil {
+			hosts[parsed.Host] = true
+		}
+	}
+
+	for depth := 0; len(level) > 0; depth++ {
+		// Pages up to MaxDepth are parsed; the links found on the deepest ones are checked without being parsed.
+		parse := func(u string) bool {
+			parsed, err := url.Parse(u)
+			return depth <= lc.MaxDepth && err == nil && hosts[parsed.Host]
+		}
+
+		found := lc.checkLevel(ctx, level, parse, results)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		level = nil
+		for _, link := range found {
+			result, ok := results[link.url]
+			if !ok {
+				result = &LinkResult{URL: link.url}
+				results[link.url] = result
+				level = append(level, link.url)
+			}
+			if !slices.Contains(result.Sources, link.source) {
+				result.Sources = append(result.Sources, link.source)
+			}
+		}
+	}
+
+	report := &LinkReport{Results: make([]LinkResult, 0, len(results))}
+	for _, result := range results {
+		sort.Strings(result.Sources)
+		report.Results = append(report.Results, *result)
+	}
+	sort.Slice(report.Results, func(i, j int) bool { return report.Results[i].URL < report.Results[j].URL })
+
+	return report, nil
+}
+
+// foundLink is a link found on a page.
+type foundLink struct {
+	url    string
+	source string
+}
+
+// checkLevel checks the URLs concurrently, parsing the pages for which parse is true, and returns the links found.
+func (lc *LinkChecker) checkLevel(ctx context.Context, urls []string, parse func(string) bool, results map[string]*LinkResult) []foundLink {
+	concurrency := lc.Concurrency
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+
+	queue := make(chan string)
+	go func() {
+		defer close(queue)
+		for _, u := range urls {
+			select {
+			case queue <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var found []foundLink
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(urls)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				mu.Lock()
+				result := results[u]
+				mu.Unlock()
+
+				links := lc.checkLink(ctx, result, parse(u))
+
+				mu.Lock()
+				for _, link := range links {
+					found = append(found, foundLink{url: link, source: u})
+				}
+				mu.Unlock()
+
+				if lc.OnResult != nil {
+					lc.OnResult(*result)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return found
+}
+
+// checkLink checks the link, filling in its result, and returns the links of the page when parse is true
+// and the link is an HTML page.
+func (lc *LinkChecker) checkLink(ctx context.Context, result *LinkResult, parse bool) []string {
+	resp, err := lc.fetch(ctx, result.URL, parse)
+	if err != nil {
+		result.Status, result.Error = LinkUnreachable, err.Error()
+		if errors.Is(err, ErrDisallowedByRobots) {
+			result.Status = LinkDisallowed
+		}
+		return nil
+	}
+	defer discardBody(resp)
+
+	result.StatusCode = resp.StatusCode
+	if final := resp.Request.URL.String(); final != result.URL {
+		result.FinalURL = final
+	}
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		result.Status = LinkServerError
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		result.Status = LinkBroken
+	case result.FinalURL != "":
+		result.Status = LinkRedirected
+	default:
+		result.Status = LinkOK
+	}
+
+	if !parse || !result.OK() {
+		return nil
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" {
+		return nil
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxLinkCheckPage))
+	if err != nil {
+		return nil
+	}
+
+	return extractLinks(resp.Request.URL, string(page))
+}
+
+// fetch gets the page, or checks the link with Client.head when it is not to be parsed.
+func (lc *LinkChecker) fetch(ctx context.Context, link string, page bool) (*http.Response, error) {
+	if !page {
+		return lc.client.head(ctx, link)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return lc.client.Do(req)
+}
+
+// extractLinks returns the absolute http and https URLs linked from the HTML page, without fragments.
+func extractLinks(base *url.URL, page string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, match := range linkPattern.FindAllStringSubmatch(page, -1) {
+		href := strings.TrimSpace(match[1] + match[2] + match[3])
+		if href == "" || strings.HasPrefix(href, "#") {
+			continue
+		}
+
+		u, err := base.Parse(strings.ReplaceAll(href, "&amp;", "&"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		u.Fragment = ""
+
+		if link := u.String(); !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
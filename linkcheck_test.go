@@ -0,0 +1,116 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestLinkChecker(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><head><link rel="stylesheet" href='/style.css'></head><body>
+			<a href="/about#team">About</a> <a href="/missing">Missing</a> <a href=/old>Old</a>
+			<a href="/error">Error</a> <a href="/private/page">Private</a> <a href="` + closed.URL + `/">Down</a>
+			<a href="mailto:team@example.com">Mail</a> <a href="#top">Top</a></body></html>`))
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<a href="/deep">Deep</a>`))
+	})
+	mux.HandleFunc("/deep", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/style.css", func(w http.ResponseWriter, r *http.Request) {})
+	mux.Handle("/old", http.RedirectHandler("/about", http.StatusMovedPermanently))
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		opts     []clink.Option
+		want     map[string]clink.LinkStatus
+	}{
+		{
+			name: "start page",
+			want: map[string]clink.LinkStatus{
+				"/": clink.LinkOK, "/about": clink.LinkOK, "/style.css": clink.LinkOK, "/missing": clink.LinkBroken,
+				"/old": clink.LinkRedirected, "/error": clink.LinkServerError, "/private/page": clink.LinkBroken,
+				closed.URL + "/": clink.LinkUnreachable,
+			},
+		},
+		{
+			name:     "followed links",
+			maxDepth: 1,
+			want: map[string]clink.LinkStatus{
+				"/": clink.LinkOK, "/about": clink.LinkOK, "/style.css": clink.LinkOK, "/missing": clink.LinkBroken,
+				"/old": clink.LinkRedirected, "/error": clink.LinkServerError, "/private/page": clink.LinkBroken,
+				closed.URL + "/": clink.LinkUnreachable, "/deep": clink.LinkOK,
+			},
+		},
+		{
+			name: "robots",
+			opts: []clink.Option{clink.WithRobots("linkbot")},
+			want: map[string]clink.LinkStatus{
+				"/": clink.LinkOK, "/about": clink.LinkOK, "/style.css": clink.LinkOK, "/missing": clink.LinkBroken,
+				"/old": clink.LinkRedirected, "/error": clink.LinkServerError, "/private/page": clink.LinkDisallowed,
+				closed.URL + "/": clink.LinkDisallowed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := clink.NewClient(append(tt.opts, clink.WithClient(server.Client()))...)
+			checker := clink.NewLinkChecker(client)
+			checker.MaxDepth = tt.maxDepth
+
+			report, err := checker.Check(context.Background(), server.URL+"/")
+			if err != nil {
+				t.Fatalf("failed to check links: %v", err)
+			}
+
+			got := make(map[string]clink.LinkStatus)
+			for _, result := range report.Results {
+				got[strings.TrimPrefix(result.URL, server.URL)] = result.Status
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("expected %d results, got: %v", len(tt.want), got)
+			}
+			for link, status := range tt.want {
+				if got[link] != status {
+					t.Errorf("expected %s to be %s, got: %s", link, status, got[link])
+				}
+			}
+
+			if len(report.Broken()) != 4 {
+				t.Errorf("expected 4 broken links, got: %+v", report.Broken())
+			}
+		})
+	}
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+	report, _ := clink.NewLinkChecker(client).Check(context.Background(), server.URL+"/")
+	data, _ := json.Marshal(report)
+	if !strings.Contains(string(data), `"url":"`+server.URL+`/old","status":"redirected","status_code":200,"final_url":"`+server.URL+`/about","sources":["`+server.URL+`/"]`) {
+		t.Errorf("unexpected json report: %s", data)
+	}
+}
@@ -0,0 +1,189 @@
+package clink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Link is a hypermedia link to a related resource.
+type Link struct {
+	Href      string `json:"href"`
+	Rel       string `json:"rel,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+// Links holds links keyed by their relation.
+type Links map[string][]Link
+
+// Get returns the first link with the given relation.
+func (l Links) Get(rel string) (Link, bool) {
+	if links := l[rel]; len(links) > 0 {
+		return links[0], true
+	}
+
+	return Link{}, false
+}
+
+// Links implements Linker so that links can be followed directly.
+func (l Links) Links() Links {
+	return l
+}
+
+// Resolve returns a copy of the links with relative hrefs resolved against base.
+func (l Links) Resolve(base *url.URL) Links {
+	resolved := make(Links, len(l))
+	for rel, links := range l {
+		for _, link := range links {
+			if u, err := url.Parse(link.Href); err == nil && !link.Templated {
+				link.Href = base.ResolveReference(u).String()
+			}
+			resolved[rel] = append(resolved[rel], link)
+		}
+	}
+
+	return resolved
+}
+
+// Linker is implemented by decoded responses exposing hypermedia links, such as structs embedding HAL.
+type Linker interface {
+	Links() Links
+}
+
+// HAL decodes the "_links" of HAL documents and the "links" array of Spring HATEOAS documents.
+// Embed it in response structs to get a Links accessor once decoded.
+type HAL struct {
+	HALLinks    map[string]halLinks `json:"_links,omitempty"`
+	SpringLinks []Link              `json:"links,omitempty"`
+}
+
+// Links returns the links of the decoded document.
+func (h HAL) Links() Links {
+	links := make(Links, len(h.HALLinks)+len(h.SpringLinks))
+	for rel, l := range h.HALLinks {
+		for _, link := range l {
+			link.Rel = rel
+			links[rel] = append(links[rel], link)
+		}
+	}
+
+	for _, link := range h.SpringLinks {
+		links[link.Rel] = append(links[link.Rel], link)
+	}
+
+	return links
+}
+
+// halLinks decodes a HAL link relation, which holds either a single link object or an array of them.
+type halLinks []Link
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *halLinks) UnmarshalJSON(data []byte) error {
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		return json.Unmarshal(data, (*[]Link)(l))
+	}
+
+	var link Link
+	if err := json.Unmarshal(data, &link); err != nil {
+		return err
+	}
+	*l = halLinks{link}
+
+	return nil
+}
+
+var linkHeaderParam = regexp.MustCompile(`;\s*([a-zA-Z*]+)\s*=\s*(?:"([^"]*)"|([^;,\s]*))`)
+
+// ResponseLinks returns the links of the Link header (RFC 8288) of the response, resolved against the request URL.
+func ResponseLinks(resp *http.Response) Links {
+	links := make(Links)
+	if resp == nil {
+		return links
+	}
+
+	for _, header := range resp.Header.Values("Link") {
+		for _, value := range splitLinkHeader(header) {
+			start, end := strings.Index(value, "<"), strings.Index(value, ">")
+			if start < 0 || end < start {
+				continue
+			}
+
+			link := Link{Href: strings.TrimSpace(value[start+1 : end])}
+			for _, m := range linkHeaderParam.FindAllStringSubmatch(value[end+1:], -1) {
+				param := m[2] + m[3]
+				switch strings.ToLower(m[1]) {
+				case "rel":
+					link.Rel = param
+				case "type":
+					link.Type = param
+				case "title":
+					link.Title = param
+				}
+			}
+
+			for _, rel := range strings.Fields(link.Rel) {
+				l := link
+				l.Rel = rel
+				links[rel] = append(links[rel], l)
+			}
+		}
+	}
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		links = links.Resolve(resp.Request.URL)
+	}
+
+	return links
+}
+
+// splitLinkHeader splits a Link header value into its links, ignoring commas within quotes and brackets.
+func splitLinkHeader(header string) []string {
+	var values []string
+	var quoted, bracketed bool
+	start := 0
+	for i, r := range header {
+		switch {
+		case r == '"' && !bracketed:
+			quoted = !quoted
+		case r == '<' && !quoted:
+			bracketed = true
+		case r == '>' && !quoted:
+			bracketed = false
+		case r == ',' && !quoted && !bracketed:
+			values = append(values, header[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(values, header[start:])
+}
+
+var uriTemplateExpression = regexp.MustCompile(`\{[^}]*\}`)
+
+// Follow sends a GET request to the first link with the given relation.
+// Templated links are followed with their template expressions removed.
+func (c *Client) Follow(ctx context.Context, from Linker, rel string, opts ...RequestOption) (*http.Response, error) {
+	link, ok := from.Links().Get(rel)
+	if !ok {
+		return nil, fmt.Errorf("no link with relation %q", rel)
+	}
+
+	href := link.Href
+	if link.Templated {
+		href = uriTemplateExpression.ReplaceAllString(href, "")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.Do(WithRequestOptions(req, opts...))
+}
@@ -0,0 +1,107 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestResponseLinks(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos?page=1", nil)
+	resp := &http.Response{
+		Request: req,
+		Header: http.Header{"Link": {
+			`<https://api.github.com/repos?page=2>; rel="next", </repos?page=5>; rel="last"; title="a, b"`,
+		}},
+	}
+
+	links := clink.ResponseLinks(resp)
+
+	if next, ok := links.Get("next"); !ok || next.Href != "https://api.github.com/repos?page=2" {
+		t.Errorf("unexpected next link: %+v", next)
+	}
+
+	if last, ok := links.Get("last"); !ok || last.Href != "https://api.github.com/repos?page=5" || last.Title != "a, b" {
+		t.Errorf("unexpected last link: %+v", last)
+	}
+}
+
+type halOrder struct {
+	clink.HAL
+	Total int `json:"total"`
+}
+
+func TestHALFollow(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders/1":
+			_, _ = w.Write([]byte(`{"total": 30, "_links": {
+				"self": {"href": "` + server.URL + `/orders/1"},
+				"customer": {"href": "` + server.URL + `/customers/{id}", "templated": true},
+				"items": [{"href": "/items/1"}, {"href": "/items/2"}]
+			}}`))
+		default:
+			_, _ = w.Write([]byte(r.URL.Path))
+		}
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := client.Get(server.URL + "/orders/1")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	var order halOrder
+	if err := clink.ResponseToJson(resp, &order); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if order.Total != 30 || len(order.Links()["items"]) != 2 {
+		t.Errorf("unexpected order: %+v", order)
+	}
+
+	resp, err = client.Follow(context.Background(), order, "customer")
+	if err != nil {
+		t.Fatalf("failed to follow link: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "/customers/" {
+		t.Errorf("expected templated link to be followed, got: %s", body)
+	}
+
+	base, _ := url.Parse(server.URL)
+	resp, err = client.Follow(context.Background(), order.Links().Resolve(base), "items")
+	if err != nil {
+		t.Fatalf("failed to follow link: %v", err)
+	}
+
+	body, _ = io.ReadAll(resp.Body)
+	if string(body) != "/items/1" {
+		t.Errorf("expected relative link to be resolved, got: %s", body)
+	}
+
+	if _, err := client.Follow(context.Background(), order, "missing"); err == nil {
+		t.Errorf("expected error for missing relation")
+	}
+}
+
+func TestSpringLinks(t *testing.T) {
+	var doc struct {
+		clink.HAL
+	}
+	_ = json.Unmarshal([]byte(`{"links": [{"rel": "self", "href": "/a"}]}`), &doc)
+
+	if self, ok := doc.Links().Get("self"); !ok || self.Href != "/a" {
+		t.Errorf("unexpected links: %+v", doc.Links())
+	}
+}
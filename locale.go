@@ -0,0 +1,75 @@
+package clink
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithLocale sets the Accept-Language header of requests from the BCP 47 language tags, in order of preference,
+// such as WithLocale("fr-CA", "en"). Each tag is followed by its base language when it has a region, and
+// decreasing quality values are given to every language after the first.
+func WithLocale(tags ...string) Option {
+	return func(c *Client) {
+		if value := acceptLanguage(tags); value != "" {
+			c.Headers["Accept-Language"] = value
+		}
+	}
+}
+
+// WithTimeZone sets the Time-Zone header of requests to the IANA name of the location, such as Europe/Paris,
+// for APIs rendering dates in the time zone of the caller.
+func WithTimeZone(location *time.Location) Option {
+	return func(c *Client) {
+		if location != nil {
+			c.Headers["Time-Zone"] = location.String()
+		}
+	}
+}
+
+// ContentLanguage returns the language tags of the Content-Language header of the response, if any.
+func ContentLanguage(resp *http.Response) []string {
+	if resp == nil {
+		return nil
+	}
+
+	var tags []string
+	for _, value := range resp.Header.Values("Content-Language") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
+
+// acceptLanguage returns the Accept-Language value of the tags, with the base language of regional tags
+// and quality values decreasing by 0.1, down to 0.1.
+func acceptLanguage(tags []string) string {
+	var languages []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if key := strings.ToLower(tag); tag != "" && !seen[key] {
+			seen[key] = true
+			languages = append(languages, tag)
+		}
+	}
+
+	for _, tag := range tags {
+		tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+		add(tag)
+		if base, _, ok := strings.Cut(tag, "-"); ok {
+			add(base)
+		}
+	}
+
+	for i := 1; i < len(languages); i++ {
+		q := max(10-i, 1)
+		languages[i] += ";q=0." + strconv.Itoa(q)
+	}
+
+	return strings.Join(languages, ", ")
+}
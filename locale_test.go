@@ -0,0 +1,58 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithLocale(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		opts         []clink.Option
+		wantLanguage string
+		wantTimeZone string
+	}{
+		{name: "single language", opts: []clink.Option{clink.WithLocale("en")}, wantLanguage: "en"},
+		{name: "regional tag with base language", opts: []clink.Option{clink.WithLocale("fr_CA", "en-US")}, wantLanguage: "fr-CA, fr;q=0.9, en-US;q=0.8, en;q=0.7"},
+		{name: "duplicate base languages", opts: []clink.Option{clink.WithLocale("pt-BR", "pt-PT", "pt")}, wantLanguage: "pt-BR, pt;q=0.9, pt-PT;q=0.8"},
+		{name: "time zone", opts: []clink.Option{clink.WithLocale("de"), clink.WithTimeZone(paris)}, wantLanguage: "de", wantTimeZone: "Europe/Paris"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Language"); got != tt.wantLanguage {
+					t.Errorf("expected Accept-Language %q, got %q", tt.wantLanguage, got)
+				}
+				if got := r.Header.Get("Time-Zone"); got != tt.wantTimeZone {
+					t.Errorf("expected Time-Zone %q, got %q", tt.wantTimeZone, got)
+				}
+				w.Header().Add("Content-Language", "de-DE, en")
+				w.Header().Add("Content-Language", "fr")
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(append(tt.opts, clink.WithClient(server.Client()))...)
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if got := clink.ContentLanguage(resp); !reflect.DeepEqual(got, []string{"de-DE", "en", "fr"}) {
+				t.Errorf("unexpected content languages %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLoginFailed is returned when the response to a login request does not pass its success check.
+var ErrLoginFailed = errors.New("login failed")
+
+type loginKey struct{}
+
+type session struct {
+	mu         sync.Mutex
+	loginURL   string
+	form       url.Values
+	success    func(*http.Response) bool
+	loggedInAt time.Time
+}
+
+// Login posts the form to loginURL and keeps the session cookies of the response in the cookie jar of the http client.
+// success reports whether the final response, after redirects, indicates a successful login; when nil, any status below 400 does.
+// A cookie jar is set on the http client if it has none, so Login should be called before the client is shared.
+func (c *Client) Login(ctx context.Context, loginURL string, form url.Values, success func(*http.Response) bool) error {
+	if success == nil {
+		success = func(resp *http.Response) bool {
+			return resp.StatusCode < http.StatusBadRequest
+		}
+	}
+
+	if err := c.ensureJar(); err != nil {
+		return err
+	}
+
+	if err := c.login(ctx, loginURL, form, success); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.session = &session{loginURL: loginURL, form: form, success: success, loggedInAt: c.clock().Now()}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// WithRelogin logs in again with the parameters of the last successful Login when a response reports that the session
+// expired, and retries the request once. When expired is nil, 401 responses are considered expired sessions.
+func WithRelogin(expired func(*http.Response) bool) Option {
+	return func(c *Client) {
+		if expired == nil {
+			expired = func(resp *http.Response) bool {
+				return resp.StatusCode == http.StatusUnauthorized
+			}
+		}
+		c.sessionExpired = expired
+	}
+}
+
+func (c *Client) login(ctx context.Context, loginURL string, form url.Values, success func(*http.Response) bool) error {
+	req, err := http.NewRequestWithContext(context.WithValue(ctx, loginKey{}, true), http.MethodPost, loginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+	defer discardBody(resp)
+
+	if !success(resp) {
+		return fmt.Errorf("%w: status %s", ErrLoginFailed, resp.Status)
+	}
+
+	return nil
+}
+
+// ensureJar sets a cookie jar on the http client if it has none.
+func (c *Client) ensureJar() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.HttpClient.Jar != nil {
+		return nil
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+
+	httpClient := *c.HttpClient
+	httpClient.Jar = jar
+	c.HttpClient = &httpClient
+	c.clearServerNameClients()
+
+	return nil
+}
+
+// shouldRelogin reports whether the response to the request reports an expired session that can be renewed.
+func (c *Client) shouldRelogin(req *http.Request, resp *http.Response) bool {
+	if c.sessionExpired == nil || resp == nil || req.Context().Value(loginKey{}) != nil {
+		return false
+	}
+
+	c.mu.Lock()
+	s := c.session
+	c.mu.Unlock()
+
+	return s != nil && c.sessionExpired(resp)
+}
+
+// relogin logs in again unless another request already did since the given time.
+func (c *Client) relogin(ctx context.Context, since time.Time) error {
+	c.mu.Lock()
+	s := c.session
+	c.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loggedInAt.After(since) {
+		return nil
+	}
+
+	if err := c.login(ctx, s.loginURL, s.form, s.success); err != nil {
+		return err
+	}
+	s.loggedInAt = c.clock().Now()
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func newLoginServer(logins *int32) *httptest.Server {
+	var session atomic.Value
+	session.Store("")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("user") != "alice" || r.PostFormValue("password") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		id := strings.Repeat("s", int(atomic.AddInt32(logins, 1)))
+		session.Store(id)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: id, Path: "/"})
+		http.Redirect(w, r, "/home", http.StatusFound)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("home"))
+	})
+	mux.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != session.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("/expire", func(w http.ResponseWriter, r *http.Request) {
+		session.Store("expired")
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestClient_Login(t *testing.T) {
+	tests := []struct {
+		name     string
+		form     url.Values
+		relogin  bool
+		loginErr error
+		expire   bool
+		status   int
+		logins   int32
+	}{
+		{
+			name:   "logs in and keeps the session cookie",
+			form:   url.Values{"user": {"alice"}, "password": {"secret"}},
+			status: http.StatusOK,
+			logins: 1,
+		},
+		{
+			name:     "fails with invalid credentials",
+			form:     url.Values{"user": {"alice"}, "password": {"wrong"}},
+			loginErr: clink.ErrLoginFailed,
+		},
+		{
+			name:   "does not log in again without relogin",
+			form:   url.Values{"user": {"alice"}, "password": {"secret"}},
+			expire: true,
+			status: http.StatusUnauthorized,
+			logins: 1,
+		},
+		{
+			name:    "logs in again when the session expired",
+			form:    url.Values{"user": {"alice"}, "password": {"secret"}},
+			relogin: true,
+			expire:  true,
+			status:  http.StatusOK,
+			logins:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logins int32
+			server := newLoginServer(&logins)
+			defer server.Close()
+
+			opts := []clink.Option{clink.WithClient(server.Client())}
+			if tt.relogin {
+				opts = append([]clink.Option{clink.WithRelogin(nil)}, opts...)
+			}
+			c := clink.NewClient(opts...)
+
+			err := c.Login(context.Background(), server.URL+"/login", tt.form, nil)
+			if !errors.Is(err, tt.loginErr) {
+				t.Fatalf("expected login error %v, got %v", tt.loginErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if tt.expire {
+				resp, err := c.Get(server.URL + "/expire")
+				if err != nil {
+					t.Fatalf("failed to expire the session: %v", err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			resp, err := c.Post(server.URL+"/data", strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status == http.StatusOK && string(body) != "payload" {
+				t.Errorf("expected the body to be replayed, got %q", body)
+			}
+			if got := atomic.LoadInt32(&logins); got != tt.logins {
+				t.Errorf("expected %d logins, got %d", tt.logins, got)
+			}
+		})
+	}
+}
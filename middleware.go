@@ -0,0 +1,82 @@
+package clink
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Handler sends a request and returns its response.
+type Handler func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the handler sending each attempt of a request, after it has been prepared by the client,
+// so that it can sign the request, record telemetry or return its own response.
+type Middleware func(next Handler) Handler
+
+type scopedMiddleware struct {
+	pattern    string
+	middleware Middleware
+}
+
+// WithMiddleware adds middleware applied to every request. Middleware added first runs first.
+func WithMiddleware(middleware ...Middleware) Option {
+	return WithMiddlewareFor("", middleware...)
+}
+
+// WithMiddlewareFor adds middleware applied to the requests whose URL matches the pattern, such as "api.stripe.com/*",
+// "*.example.com" or "example.com/v1/users/*". See MatchURL for the pattern syntax.
+func WithMiddlewareFor(pattern string, middleware ...Middleware) Option {
+	return func(c *Client) {
+		for _, mw := range middleware {
+			c.middleware = append(c.middleware, scopedMiddleware{pattern: pattern, middleware: mw})
+		}
+	}
+}
+
+// MatchURL reports whether the URL matches the pattern, made of a host pattern optionally followed by a path pattern.
+// Patterns use the syntax of path.Match, and a path pattern ending with "/*" matches any path below its prefix.
+// A pattern without path matches any path, an empty pattern matches any URL, and the scheme of the pattern, if any, must match.
+func MatchURL(pattern string, u *url.URL) bool {
+	if pattern == "" {
+		return true
+	}
+
+	if scheme, rest, ok := strings.Cut(pattern, "://"); ok {
+		if !strings.EqualFold(scheme, u.Scheme) {
+			return false
+		}
+		pattern = rest
+	}
+
+	hostPattern, pathPattern, hasPath := strings.Cut(pattern, "/")
+	if matched, _ := path.Match(hostPattern, u.Host); !matched {
+		if matched, _ := path.Match(hostPattern, u.Hostname()); !matched {
+			return false
+		}
+	}
+
+	if !hasPath {
+		return true
+	}
+
+	pathPattern = "/" + pathPattern
+	if prefix, ok := strings.CutSuffix(pathPattern, "/*"); ok {
+		return u.Path == prefix || strings.HasPrefix(u.Path, prefix+"/")
+	}
+
+	matched, _ := path.Match(pathPattern, u.Path)
+
+	return matched
+}
+
+// handler returns the handler sending the request through the middleware matching its URL.
+func (c *Client) handler(req *http.Request, send Handler) Handler {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		if MatchURL(c.middleware[i].pattern, req.URL) {
+			send = c.middleware[i].middleware(send)
+		}
+	}
+
+	return send
+}
@@ -0,0 +1,95 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestMatchURL(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{pattern: "", url: "https://example.com/a", want: true},
+		{pattern: "api.stripe.com/*", url: "https://api.stripe.com/v1/charges", want: true},
+		{pattern: "api.stripe.com/*", url: "https://api.stripe.com", want: true},
+		{pattern: "api.stripe.com/*", url: "https://stripe.com/v1", want: false},
+		{pattern: "*.example.com", url: "https://api.example.com:8443/x", want: true},
+		{pattern: "*.example.com", url: "https://example.com/x", want: false},
+		{pattern: "example.com/v1/users/*", url: "https://example.com/v1/users/1", want: true},
+		{pattern: "example.com/v1/users/*", url: "https://example.com/v1/usersx", want: false},
+		{pattern: "example.com/v1/*/items", url: "https://example.com/v1/42/items", want: true},
+		{pattern: "https://example.com", url: "http://example.com/", want: false},
+		{pattern: "localhost:8080/*", url: "http://localhost:8080/a", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.url, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			if got := clink.MatchURL(tt.pattern, u); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_MiddlewareFor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Signature", r.Header.Get("X-Signature"))
+		w.Header().Set("X-Trace", r.Header.Get("X-Trace"))
+	}))
+	defer server.Close()
+
+	var order []string
+	setHeader := func(key, value string) clink.Middleware {
+		return func(next clink.Handler) clink.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, key)
+				req.Header.Set(key, value)
+				return next(req)
+			}
+		}
+	}
+
+	c := clink.NewClient(
+		clink.WithMiddleware(setHeader("X-Trace", "on")),
+		clink.WithMiddlewareFor("127.0.0.1/signed/*", setHeader("X-Signature", "signed")),
+		clink.WithClient(server.Client()),
+	)
+
+	tests := []struct {
+		path      string
+		signature string
+		order     []string
+	}{
+		{path: "/signed/charges", signature: "signed", order: []string{"X-Trace", "X-Signature"}},
+		{path: "/public", signature: "", order: []string{"X-Trace"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			order = nil
+
+			resp, err := c.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if got := resp.Header.Get("X-Signature"); got != tt.signature {
+				t.Errorf("expected signature %q, got %q", tt.signature, got)
+			}
+			if resp.Header.Get("X-Trace") != "on" {
+				t.Errorf("expected the global middleware to apply")
+			}
+			if len(order) != len(tt.order) || order[0] != tt.order[0] {
+				t.Errorf("expected middleware order %v, got %v", tt.order, order)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// MultipartUpload configures an S3 compatible multipart upload.
+type MultipartUpload struct {
+	// PartSize is the size of each part. S3 requires at least 5 MiB for all parts but the last. Defaults to 8 MiB.
+	PartSize int64
+	// Concurrency is the number of parts uploaded at once. Defaults to 4.
+	Concurrency int
+	// PartRetries is the number of times a failed part is retried. Defaults to 3.
+	PartRetries int
+	// ContentType is the content type of the object when set.
+	ContentType string
+}
+
+// S3Error is an error document returned by an S3 compatible API.
+type S3Error struct {
+	Code      string `xml:"Code"`
+	Message   string `xml:"Message"`
+	RequestID string `xml:"RequestId"`
+}
+
+// Error implements the error interface.
+func (e *S3Error) Error() string {
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// UploadMultipart uploads the size bytes of r to objectURL as an S3 multipart upload and returns the completion response.
+// The parts are uploaded concurrently through the client, so its headers, rate limiting and retries apply to every request,
+// and the upload is aborted if a part still fails after its retries.
+func (c *Client) UploadMultipart(ctx context.Context, objectURL string, r io.ReaderAt, size int64, upload MultipartUpload) (*http.Response, error) {
+	partSize := upload.PartSize
+	if partSize <= 0 {
+		partSize = 8 << 20
+	}
+
+	concurrency := upload.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	retries := upload.PartRetries
+	if retries <= 0 {
+		retries = 3
+	}
+
+	uploadID, err := c.initiateMultipart(ctx, objectURL, upload.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := c.uploadParts(ctx, objectURL, uploadID, r, size, partSize, concurrency, retries)
+	if err != nil {
+		c.abortMultipart(context.WithoutCancel(ctx), objectURL, uploadID)
+		return nil, err
+	}
+
+	resp, err := c.completeMultipart(ctx, objectURL, uploadID, parts)
+	if err != nil {
+		c.abortMultipart(context.WithoutCancel(ctx), objectURL, uploadID)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// multipartURL returns the object URL with the raw query appended.
+func multipartURL(objectURL, rawQuery string) (string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse object url: %w", err)
+	}
+
+	if u.RawQuery != "" {
+		rawQuery = u.RawQuery + "&" + rawQuery
+	}
+	u.RawQuery = rawQuery
+
+	return u.String(), nil
+}
+
+// doS3 sends an S3 request and reads its body, returning a *S3Error for error documents.
+func (c *Client) doS3(ctx context.Context, method, rawURL string, body io.Reader, size int64, contentType string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	var s3Err struct {
+		XMLName xml.Name `xml:"Error"`
+		S3Error
+	}
+	if xml.Unmarshal(data, &s3Err) == nil {
+		return nil, nil, &s3Err.S3Error
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil, newStatusError(req, resp)
+	}
+
+	return resp, data, nil
+}
+
+func (c *Client) initiateMultipart(ctx context.Context, objectURL, contentType string) (string, error) {
+	u, err := multipartURL(objectURL, "uploads")
+	if err != nil {
+		return "", err
+	}
+
+	_, data, err := c.doS3(ctx, http.MethodPost, u, nil, 0, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to initiate multipart upload: %w", err)
+	}
+
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(data, &result); err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("failed to read multipart upload id: %w", err)
+	}
+
+	return result.UploadID, nil
+}
+
+// uploadParts uploads the parts with the given concurrency and returns them ordered by part number.
+func (c *Client) uploadParts(ctx context.Context, objectURL, uploadID string, r io.ReaderAt, size, partSize int64, concurrency, retries int) ([]completedPart, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	count := int((size + partSize - 1) / partSize)
+	if count == 0 {
+		count = 1
+	}
+
+	numbers := make(chan int)
+	go func() {
+		defer close(numbers)
+		for n := 1; n <= count; n++ {
+			select {
+			case numbers <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	var firstErr error
+	parts := make([]completedPart, 0, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numbers {
+				offset := int64(n-1) * partSize
+				length := partSize
+				if offset+length > size {
+					length = size - offset
+				}
+
+				etag, err := c.uploadPart(ctx, objectURL, uploadID, n, io.NewSectionReader(r, offset, length), retries)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				if err == nil {
+					parts = append(parts, completedPart{PartNumber: n, ETag: etag})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	return parts, nil
+}
+
+// uploadPart uploads a part, retrying it on failure, and returns its ETag.
+func (c *Client) uploadPart(ctx context.Context, objectURL, uploadID string, number int, part *io.SectionReader, retries int) (string, error) {
+	u, err := multipartURL(objectURL, "partNumber="+strconv.Itoa(number)+"&uploadId="+url.QueryEscape(uploadID))
+	if err != nil {
+		return "", err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, _, err := c.doS3(ctx, http.MethodPut, u, io.NewSectionReader(part, 0, part.Size()), part.Size(), "")
+		if err == nil {
+			return resp.Header.Get("ETag"), nil
+		}
+
+		if attempt >= retries || ctx.Err() != nil {
+			return "", fmt.Errorf("failed to upload part %d: %w", number, err)
+		}
+
+		delay, err := c.backoff(attempt, nil)
+		if err != nil {
+			return "", err
+		}
+
+		select {
+		case <-clockAfter(ctx, c.clock(), delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+func (c *Client) completeMultipart(ctx context.Context, objectURL, uploadID string, parts []completedPart) (*http.Response, error) {
+	u, err := multipartURL(objectURL, "uploadId="+url.QueryEscape(uploadID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode completion request: %w", err)
+	}
+
+	resp, _, err := c.doS3(ctx, http.MethodPost, u, bytes.NewReader(body), int64(len(body)), "application/xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) abortMultipart(ctx context.Context, objectURL, uploadID string) {
+	u, err := multipartURL(objectURL, "uploadId="+url.QueryEscape(uploadID))
+	if err != nil {
+		return
+	}
+
+	_, _, _ = c.doS3(ctx, http.MethodDelete, u, nil, 0, "")
+}
@@ -0,0 +1,123 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+// fakeS3 implements the multipart upload API of S3 for a single object.
+type fakeS3 struct {
+	mu       sync.Mutex
+	parts    map[int]string
+	failures map[int]int
+	object   string
+	aborted  bool
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		_, _ = fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == http.MethodPut:
+		n, _ := strconv.Atoi(query.Get("partNumber"))
+		if f.failures[n] > 0 {
+			f.failures[n]--
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = fmt.Fprint(w, `<Error><Code>InternalError</Code><Message>try again</Message></Error>`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.parts[n] = string(body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+	case r.Method == http.MethodPost:
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+				ETag       string
+			} `xml:"Part"`
+		}
+		_ = xml.NewDecoder(r.Body).Decode(&complete)
+		for _, part := range complete.Parts {
+			if part.ETag != fmt.Sprintf(`"etag-%d"`, part.PartNumber) {
+				_, _ = fmt.Fprint(w, `<Error><Code>InvalidPart</Code><Message>bad etag</Message></Error>`)
+				return
+			}
+			f.object += f.parts[part.PartNumber]
+		}
+		_, _ = fmt.Fprint(w, `<CompleteMultipartUploadResult><Key>object</Key></CompleteMultipartUploadResult>`)
+	case r.Method == http.MethodDelete:
+		f.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestClient_UploadMultipart(t *testing.T) {
+	data := strings.Repeat("0123456789", 5)
+
+	testCases := []struct {
+		name     string
+		failures map[int]int
+		err      bool
+	}{
+		{name: "uploads the parts", failures: map[int]int{}},
+		{name: "retries failed parts", failures: map[int]int{2: 2}},
+		{name: "aborts when a part keeps failing", failures: map[int]int{3: 10}, err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s3 := &fakeS3{parts: make(map[int]string), failures: tc.failures}
+			server := httptest.NewServer(s3)
+			defer server.Close()
+
+			c := clink.NewClient(
+				clink.WithBackoff(func(attempt int, resp *http.Response) time.Duration { return time.Millisecond }),
+				clink.WithClient(server.Client()),
+			)
+
+			resp, err := c.UploadMultipart(context.Background(), server.URL+"/bucket/object", strings.NewReader(data), int64(len(data)), clink.MultipartUpload{
+				PartSize:    12,
+				Concurrency: 3,
+			})
+
+			s3.mu.Lock()
+			defer s3.mu.Unlock()
+
+			if tc.err {
+				var s3Err *clink.S3Error
+				if !errors.As(err, &s3Err) || s3Err.Code != "InternalError" {
+					t.Errorf("expected an s3 error, got %v", err)
+				}
+				if !s3.aborted {
+					t.Error("expected the upload to be aborted")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("upload failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if s3.object != data {
+				t.Errorf("expected %q to be uploaded, got %q", data, s3.object)
+			}
+		})
+	}
+}
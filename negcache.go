@@ -0,0 +1,249 @@
+package clink
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxNegativeBody is the size of the largest response body kept by the negative cache.
+const maxNegativeBody = 64 << 10
+
+// NegativeCache configures WithNegativeCache.
+type NegativeCache struct {
+	// TTL is how long failures are remembered. Defaults to 30 seconds.
+	TTL time.Duration
+	// Statuses are the status codes remembered per method and URL. Defaults to 404 and 410.
+	Statuses []int
+	// DNS remembers "no such host" DNS failures per host.
+	DNS bool
+	// OnHit is called with the requests answered from the negative cache, such as to count them.
+	OnHit func(req *http.Request)
+	// MaxEntries is the number of failures remembered, the least recently used being forgotten first.
+	// Defaults to 1000.
+	MaxEntries int
+}
+
+// WithNegativeCache remembers the failures of GET and HEAD requests for a short time, answering the same requests
+// with the remembered response or error without contacting the server, for read-heavy workloads hitting missing
+// resources repeatedly. Requests with an Authorization or Cookie header are not cached, as the failure may depend on
+// the credentials. Unlike WithCache, it ignores the caching headers of the responses. NoCache bypasses it and
+// Refresh sends the request anyway. See InvalidateNegativeCache to forget failures early.
+func WithNegativeCache(config NegativeCache) Option {
+	return func(c *Client) {
+		if config.TTL <= 0 {
+			config.TTL = 30 * time.Second
+		}
+		if len(config.Statuses) == 0 {
+			config.Statuses = []int{http.StatusNotFound, http.StatusGone}
+		}
+		if config.MaxEntries <= 0 {
+			config.MaxEntries = 1000
+		}
+
+		c.negativeCache = &negativeCache{config: config, lru: list.New(), entries: make(map[string]*list.Element)}
+	}
+}
+
+// InvalidateNegativeCache forgets the failures remembered for the URL and, for DNS failures, for its host,
+// such as after creating the resource. An empty URL forgets every failure.
+func (c *Client) InvalidateNegativeCache(rawURL string) {
+	if c.negativeCache == nil {
+		return
+	}
+
+	c.negativeCache.mu.Lock()
+	defer c.negativeCache.mu.Unlock()
+
+	if rawURL == "" {
+		c.negativeCache.lru.Init()
+		c.negativeCache.entries = make(map[string]*list.Element)
+		return
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return
+	}
+
+	c.negativeCache.remove(negativeHostKey(u))
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		c.negativeCache.remove(method + " " + u.String())
+	}
+}
+
+type negativeCache struct {
+	mu      sync.Mutex
+	config  NegativeCache
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type negativeEntry struct {
+	key        string
+	expires    time.Time
+	err        error
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// lookup returns the entry of the key if it has not expired, forgetting it otherwise. It must be called with the lock held.
+func (n *negativeCache) lookup(key string, now time.Time) (*negativeEntry, bool) {
+	elem, ok := n.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*negativeEntry)
+	if !entry.expires.After(now) {
+		n.remove(key)
+		return nil, false
+	}
+	n.lru.MoveToFront(elem)
+
+	return entry, true
+}
+
+// store remembers the entry, forgetting the least recently used entries beyond the maximum.
+// It must be called with the lock held.
+func (n *negativeCache) store(entry *negativeEntry) {
+	n.remove(entry.key)
+	n.entries[entry.key] = n.lru.PushFront(entry)
+
+	for n.lru.Len() > n.config.MaxEntries {
+		n.remove(n.lru.Back().Value.(*negativeEntry).key)
+	}
+}
+
+// remove forgets the entry of the key, if any. It must be called with the lock held.
+func (n *negativeCache) remove(key string) {
+	if elem, ok := n.entries[key]; ok {
+		n.lru.Remove(elem)
+		delete(n.entries, key)
+	}
+}
+
+func negativeHostKey(u *url.URL) string {
+	return "dns " + u.Hostname()
+}
+
+// negativeKeys returns the keys of the request in the negative cache, or nil when it is not subject to it.
+func (c *Client) negativeKeys(req *http.Request) (hostKey, requestKey string, ok bool) {
+	if c.negativeCache == nil || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return "", "", false
+	}
+
+	if req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" {
+		return "", "", false
+	}
+
+	if requestConfigFrom(req).cacheMode == cacheBypass {
+		return "", "", false
+	}
+
+	return negativeHostKey(req.URL), req.Method + " " + req.URL.String(), true
+}
+
+// cachedFailure returns the failure remembered for the request, if any.
+func (c *Client) cachedFailure(req *http.Request) (*http.Response, error, bool) {
+	hostKey, requestKey, ok := c.negativeKeys(req)
+	if !ok || requestConfigFrom(req).cacheMode == cacheRefresh {
+		return nil, nil, false
+	}
+
+	now := c.clock().Now()
+
+	c.negativeCache.mu.Lock()
+	entry, found := c.negativeCache.lookup(hostKey, now)
+	if !found {
+		entry, found = c.negativeCache.lookup(requestKey, now)
+	}
+	c.negativeCache.mu.Unlock()
+
+	if !found {
+		return nil, nil, false
+	}
+
+	if hook := c.negativeCache.config.OnHit; hook != nil {
+		func() {
+			defer recordPanic(req)
+			hook(req)
+		}()
+	}
+
+	if entry.err != nil {
+		return nil, entry.err, true
+	}
+
+	header := entry.header.Clone()
+	header.Set(CacheStatusHeader, "HIT")
+
+	return &http.Response{
+		Status:        strconv.Itoa(entry.statusCode) + " " + http.StatusText(entry.statusCode),
+		StatusCode:    entry.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+		Request:       req,
+	}, nil, true
+}
+
+// storeFailure remembers the failure of the request, if it is one the negative cache is configured for.
+// The body of remembered responses is read, and replaced by a copy.
+func (c *Client) storeFailure(req *http.Request, resp *http.Response, err error) {
+	hostKey, requestKey, ok := c.negativeKeys(req)
+	if !ok {
+		return
+	}
+
+	entry := &negativeEntry{key: requestKey, expires: c.clock().Now().Add(c.negativeCache.config.TTL)}
+
+	switch {
+	case err != nil:
+		var dnsErr *net.DNSError
+		if !c.negativeCache.config.DNS || !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			return
+		}
+		entry.err, entry.key = err, hostKey
+	case c.negativeStatus(resp.StatusCode) && resp.Header.Get(CacheStatusHeader) == "":
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxNegativeBody+1))
+		if readErr != nil || len(body) > maxNegativeBody {
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+			return
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		entry.statusCode, entry.header, entry.body = resp.StatusCode, resp.Header.Clone(), body
+	default:
+		return
+	}
+
+	c.negativeCache.mu.Lock()
+	defer c.negativeCache.mu.Unlock()
+
+	c.negativeCache.store(entry)
+}
+
+func (c *Client) negativeStatus(code int) bool {
+	for _, status := range c.negativeCache.config.Statuses {
+		if status == code {
+			return true
+		}
+	}
+
+	return false
+}
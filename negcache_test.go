@@ -0,0 +1,116 @@
+package clink_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestWithNegativeCache(t *testing.T) {
+	transport := clinktest.NewTransport()
+	missing := transport.On(http.MethodGet, "api.example.com/users/*").Respond(clinktest.Reply(http.StatusNotFound, "not found"))
+	unknown := transport.On("", "unknown.example.com").Respond(clinktest.Fail(&net.DNSError{Err: "no such host", Name: "unknown.example.com", IsNotFound: true}))
+
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	hits := 0
+	client := clink.NewClient(
+		clink.WithNegativeCache(clink.NegativeCache{TTL: time.Minute, DNS: true, OnHit: func(*http.Request) { hits++ }}),
+		clink.WithClock(clock),
+		clink.WithClient(transport.Client()),
+	)
+
+	get := func(url string, opts ...clink.RequestOption) {
+		t.Helper()
+
+		resp, err := client.Get(url, opts...)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound || string(body) != "not found" {
+			t.Errorf("expected the 404 response, got %d: %s", resp.StatusCode, body)
+		}
+	}
+
+	get("https://api.example.com/users/1")
+	get("https://api.example.com/users/1")
+	if missing.Calls() != 1 || hits != 1 {
+		t.Errorf("expected the second request to be served from the negative cache, got %d calls", missing.Calls())
+	}
+
+	get("https://api.example.com/users/1", clink.NoCache())
+	get("https://api.example.com/users/2")
+	if missing.Calls() != 3 {
+		t.Errorf("expected bypassed and other requests to reach the server, got %d calls", missing.Calls())
+	}
+
+	client.InvalidateNegativeCache("https://api.example.com/users/1")
+	get("https://api.example.com/users/1")
+	clock.Advance(2 * time.Minute)
+	get("https://api.example.com/users/2")
+	if missing.Calls() != 5 {
+		t.Errorf("expected invalidated and expired failures to be forgotten, got %d calls", missing.Calls())
+	}
+
+	for _, path := range []string{"/a", "/b"} {
+		var dnsErr *net.DNSError
+		if _, err := client.Get("https://unknown.example.com" + path); !errors.As(err, &dnsErr) {
+			t.Fatalf("expected a dns error, got: %v", err)
+		}
+	}
+	if unknown.Calls() != 1 {
+		t.Errorf("expected the dns failure to be remembered for the host, got %d calls", unknown.Calls())
+	}
+}
+
+func TestWithNegativeCache_Credentials(t *testing.T) {
+	transport := clinktest.NewTransport()
+	route := transport.On(http.MethodGet, "api.example.com/users/1").Respond(clinktest.Reply(http.StatusNotFound, "not found"))
+
+	client := clink.NewClient(
+		clink.WithNegativeCache(clink.NegativeCache{TTL: time.Minute}),
+		clink.WithClient(transport.Client()),
+	)
+
+	for _, token := range []string{"Bearer alice", "Bearer bob", "Bearer alice"} {
+		resp, err := client.Get("https://api.example.com/users/1", clink.WithRequestHeader("Authorization", token))
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if route.Calls() != 3 {
+		t.Errorf("expected credentialed requests to bypass the negative cache, got %d calls", route.Calls())
+	}
+}
+
+func TestWithNegativeCache_MaxEntries(t *testing.T) {
+	transport := clinktest.NewTransport()
+	missing := transport.On(http.MethodGet, "api.example.com/users/*").Respond(clinktest.Reply(http.StatusNotFound, "not found"))
+
+	client := clink.NewClient(
+		clink.WithNegativeCache(clink.NegativeCache{MaxEntries: 2}),
+		clink.WithClient(transport.Client()),
+	)
+
+	for _, id := range []string{"1", "2", "3", "3", "2", "1"} {
+		resp, err := client.Get("https://api.example.com/users/" + id)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if missing.Calls() != 4 {
+		t.Errorf("expected the least recently used failure to be forgotten, got %d calls", missing.Calls())
+	}
+}
@@ -0,0 +1,177 @@
+package clink
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ODataQuery builds the system query options of an OData request.
+type ODataQuery struct {
+	filter  string
+	search  string
+	selects []string
+	expand  []string
+	orderBy []string
+	top     int
+	skip    int
+	count   bool
+}
+
+// NewODataQuery returns an empty OData query.
+func NewODataQuery() *ODataQuery {
+	return &ODataQuery{top: -1, skip: -1}
+}
+
+// Filter sets the $filter expression. Each ? placeholder in expr is replaced with the corresponding argument
+// formatted as an OData literal, with strings quoted and escaped.
+func (q *ODataQuery) Filter(expr string, args ...any) *ODataQuery {
+	var b strings.Builder
+	for _, arg := range args {
+		i := strings.IndexByte(expr, '?')
+		if i < 0 {
+			break
+		}
+		b.WriteString(expr[:i])
+		b.WriteString(ODataLiteral(arg))
+		expr = expr[i+1:]
+	}
+	b.WriteString(expr)
+	q.filter = b.String()
+
+	return q
+}
+
+// Search sets the $search expression.
+func (q *ODataQuery) Search(expr string) *ODataQuery {
+	q.search = expr
+	return q
+}
+
+// Select adds properties to $select.
+func (q *ODataQuery) Select(properties ...string) *ODataQuery {
+	q.selects = append(q.selects, properties...)
+	return q
+}
+
+// Expand adds navigation properties to $expand.
+func (q *ODataQuery) Expand(properties ...string) *ODataQuery {
+	q.expand = append(q.expand, properties...)
+	return q
+}
+
+// OrderBy adds a property to $orderby, in descending order when desc is true.
+func (q *ODataQuery) OrderBy(property string, desc bool) *ODataQuery {
+	if desc {
+		property += " desc"
+	}
+	q.orderBy = append(q.orderBy, property)
+
+	return q
+}
+
+// Top sets $top.
+func (q *ODataQuery) Top(n int) *ODataQuery {
+	q.top = n
+	return q
+}
+
+// Skip sets $skip.
+func (q *ODataQuery) Skip(n int) *ODataQuery {
+	q.skip = n
+	return q
+}
+
+// Count sets $count=true.
+func (q *ODataQuery) Count() *ODataQuery {
+	q.count = true
+	return q
+}
+
+// Encode returns the query options URL encoded, keeping the $ prefix of the option names.
+func (q *ODataQuery) Encode() string {
+	var params []string
+	add := func(name, value string) {
+		params = append(params, name+"="+strings.ReplaceAll(url.QueryEscape(value), "+", "%20"))
+	}
+
+	if q.filter != "" {
+		add("$filter", q.filter)
+	}
+	if q.search != "" {
+		add("$search", q.search)
+	}
+	if len(q.selects) > 0 {
+		add("$select", strings.Join(q.selects, ","))
+	}
+	if len(q.expand) > 0 {
+		add("$expand", strings.Join(q.expand, ","))
+	}
+	if len(q.orderBy) > 0 {
+		add("$orderby", strings.Join(q.orderBy, ","))
+	}
+	if q.top >= 0 {
+		add("$top", strconv.Itoa(q.top))
+	}
+	if q.skip >= 0 {
+		add("$skip", strconv.Itoa(q.skip))
+	}
+	if q.count {
+		add("$count", "true")
+	}
+
+	return strings.Join(params, "&")
+}
+
+// URL appends the query options to the given URL.
+func (q *ODataQuery) URL(base string) string {
+	encoded := q.Encode()
+	if encoded == "" {
+		return base
+	}
+
+	if strings.Contains(base, "?") {
+		return base + "&" + encoded
+	}
+
+	return base + "?" + encoded
+}
+
+// ODataLiteral formats the value as an OData literal, quoting and escaping strings.
+func ODataLiteral(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return "'" + strings.ReplaceAll(value.String(), "'", "''") + "'"
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// ODataCollect requests the OData collection at url and follows @odata.nextLink until the last page,
+// returning the items of every page. maxPages bounds the number of pages requested, 0 meaning no limit.
+// When maxPages stops the collection before the last page, the items collected so far are returned with ErrPaginationLimit.
+func ODataCollect[T any](ctx context.Context, c *Client, url string, maxPages int) ([]T, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if maxPages <= 0 {
+		maxPages = math.MaxInt
+	}
+
+	return CollectAll(ctx, c, req, ODataPagination[T](), MaxPages(maxPages))
+}
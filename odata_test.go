@@ -0,0 +1,83 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestODataQuery(t *testing.T) {
+	query := clink.NewODataQuery().
+		Filter("displayName eq ? and createdAt gt ? and enabled eq ?", "O'Brien", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), true).
+		Select("id", "displayName").
+		Expand("manager").
+		OrderBy("displayName", false).
+		OrderBy("createdAt", true).
+		Top(10).
+		Skip(20)
+
+	encoded := query.Encode()
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+
+	expected := map[string]string{
+		"$filter":  "displayName eq 'O''Brien' and createdAt gt 2024-01-02T03:04:05Z and enabled eq true",
+		"$select":  "id,displayName",
+		"$expand":  "manager",
+		"$orderby": "displayName,createdAt desc",
+		"$top":     "10",
+		"$skip":    "20",
+	}
+	for key, value := range expected {
+		if values.Get(key) != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, values.Get(key))
+		}
+	}
+
+	if u := query.URL("https://graph.microsoft.com/v1.0/users"); u != "https://graph.microsoft.com/v1.0/users?"+encoded {
+		t.Errorf("unexpected url: %s", u)
+	}
+}
+
+func TestODataCollect(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := map[string]any{"value": []map[string]string{{"id": "1"}, {"id": "2"}}}
+		if r.URL.Query().Get("$skiptoken") == "" {
+			page["@odata.nextLink"] = server.URL + "/users?$skiptoken=abc"
+		} else {
+			page["value"] = []map[string]string{{"id": "3"}}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	type user struct {
+		ID string `json:"id"`
+	}
+
+	users, err := clink.ODataCollect[user](context.Background(), client, server.URL+"/users", 0)
+	if err != nil {
+		t.Fatalf("failed to collect users: %v", err)
+	}
+
+	if len(users) != 3 || users[2].ID != "3" {
+		t.Errorf("unexpected users: %+v", users)
+	}
+
+	users, err = clink.ODataCollect[user](context.Background(), client, server.URL+"/users", 1)
+	if len(users) != 2 || !errors.Is(err, clink.ErrPaginationLimit) {
+		t.Errorf("expected page limit to be honored, got %d users and %v", len(users), err)
+	}
+}
@@ -0,0 +1,101 @@
+package clink
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// OutboxMessage is a request waiting for delivery in an outbox.
+type OutboxMessage struct {
+	// ID identifies the message in the outbox. It is sent as the Idempotency-Key of the request unless
+	// the snapshot already has one, so that redeliveries after a crash can be detected by the receiver.
+	ID       string
+	Snapshot *Snapshot
+	// Attempts is the number of deliveries already attempted, maintained by the outbox.
+	Attempts int
+}
+
+// Outbox is a persistent store of requests, such as a database table or a queue, written in the same
+// transaction as the changes they report. The client delivers its pending messages and reports the outcome
+// of each delivery, so that it can be recorded transactionally.
+type Outbox interface {
+	// Pending returns up to limit messages waiting for delivery, usually locking them until they are marked.
+	Pending(ctx context.Context, limit int) ([]OutboxMessage, error)
+	// Delivered marks the message delivered. The response body is closed once it returns.
+	Delivered(ctx context.Context, msg OutboxMessage, resp *http.Response) error
+	// Failed records a failed delivery of the message, which stays pending or is moved aside by the outbox.
+	Failed(ctx context.Context, msg OutboxMessage, err error) error
+}
+
+// DeliverOutbox delivers the pending messages of the outbox in batches of limit until none is left or a message fails,
+// and returns the number of delivered messages. Messages are delivered when their response has a 2xx status,
+// other responses are reported to Failed as a *StatusError. Errors returned by the outbox stop the delivery.
+func (c *Client) DeliverOutbox(ctx context.Context, outbox Outbox, limit int) (int, error) {
+	delivered := 0
+	for {
+		messages, err := outbox.Pending(ctx, limit)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to read outbox: %w", err)
+		}
+		if len(messages) == 0 {
+			return delivered, nil
+		}
+
+		failed := false
+		for _, msg := range messages {
+			ok, err := c.deliverOutboxMessage(ctx, outbox, msg)
+			if err != nil {
+				return delivered, err
+			}
+
+			if ok {
+				delivered++
+			} else {
+				failed = true
+			}
+		}
+
+		// Failed messages may still be pending, so the next batch would deliver them again right away.
+		if failed || len(messages) < limit {
+			return delivered, nil
+		}
+	}
+}
+
+// deliverOutboxMessage sends the message and marks it, reporting whether it was delivered.
+func (c *Client) deliverOutboxMessage(ctx context.Context, outbox Outbox, msg OutboxMessage) (bool, error) {
+	req, err := msg.Snapshot.Request(ctx)
+	if err != nil {
+		return false, outboxMark(outbox.Failed(ctx, msg, err))
+	}
+
+	if msg.ID != "" && req.Header.Get("Idempotency-Key") == "" {
+		req.Header.Set("Idempotency-Key", msg.ID)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return false, outboxMark(outbox.Failed(ctx, msg, err))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, outboxMark(outbox.Failed(ctx, msg, newStatusError(req, resp)))
+	}
+	defer discardBody(resp)
+
+	if err := outbox.Delivered(ctx, msg, resp); err != nil {
+		return false, outboxMark(err)
+	}
+
+	return true, nil
+}
+
+// outboxMark wraps the error of the outbox when marking a message.
+func outboxMark(err error) error {
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type memoryOutbox struct {
+	mu           sync.Mutex
+	pending      []clink.OutboxMessage
+	delivered    []string
+	failed       map[string]error
+	deliveredErr error
+}
+
+func (o *memoryOutbox) Pending(_ context.Context, limit int) ([]clink.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var messages []clink.OutboxMessage
+	for _, msg := range o.pending {
+		if _, failed := o.failed[msg.ID]; !failed && len(messages) < limit {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages, nil
+}
+
+func (o *memoryOutbox) Delivered(_ context.Context, msg clink.OutboxMessage, _ *http.Response) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.deliveredErr != nil {
+		return o.deliveredErr
+	}
+
+	o.delivered = append(o.delivered, msg.ID)
+	for i, pending := range o.pending {
+		if pending.ID == msg.ID {
+			o.pending = append(o.pending[:i], o.pending[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (o *memoryOutbox) Failed(_ context.Context, msg clink.OutboxMessage, err error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.failed[msg.ID] = err
+
+	return nil
+}
+
+func TestClient_DeliverOutbox(t *testing.T) {
+	var mu sync.Mutex
+	keys := make(map[string]string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys[r.URL.Path] = r.Header.Get("Idempotency-Key")
+		mu.Unlock()
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	message := func(id, path string) clink.OutboxMessage {
+		return clink.OutboxMessage{ID: id, Snapshot: &clink.Snapshot{Method: http.MethodPost, URL: server.URL + path, Body: []byte(`{}`)}}
+	}
+
+	tests := []struct {
+		name          string
+		messages      []clink.OutboxMessage
+		deliveredErr  error
+		wantDelivered int
+		wantFailed    []string
+		wantErr       bool
+	}{
+		{
+			name:          "delivers every batch",
+			messages:      []clink.OutboxMessage{message("1", "/a"), message("2", "/b"), message("3", "/c")},
+			wantDelivered: 3,
+		},
+		{
+			name:          "reports failures",
+			messages:      []clink.OutboxMessage{message("1", "/a"), message("2", "/fail")},
+			wantDelivered: 1,
+			wantFailed:    []string{"2"},
+		},
+		{
+			name:         "stops when marking fails",
+			messages:     []clink.OutboxMessage{message("1", "/a")},
+			deliveredErr: errors.New("transaction aborted"),
+			wantErr:      true,
+		},
+	}
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &memoryOutbox{pending: tt.messages, failed: make(map[string]error), deliveredErr: tt.deliveredErr}
+
+			delivered, err := client.DeliverOutbox(context.Background(), outbox, 2)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if delivered != tt.wantDelivered || len(outbox.delivered) != tt.wantDelivered {
+				t.Errorf("expected %d delivered messages, got %d %v", tt.wantDelivered, delivered, outbox.delivered)
+			}
+
+			for _, id := range tt.wantFailed {
+				var statusErr *clink.StatusError
+				if !errors.As(outbox.failed[id], &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+					t.Errorf("expected message %s to fail with a status error, got %v", id, outbox.failed[id])
+				}
+			}
+		})
+	}
+
+	if keys["/a"] != "1" || keys["/fail"] != "2" {
+		t.Errorf("expected message IDs as idempotency keys, got %v", keys)
+	}
+}
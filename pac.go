@@ -0,0 +1,728 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidPAC is returned when a proxy auto-config script cannot be parsed or evaluated.
+var ErrInvalidPAC = errors.New("invalid pac script")
+
+// PAC evaluates a proxy auto-config script for a URL, returning the result of its FindProxyForURL function,
+// such as "PROXY proxy.corp:8080; DIRECT". Implementations backed by a JavaScript engine can be used for
+// scripts beyond the subset supported by ParsePAC.
+type PAC interface {
+	FindProxyForURL(u *url.URL, host string) (string, error)
+}
+
+// WithPAC sends each request through the proxy chosen by the proxy auto-config script for its URL.
+// The first PROXY, HTTPS, SOCKS or DIRECT entry of the result is used; other entries are fallbacks
+// that are not tried. Requests fail if the script fails to evaluate.
+func WithPAC(pac PAC) Option {
+	return func(c *Client) {
+		if pac == nil {
+			return
+		}
+
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			t.Proxy = func(req *http.Request) (*url.URL, error) {
+				result, err := pac.FindProxyForURL(req.URL, req.URL.Hostname())
+				if err != nil {
+					return nil, fmt.Errorf("failed to evaluate pac script: %w", err)
+				}
+
+				return pacProxy(result)
+			}
+		})
+	}
+}
+
+// LoadPAC fetches the proxy auto-config script at url, usually served as application/x-ns-proxy-autoconfig,
+// and parses it with ParsePAC.
+func (c *Client) LoadPAC(ctx context.Context, url string) (PAC, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError(req, resp)
+	}
+
+	script, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pac script: %w", err)
+	}
+
+	return ParsePAC(string(script))
+}
+
+// pacProxy returns the proxy URL of the first entry of a FindProxyForURL result, or nil for DIRECT.
+func pacProxy(result string) (*url.URL, error) {
+	for _, entry := range strings.Split(result, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var scheme string
+		switch strings.ToUpper(fields[0]) {
+		case "DIRECT":
+			return nil, nil
+		case "PROXY", "HTTP":
+			scheme = "http"
+		case "HTTPS":
+			scheme = "https"
+		case "SOCKS", "SOCKS5":
+			scheme = "socks5"
+		default:
+			continue
+		}
+
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w: %s entry without address", ErrInvalidPAC, fields[0])
+		}
+
+		return &url.URL{Scheme: scheme, Host: fields[1]}, nil
+	}
+
+	return nil, nil
+}
+
+// ParsePAC parses a proxy auto-config script written in the declarative subset of JavaScript used by most PAC files:
+// a FindProxyForURL(url, host) function made of var declarations, if/else statements and return statements, with
+// string concatenation, comparisons, boolean operators and the shExpMatch, dnsDomainIs, localHostOrDomainIs,
+// isPlainHostName, dnsDomainLevels, isResolvable, dnsResolve, isInNet and myIpAddress functions.
+// Scripts using other constructs fail with ErrInvalidPAC.
+func ParsePAC(script string) (PAC, error) {
+	tokens, err := pacTokenize(script)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pacParser{tokens: tokens}
+	for !p.done() && !(p.peek().text == "function" && p.peekAt(1).text == "FindProxyForURL") {
+		p.pos++
+	}
+	if p.done() {
+		return nil, fmt.Errorf("%w: missing FindProxyForURL function", ErrInvalidPAC)
+	}
+	p.pos += 2
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var params []string
+	for p.peek().text != ")" {
+		if p.peek().kind != pacTokenIdent {
+			return nil, p.errorf("expected parameter name")
+		}
+		params = append(params, p.next().text)
+		if p.peek().text == "," {
+			p.pos++
+		}
+	}
+	p.pos++
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%w: FindProxyForURL must take 2 parameters", ErrInvalidPAC)
+	}
+
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pacScript{urlParam: params[0], hostParam: params[1], body: body}, nil
+}
+
+type pacScript struct {
+	urlParam  string
+	hostParam string
+	body      pacStmt
+}
+
+// FindProxyForURL implements PAC.
+func (s *pacScript) FindProxyForURL(u *url.URL, host string) (string, error) {
+	vars := map[string]any{s.urlParam: u.String(), s.hostParam: host}
+
+	result, returned, err := s.body(vars)
+	if err != nil {
+		return "", err
+	}
+	if !returned {
+		return "", fmt.Errorf("%w: FindProxyForURL returned nothing", ErrInvalidPAC)
+	}
+
+	return pacString(result), nil
+}
+
+type pacTokenKind int
+
+const (
+	pacTokenEOF pacTokenKind = iota
+	pacTokenIdent
+	pacTokenString
+	pacTokenNumber
+	pacTokenPunct
+)
+
+type pacToken struct {
+	kind pacTokenKind
+	text string
+}
+
+// pacPunctuation lists the operators of the subset, longest first.
+var pacPunctuation = []string{"===", "!==", "==", "!=", "<=", ">=", "&&", "||", "=", "(", ")", "{", "}", ";", ",", "!", "<", ">", "+"}
+
+// pacTokenize splits the script into tokens, skipping comments.
+func pacTokenize(script string) ([]pacToken, error) {
+	var tokens []pacToken
+	for i := 0; i < len(script); {
+		ch := script[i]
+		switch {
+		case unicode.IsSpace(rune(ch)):
+			i++
+		case strings.HasPrefix(script[i:], "//"):
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated comment", ErrInvalidPAC)
+			}
+			i += end + 4
+		case ch == '"' || ch == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(script) && script[j] != ch; j++ {
+				if script[j] == '\\' && j+1 < len(script) {
+					j++
+				}
+				b.WriteByte(script[j])
+			}
+			if j >= len(script) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidPAC)
+			}
+			tokens = append(tokens, pacToken{kind: pacTokenString, text: b.String()})
+			i = j + 1
+		case ch == '_' || ch == '$' || unicode.IsLetter(rune(ch)):
+			j := i
+			for j < len(script) && (script[j] == '_' || script[j] == '$' || unicode.IsLetter(rune(script[j])) || unicode.IsDigit(rune(script[j]))) {
+				j++
+			}
+			tokens = append(tokens, pacToken{kind: pacTokenIdent, text: script[i:j]})
+			i = j
+		case unicode.IsDigit(rune(ch)):
+			j := i
+			for j < len(script) && (unicode.IsDigit(rune(script[j])) || script[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, pacToken{kind: pacTokenNumber, text: script[i:j]})
+			i = j
+		default:
+			matched := false
+			for _, punct := range pacPunctuation {
+				if strings.HasPrefix(script[i:], punct) {
+					tokens = append(tokens, pacToken{kind: pacTokenPunct, text: punct})
+					i += len(punct)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidPAC, ch)
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+// pacStmt executes a statement, reporting the returned value if it returned.
+type pacStmt func(vars map[string]any) (any, bool, error)
+
+// pacExpr evaluates an expression.
+type pacExpr func(vars map[string]any) (any, error)
+
+type pacParser struct {
+	tokens []pacToken
+	pos    int
+}
+
+func (p *pacParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *pacParser) peekAt(offset int) pacToken {
+	if p.pos+offset >= len(p.tokens) {
+		return pacToken{kind: pacTokenEOF}
+	}
+
+	return p.tokens[p.pos+offset]
+}
+
+func (p *pacParser) peek() pacToken {
+	return p.peekAt(0)
+}
+
+func (p *pacParser) next() pacToken {
+	token := p.peek()
+	p.pos++
+
+	return token
+}
+
+func (p *pacParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s near %q", ErrInvalidPAC, fmt.Sprintf(format, args...), p.peek().text)
+}
+
+func (p *pacParser) expect(text string) error {
+	if p.peek().text != text || p.peek().kind == pacTokenString {
+		return p.errorf("expected %q", text)
+	}
+	p.pos++
+
+	return nil
+}
+
+// block parses statements between braces.
+func (p *pacParser) block() (pacStmt, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var stmts []pacStmt
+	for p.peek().text != "}" {
+		if p.done() {
+			return nil, p.errorf("unterminated block")
+		}
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+	p.pos++
+
+	return func(vars map[string]any) (any, bool, error) {
+		for _, stmt := range stmts {
+			if result, returned, err := stmt(vars); err != nil || returned {
+				return result, returned, err
+			}
+		}
+		return nil, false, nil
+	}, nil
+}
+
+// statement parses a block, var declaration, if statement or return statement.
+func (p *pacParser) statement() (pacStmt, error) {
+	token := p.peek()
+	switch {
+	case token.kind == pacTokenPunct && token.text == "{":
+		return p.block()
+	case token.kind == pacTokenPunct && token.text == ";":
+		p.pos++
+		return func(map[string]any) (any, bool, error) { return nil, false, nil }, nil
+	case token.kind == pacTokenIdent && token.text == "var":
+		p.pos++
+		name := p.next()
+		if name.kind != pacTokenIdent {
+			return nil, p.errorf("expected variable name")
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSemicolon()
+		return func(vars map[string]any) (any, bool, error) {
+			v, err := value(vars)
+			vars[name.text] = v
+			return nil, false, err
+		}, nil
+	case token.kind == pacTokenIdent && token.text == "return":
+		p.pos++
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSemicolon()
+		return func(vars map[string]any) (any, bool, error) {
+			v, err := value(vars)
+			return v, err == nil, err
+		}, nil
+	case token.kind == pacTokenIdent && token.text == "if":
+		return p.ifStatement()
+	}
+
+	return nil, p.errorf("unsupported statement")
+}
+
+func (p *pacParser) skipSemicolon() {
+	if p.peek().kind == pacTokenPunct && p.peek().text == ";" {
+		p.pos++
+	}
+}
+
+// ifStatement parses an if statement with an optional else branch.
+func (p *pacParser) ifStatement() (pacStmt, error) {
+	p.pos++
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	cond, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	then, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+
+	otherwise := pacStmt(func(map[string]any) (any, bool, error) { return nil, false, nil })
+	if p.peek().kind == pacTokenIdent && p.peek().text == "else" {
+		p.pos++
+		if otherwise, err = p.statement(); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(vars map[string]any) (any, bool, error) {
+		v, err := cond(vars)
+		if err != nil {
+			return nil, false, err
+		}
+		if pacTruthy(v) {
+			return then(vars)
+		}
+		return otherwise(vars)
+	}, nil
+}
+
+// expression parses an expression, from the lowest precedence operator.
+func (p *pacParser) expression() (pacExpr, error) {
+	return p.binary(0)
+}
+
+// pacPrecedence lists the binary operators by increasing precedence.
+var pacPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "===", "!=", "!=="},
+	{"<", ">", "<=", ">="},
+	{"+"},
+}
+
+// binary parses the binary operators of the precedence level and above.
+func (p *pacParser) binary(level int) (pacExpr, error) {
+	if level == len(pacPrecedence) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		token := p.peek()
+		if token.kind != pacTokenPunct || !pacContains(pacPrecedence[level], token.text) {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = pacOperator(token.text, left, right)
+	}
+}
+
+// unary parses negations and primary expressions.
+func (p *pacParser) unary() (pacExpr, error) {
+	if token := p.peek(); token.kind == pacTokenPunct && token.text == "!" {
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]any) (any, error) {
+			v, err := operand(vars)
+			return !pacTruthy(v), err
+		}, nil
+	}
+
+	return p.primary()
+}
+
+// primary parses literals, variables, function calls and parenthesized expressions.
+func (p *pacParser) primary() (pacExpr, error) {
+	token := p.next()
+	switch token.kind {
+	case pacTokenString:
+		return func(map[string]any) (any, error) { return token.text, nil }, nil
+	case pacTokenNumber:
+		n, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q", ErrInvalidPAC, token.text)
+		}
+		return func(map[string]any) (any, error) { return n, nil }, nil
+	case pacTokenPunct:
+		if token.text != "(" {
+			break
+		}
+		inner, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case pacTokenIdent:
+		switch token.text {
+		case "true", "false":
+			value := token.text == "true"
+			return func(map[string]any) (any, error) { return value, nil }, nil
+		}
+
+		if p.peek().kind != pacTokenPunct || p.peek().text != "(" {
+			return func(vars map[string]any) (any, error) {
+				v, ok := vars[token.text]
+				if !ok {
+					return nil, fmt.Errorf("%w: undefined variable %s", ErrInvalidPAC, token.text)
+				}
+				return v, nil
+			}, nil
+		}
+
+		fn, ok := pacFunctions[token.text]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported function %s", ErrInvalidPAC, token.text)
+		}
+
+		p.pos++
+		var args []pacExpr
+		for p.peek().text != ")" || p.peek().kind == pacTokenString {
+			if p.done() {
+				return nil, p.errorf("unterminated call")
+			}
+			arg, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind == pacTokenPunct && p.peek().text == "," {
+				p.pos++
+			}
+		}
+		p.pos++
+
+		return func(vars map[string]any) (any, error) {
+			values := make([]string, len(args))
+			for i, arg := range args {
+				v, err := arg(vars)
+				if err != nil {
+					return nil, err
+				}
+				values[i] = pacString(v)
+			}
+			return fn(values)
+		}, nil
+	}
+
+	p.pos--
+
+	return nil, p.errorf("unexpected token")
+}
+
+// pacOperator returns the expression applying the binary operator.
+func pacOperator(op string, left, right pacExpr) pacExpr {
+	return func(vars map[string]any) (any, error) {
+		l, err := left(vars)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case "||":
+			if pacTruthy(l) {
+				return l, nil
+			}
+			return right(vars)
+		case "&&":
+			if !pacTruthy(l) {
+				return l, nil
+			}
+			return right(vars)
+		}
+
+		r, err := right(vars)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case "==", "===":
+			return pacString(l) == pacString(r), nil
+		case "!=", "!==":
+			return pacString(l) != pacString(r), nil
+		case "+":
+			ln, lok := l.(float64)
+			rn, rok := r.(float64)
+			if lok && rok {
+				return ln + rn, nil
+			}
+			return pacString(l) + pacString(r), nil
+		}
+
+		ln, lok := l.(float64)
+		rn, rok := r.(float64)
+		if !lok || !rok {
+			return nil, fmt.Errorf("%w: %s compares non numbers", ErrInvalidPAC, op)
+		}
+
+		switch op {
+		case "<":
+			return ln < rn, nil
+		case ">":
+			return ln > rn, nil
+		case "<=":
+			return ln <= rn, nil
+		default:
+			return ln >= rn, nil
+		}
+	}
+}
+
+// pacFunctions are the PAC functions supported by ParsePAC.
+var pacFunctions = map[string]func(args []string) (any, error){
+	"isPlainHostName": func(args []string) (any, error) {
+		return !strings.Contains(pacArg(args, 0), "."), nil
+	},
+	"dnsDomainIs": func(args []string) (any, error) {
+		return strings.HasSuffix(strings.ToLower(pacArg(args, 0)), strings.ToLower(pacArg(args, 1))), nil
+	},
+	"localHostOrDomainIs": func(args []string) (any, error) {
+		host, hostDomain := strings.ToLower(pacArg(args, 0)), strings.ToLower(pacArg(args, 1))
+		return host == hostDomain || (!strings.Contains(host, ".") && strings.HasPrefix(hostDomain, host+".")), nil
+	},
+	"dnsDomainLevels": func(args []string) (any, error) {
+		return float64(strings.Count(pacArg(args, 0), ".")), nil
+	},
+	"shExpMatch": func(args []string) (any, error) {
+		pattern := regexp.QuoteMeta(pacArg(args, 1))
+		pattern = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(pattern)
+		return regexp.MatchString("^"+pattern+"$", pacArg(args, 0))
+	},
+	"isResolvable": func(args []string) (any, error) {
+		return pacResolve(pacArg(args, 0)) != "", nil
+	},
+	"dnsResolve": func(args []string) (any, error) {
+		return pacResolve(pacArg(args, 0)), nil
+	},
+	"isInNet": func(args []string) (any, error) {
+		ip := net.ParseIP(pacResolve(pacArg(args, 0)))
+		network, mask := net.ParseIP(pacArg(args, 1)), net.ParseIP(pacArg(args, 2))
+		if ip == nil || network == nil || mask == nil || ip.To4() == nil || network.To4() == nil || mask.To4() == nil {
+			return false, nil
+		}
+		m := net.IPMask(mask.To4())
+		return ip.To4().Mask(m).Equal(network.To4().Mask(m)), nil
+	},
+	"myIpAddress": func([]string) (any, error) {
+		conn, err := net.Dial("udp", "192.0.2.1:80")
+		if err != nil {
+			return "127.0.0.1", nil
+		}
+		defer func() { _ = conn.Close() }()
+		return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+	},
+}
+
+// pacResolve returns the first IPv4 address of the host, or the host itself if it is an IP address.
+func pacResolve(host string) string {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return ""
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String()
+		}
+	}
+
+	return ""
+}
+
+func pacArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+
+	return ""
+}
+
+func pacContains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pacTruthy converts a value to a boolean as JavaScript does.
+func pacTruthy(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	}
+
+	return false
+}
+
+// pacString converts a value to a string as JavaScript does.
+func pacString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return "undefined"
+}
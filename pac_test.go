@@ -0,0 +1,135 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+const corporatePAC = `
+// Corporate proxy configuration.
+function FindProxyForURL(url, host) {
+	var proxy = "PROXY proxy.corp:8080; DIRECT";
+
+	/* Internal hosts are reached directly. */
+	if (isPlainHostName(host) || dnsDomainIs(host, ".intranet.corp") || localHostOrDomainIs(host, "wiki.corp"))
+		return "DIRECT";
+
+	if (shExpMatch(host, "10.*") && isInNet(host, "10.0.0.0", "255.0.0.0")) {
+		return "DIRECT";
+	} else if (shExpMatch(url, "https://*.example.com/*") && !(host == "public.example.com")) {
+		return "HTTPS secure.corp:443";
+	}
+
+	if (dnsDomainLevels(host) > 3)
+		return 'SOCKS socks.corp:1080';
+
+	return proxy;
+}
+`
+
+func TestParsePAC(t *testing.T) {
+	pac, err := clink.ParsePAC(corporatePAC)
+	if err != nil {
+		t.Fatalf("failed to parse pac: %v", err)
+	}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "http://wiki/page", want: "DIRECT"},
+		{url: "http://docs.intranet.corp/", want: "DIRECT"},
+		{url: "http://wiki.corp/", want: "DIRECT"},
+		{url: "http://10.1.2.3/", want: "DIRECT"},
+		{url: "https://api.example.com/v1", want: "HTTPS secure.corp:443"},
+		{url: "https://public.example.com/v1", want: "PROXY proxy.corp:8080; DIRECT"},
+		{url: "http://a.b.c.d.org/", want: "SOCKS socks.corp:1080"},
+		{url: "http://golang.org/", want: "PROXY proxy.corp:8080; DIRECT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			got, err := pac.FindProxyForURL(u, u.Hostname())
+			if err != nil {
+				t.Fatalf("failed to evaluate pac: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParsePAC_Invalid(t *testing.T) {
+	scripts := []string{
+		`var x = 1;`,
+		`function FindProxyForURL(url, host) { return weekdayRange("MON", "FRI"); }`,
+		`function FindProxyForURL(url, host) { for (;;) {} }`,
+		`function FindProxyForURL(url, host) { return "DIRECT"`,
+	}
+
+	for _, script := range scripts {
+		if _, err := clink.ParsePAC(script); !errors.Is(err, clink.ErrInvalidPAC) {
+			t.Errorf("expected ErrInvalidPAC for %q, got %v", script, err)
+		}
+	}
+}
+
+func TestWithPAC(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("direct"))
+	}))
+	defer target.Close()
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("proxied " + r.URL.String()))
+	}))
+	defer proxy.Close()
+	proxyHost := strings.TrimPrefix(proxy.URL, "http://")
+
+	pacServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ns-proxy-autoconfig")
+		_, _ = w.Write([]byte(`function FindProxyForURL(url, host) {
+			if (shExpMatch(host, "*.proxied.test")) return "PROXY ` + proxyHost + `";
+			return "DIRECT";
+		}`))
+	}))
+	defer pacServer.Close()
+
+	pac, err := clink.NewClient().LoadPAC(context.Background(), pacServer.URL+"/proxy.pac")
+	if err != nil {
+		t.Fatalf("failed to load pac: %v", err)
+	}
+
+	client := clink.NewClient(clink.WithPAC(pac), clink.WithClient(&http.Client{Transport: &http.Transport{}}))
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: target.URL, want: "direct"},
+		{url: "http://api.proxied.test/v1", want: "proxied http://api.proxied.test/v1"},
+	}
+
+	for _, tt := range tests {
+		resp, err := client.Get(tt.url)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if string(body) != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, body)
+		}
+	}
+}
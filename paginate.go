@@ -0,0 +1,219 @@
+package clink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ErrPaginationLimit is returned along with the items collected so far when a pagination limit stops the collection
+// before the last page.
+var ErrPaginationLimit = errors.New("pagination limit reached")
+
+// defaultMaxPages bounds the number of pages collected when no limit is given, to stop on endless pagination.
+const defaultMaxPages = 1000
+
+// Pagination decodes the items of a page and finds the request for the next page.
+type Pagination[T any] interface {
+	// Page decodes the items of the response to req and returns the request for the next page, or nil after the last page.
+	Page(req *http.Request, resp *http.Response) (items []T, next *http.Request, err error)
+}
+
+// PaginationFunc is a function implementing Pagination.
+type PaginationFunc[T any] func(req *http.Request, resp *http.Response) ([]T, *http.Request, error)
+
+// Page implements Pagination.
+func (f PaginationFunc[T]) Page(req *http.Request, resp *http.Response) ([]T, *http.Request, error) {
+	return f(req, resp)
+}
+
+// CollectOption configures the limits of CollectAll.
+type CollectOption func(*collectConfig)
+
+type collectConfig struct {
+	maxPages int
+	maxItems int
+}
+
+// MaxPages stops collecting after n pages. Defaults to 1000.
+func MaxPages(n int) CollectOption {
+	return func(cfg *collectConfig) {
+		cfg.maxPages = n
+	}
+}
+
+// MaxItems stops collecting once n items have been collected.
+func MaxItems(n int) CollectOption {
+	return func(cfg *collectConfig) {
+		cfg.maxItems = n
+	}
+}
+
+// CollectAll sends req and the requests for every following page found by the pagination, returning the items of all pages.
+// When a limit stops the collection before the last page, the items collected so far are returned with ErrPaginationLimit.
+func CollectAll[T any](ctx context.Context, c *Client, req *http.Request, pagination Pagination[T], opts ...CollectOption) ([]T, error) {
+	cfg := &collectConfig{maxPages: defaultMaxPages}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var items []T
+	for page := 0; req != nil; page++ {
+		if cfg.maxPages > 0 && page >= cfg.maxPages {
+			return items, fmt.Errorf("%w: %d pages", ErrPaginationLimit, cfg.maxPages)
+		}
+
+		req = req.WithContext(ctx)
+		resp, err := c.Do(req)
+		if err != nil {
+			return items, err
+		}
+
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			return items, newStatusError(req, resp)
+		}
+
+		pageItems, next, err := pagination.Page(req, resp)
+		if err != nil {
+			return items, fmt.Errorf("failed to read page %d: %w", page+1, err)
+		}
+
+		items = append(items, pageItems...)
+		if cfg.maxItems > 0 && len(items) >= cfg.maxItems {
+			if len(items) > cfg.maxItems || next != nil {
+				return items[:cfg.maxItems], fmt.Errorf("%w: %d items", ErrPaginationLimit, cfg.maxItems)
+			}
+			return items, nil
+		}
+
+		req = next
+	}
+
+	return items, nil
+}
+
+// LinkHeaderPagination decodes pages holding a JSON array of items and follows the "next" relation of the Link header,
+// as done by the GitHub API.
+func LinkHeaderPagination[T any]() Pagination[T] {
+	return PaginationFunc[T](func(req *http.Request, resp *http.Response) ([]T, *http.Request, error) {
+		links := ResponseLinks(resp)
+
+		var items []T
+		if err := ResponseToJson(resp, &items, AllowNoContent()); err != nil {
+			return nil, nil, err
+		}
+
+		next, ok := links.Get("next")
+		if !ok {
+			return items, nil, nil
+		}
+
+		nextReq, err := http.NewRequestWithContext(req.Context(), req.Method, next.Href, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		nextReq.Header = req.Header.Clone()
+
+		return items, nextReq, nil
+	})
+}
+
+// ODataPagination decodes the "value" of OData collection pages and follows their "@odata.nextLink".
+func ODataPagination[T any]() Pagination[T] {
+	return PaginationFunc[T](func(req *http.Request, resp *http.Response) ([]T, *http.Request, error) {
+		var body struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"@odata.nextLink"`
+		}
+		if err := ResponseToJson(resp, &body); err != nil {
+			return nil, nil, err
+		}
+
+		if body.NextLink == "" {
+			return body.Value, nil, nil
+		}
+
+		next, err := http.NewRequestWithContext(req.Context(), req.Method, body.NextLink, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		next.Header = req.Header.Clone()
+
+		return body.Value, next, nil
+	})
+}
+
+// CursorPagination decodes pages holding a JSON object with the items in itemsField and the next cursor in cursorField,
+// and requests the next page by setting the cursor as the cursorParam query parameter. An empty or null cursor ends the pagination.
+func CursorPagination[T any](itemsField, cursorField, cursorParam string) Pagination[T] {
+	return PaginationFunc[T](func(req *http.Request, resp *http.Response) ([]T, *http.Request, error) {
+		var body map[string]json.RawMessage
+		if err := ResponseToJson(resp, &body); err != nil {
+			return nil, nil, err
+		}
+
+		var items []T
+		if raw, ok := body[itemsField]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, nil, &DecodeError{Content: "items", Err: err}
+			}
+		}
+
+		var cursor any
+		if raw, ok := body[cursorField]; ok {
+			_ = json.Unmarshal(raw, &cursor)
+		}
+
+		value := ""
+		switch v := cursor.(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if value == "" {
+			return items, nil, nil
+		}
+
+		next := req.Clone(req.Context())
+		u := *req.URL
+		query := u.Query()
+		query.Set(cursorParam, value)
+		u.RawQuery = query.Encode()
+		next.URL = &u
+
+		return items, next, nil
+	})
+}
+
+// PagePagination decodes pages holding a JSON array of items and requests the next page by incrementing the
+// pageParam query parameter, starting at 1 when it is not set. An empty page ends the pagination.
+func PagePagination[T any](pageParam string) Pagination[T] {
+	return PaginationFunc[T](func(req *http.Request, resp *http.Response) ([]T, *http.Request, error) {
+		var items []T
+		if err := ResponseToJson(resp, &items, AllowNoContent()); err != nil {
+			return nil, nil, err
+		}
+
+		if len(items) == 0 {
+			return items, nil, nil
+		}
+
+		page, err := strconv.Atoi(req.URL.Query().Get(pageParam))
+		if err != nil {
+			page = 1
+		}
+
+		next := req.Clone(req.Context())
+		u := *req.URL
+		query := u.Query()
+		query.Set(pageParam, strconv.Itoa(page+1))
+		u.RawQuery = query.Encode()
+		next.URL = &u
+
+		return items, next, nil
+	})
+}
@@ -0,0 +1,140 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type paginatedItem struct {
+	ID int `json:"id"`
+}
+
+func TestCollectAll(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/link":
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page < 2 {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/link?page=%d>; rel="next"`, server.URL, page+1))
+			}
+			_ = json.NewEncoder(w).Encode([]paginatedItem{{ID: page*2 + 1}, {ID: page*2 + 2}})
+		case "/cursor":
+			body := map[string]any{"data": []paginatedItem{{ID: 1}}, "next_cursor": "abc"}
+			if r.URL.Query().Get("cursor") == "abc" {
+				body = map[string]any{"data": []paginatedItem{{ID: 2}}, "next_cursor": nil}
+			}
+			_ = json.NewEncoder(w).Encode(body)
+		case "/page":
+			page, err := strconv.Atoi(r.URL.Query().Get("page"))
+			if err != nil {
+				page = 1
+			}
+			items := []paginatedItem{}
+			if page <= 3 {
+				items = append(items, paginatedItem{ID: page})
+			}
+			_ = json.NewEncoder(w).Encode(items)
+		case "/endless":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/endless>; rel="next"`, server.URL))
+			_ = json.NewEncoder(w).Encode([]paginatedItem{{ID: 1}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClient(server.Client()))
+
+	testCases := []struct {
+		name       string
+		path       string
+		pagination clink.Pagination[paginatedItem]
+		opts       []clink.CollectOption
+		expected   []int
+		err        error
+	}{
+		{
+			name:       "link header",
+			path:       "/link",
+			pagination: clink.LinkHeaderPagination[paginatedItem](),
+			expected:   []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:       "cursor",
+			path:       "/cursor",
+			pagination: clink.CursorPagination[paginatedItem]("data", "next_cursor", "cursor"),
+			expected:   []int{1, 2},
+		},
+		{
+			name:       "page number",
+			path:       "/page",
+			pagination: clink.PagePagination[paginatedItem]("page"),
+			expected:   []int{1, 2, 3},
+		},
+		{
+			name:       "page limit",
+			path:       "/endless",
+			pagination: clink.LinkHeaderPagination[paginatedItem](),
+			opts:       []clink.CollectOption{clink.MaxPages(3)},
+			expected:   []int{1, 1, 1},
+			err:        clink.ErrPaginationLimit,
+		},
+		{
+			name:       "item limit",
+			path:       "/link",
+			pagination: clink.LinkHeaderPagination[paginatedItem](),
+			opts:       []clink.CollectOption{clink.MaxItems(3)},
+			expected:   []int{1, 2, 3},
+			err:        clink.ErrPaginationLimit,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tc.path, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			items, err := clink.CollectAll(context.Background(), c, req, tc.pagination, tc.opts...)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+
+			if len(items) != len(tc.expected) {
+				t.Fatalf("expected %d items, got %d", len(tc.expected), len(items))
+			}
+			for i, item := range items {
+				if item.ID != tc.expected[i] {
+					t.Errorf("expected item %d to have id %d, got %d", i, tc.expected[i], item.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectAll_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClient(server.Client()))
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	_, err := clink.CollectAll(context.Background(), c, req, clink.LinkHeaderPagination[paginatedItem]())
+
+	var statusErr *clink.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
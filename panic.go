@@ -0,0 +1,98 @@
+package clink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// ErrPanic is matched by the *PanicError returned when a user provided function panics.
+var ErrPanic = errors.New("panic")
+
+// PanicError is returned instead of crashing when a middleware, hook, ShouldRetryFunc or BackoffFunc panics
+// while the client sends a request. The response body, if any, is closed.
+type PanicError struct {
+	// Value is the value the function panicked with.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Is makes errors.Is match ErrPanic.
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+func newPanicError(value any) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+// recordPanic recovers a panic of a hook observing the request, to be deferred. The panic is returned by Do
+// in place of the response, as hooks cannot return errors. Panics of hooks without request are dropped.
+func recordPanic(req *http.Request) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	if stats, ok := requestValue[*requestStats](req, requestStatsKey{}); ok && stats.panic == nil {
+		stats.panic = newPanicError(value)
+	}
+}
+
+// handle sends the request through the middleware and the http client of the request.
+func (c *Client) handle(req *http.Request) (*http.Response, error) {
+	return c.handleWith(req, c.doWithServerName)
+}
+
+// handleWith sends the request through the middleware to send, recovering their panics. The response returned by
+// send is closed if a middleware panics after receiving it.
+func (c *Client) handleWith(req *http.Request, send Handler) (resp *http.Response, err error) {
+	var received *http.Response
+	defer func() {
+		if value := recover(); value != nil {
+			discardBody(received)
+			resp, err = nil, newPanicError(value)
+		}
+	}()
+
+	return c.handler(req, func(req *http.Request) (*http.Response, error) {
+		resp, err := send(req)
+		received = resp
+		return resp, err
+	})(req)
+}
+
+// callShouldRetry calls the retry function, recovering its panics.
+func callShouldRetry(shouldRetry func(*http.Request, *http.Response, error) bool, req *http.Request, resp *http.Response, err error) (retry bool, panicErr error) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr = newPanicError(value)
+		}
+	}()
+
+	return shouldRetry(req, resp, err), nil
+}
+
+// backoff returns the delay before retrying the given attempt, defaulting to one second per attempt.
+// A panic of the backoff function is returned as a *PanicError.
+func (c *Client) backoff(attempt int, resp *http.Response) (delay time.Duration, err error) {
+	if c.BackoffFunc == nil {
+		return time.Duration(attempt) * time.Second, nil
+	}
+
+	defer func() {
+		if value := recover(); value != nil {
+			err = newPanicError(value)
+		}
+	}()
+
+	return c.BackoffFunc(attempt, resp), nil
+}
@@ -0,0 +1,71 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opt  clink.Option
+	}{
+		{
+			name: "middleware",
+			opt: clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+				return func(req *http.Request) (*http.Response, error) {
+					_, _ = next(req)
+					panic("middleware")
+				}
+			}),
+		},
+		{
+			name: "should retry",
+			opt: clink.WithRetries(1, func(*http.Request, *http.Response, error) bool {
+				panic("should retry")
+			}),
+		},
+		{
+			name: "backoff",
+			opt: clink.WithRetryPolicy(clink.RetryPolicy{
+				MaxRetries:  1,
+				ShouldRetry: clink.RetryOnStatus(http.StatusServiceUnavailable),
+				Backoff: func(int, *http.Response) time.Duration {
+					panic("backoff")
+				},
+			}),
+		},
+		{
+			name: "rate limit wait hook",
+			opt: clink.WithRateLimitWaitHook(func(*http.Request, time.Duration) {
+				panic("wait hook")
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := clink.NewClient(clink.WithRateLimit(6000), tt.opt, clink.WithClient(server.Client()))
+
+			resp, err := client.Get(server.URL)
+
+			var panicErr *clink.PanicError
+			if resp != nil || !errors.As(err, &panicErr) || !errors.Is(err, clink.ErrPanic) {
+				t.Fatalf("expected a *clink.PanicError, got: %v", err)
+			}
+			if len(panicErr.Stack) == 0 {
+				t.Error("expected the panic error to carry a stack trace")
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package clink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	// JSONPatchMediaType is the media type of JSON Patch documents (RFC 6902).
+	JSONPatchMediaType = "application/json-patch+json"
+	// MergePatchMediaType is the media type of JSON Merge Patch documents (RFC 7396).
+	MergePatchMediaType = "application/merge-patch+json"
+)
+
+// JSONPatchOperation is an operation of a JSON Patch document.
+type JSONPatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value any
+}
+
+// MarshalJSON implements json.Marshaler, only encoding the members used by the operation.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	op := map[string]any{"op": o.Op, "path": o.Path}
+	switch o.Op {
+	case "add", "replace", "test":
+		op["value"] = o.Value
+	case "move", "copy":
+		op["from"] = o.From
+	}
+
+	return json.Marshal(op)
+}
+
+// JSONPatch is a JSON Patch document built by chaining its operations.
+// Paths are JSON Pointers, see JSONPointer to build them from keys.
+type JSONPatch []JSONPatchOperation
+
+// Add adds an operation adding value at path.
+func (p JSONPatch) Add(path string, value any) JSONPatch {
+	return append(p, JSONPatchOperation{Op: "add", Path: path, Value: value})
+}
+
+// Remove adds an operation removing the value at path.
+func (p JSONPatch) Remove(path string) JSONPatch {
+	return append(p, JSONPatchOperation{Op: "remove", Path: path})
+}
+
+// Replace adds an operation replacing the value at path.
+func (p JSONPatch) Replace(path string, value any) JSONPatch {
+	return append(p, JSONPatchOperation{Op: "replace", Path: path, Value: value})
+}
+
+// Move adds an operation moving the value at from to path.
+func (p JSONPatch) Move(from, path string) JSONPatch {
+	return append(p, JSONPatchOperation{Op: "move", Path: path, From: from})
+}
+
+// Copy adds an operation copying the value at from to path.
+func (p JSONPatch) Copy(from, path string) JSONPatch {
+	return append(p, JSONPatchOperation{Op: "copy", Path: path, From: from})
+}
+
+// Test adds an operation failing the patch unless the value at path equals value.
+func (p JSONPatch) Test(path string, value any) JSONPatch {
+	return append(p, JSONPatchOperation{Op: "test", Path: path, Value: value})
+}
+
+// JSONPointer returns the JSON Pointer to the value found by following the keys.
+func JSONPointer(keys ...string) string {
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1"))
+	}
+
+	return b.String()
+}
+
+// JSONPatchBody sets the body of the request to the JSON Patch document.
+func JSONPatchBody(patch JSONPatch) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.body = func(c *Client, req *http.Request) error {
+			if patch == nil {
+				patch = JSONPatch{}
+			}
+			return c.setJSONBody(req, patch, JSONPatchMediaType)
+		}
+	}
+}
+
+// MergePatchBody sets the body of the request to the JSON Merge Patch document v, such as the result of MergePatchDiff.
+func MergePatchBody(v any) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.body = func(c *Client, req *http.Request) error {
+			return c.setJSONBody(req, v, MergePatchMediaType)
+		}
+	}
+}
+
+// MergePatchDiff returns the JSON Merge Patch turning the JSON encoding of from into the JSON encoding of to.
+// Removed members are set to null, and arrays are replaced as a whole.
+func MergePatchDiff(from, to any) (map[string]any, error) {
+	source, target, err := jsonObjects(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergePatchDiff(source, target), nil
+}
+
+func mergePatchDiff(source, target map[string]any) map[string]any {
+	patch := make(map[string]any)
+	for key := range source {
+		if _, ok := target[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	for key, value := range target {
+		old, ok := source[key]
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+
+		oldObject, oldIsObject := old.(map[string]any)
+		newObject, newIsObject := value.(map[string]any)
+		if oldIsObject && newIsObject {
+			patch[key] = mergePatchDiff(oldObject, newObject)
+			continue
+		}
+
+		patch[key] = value
+	}
+
+	return patch
+}
+
+// JSONPatchDiff returns the JSON Patch turning the JSON encoding of from into the JSON encoding of to.
+// Operations are sorted by path, and arrays are replaced as a whole.
+func JSONPatchDiff(from, to any) (JSONPatch, error) {
+	source, target, err := jsonObjects(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := jsonPatchDiff(JSONPatch{}, "", source, target)
+	sort.SliceStable(patch, func(i, j int) bool {
+		return patch[i].Path < patch[j].Path
+	})
+
+	return patch, nil
+}
+
+func jsonPatchDiff(patch JSONPatch, prefix string, source, target map[string]any) JSONPatch {
+	for key := range source {
+		if _, ok := target[key]; !ok {
+			patch = patch.Remove(prefix + JSONPointer(key))
+		}
+	}
+
+	for key, value := range target {
+		path := prefix + JSONPointer(key)
+		old, ok := source[key]
+		switch {
+		case !ok:
+			patch = patch.Add(path, value)
+		case reflect.DeepEqual(old, value):
+		default:
+			oldObject, oldIsObject := old.(map[string]any)
+			newObject, newIsObject := value.(map[string]any)
+			if oldIsObject && newIsObject {
+				patch = jsonPatchDiff(patch, path, oldObject, newObject)
+			} else {
+				patch = patch.Replace(path, value)
+			}
+		}
+	}
+
+	return patch
+}
+
+// jsonObjects returns the JSON encodings of from and to decoded as generic objects.
+func jsonObjects(from, to any) (map[string]any, map[string]any, error) {
+	source, err := jsonObject(from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := jsonObject(to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source, target, nil
+}
+
+func jsonObject(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("value must encode to a JSON object: %w", err)
+	}
+
+	return object, nil
+}
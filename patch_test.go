@@ -0,0 +1,94 @@
+package clink_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type patchedUser struct {
+	Name    string            `json:"name"`
+	Email   string            `json:"email,omitempty"`
+	Tags    []string          `json:"tags"`
+	Address map[string]string `json:"address"`
+}
+
+func TestPatchBodies(t *testing.T) {
+	var contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClient(server.Client()))
+
+	from := patchedUser{Name: "Ada", Email: "ada@example.com", Tags: []string{"a"}, Address: map[string]string{"city": "London", "zip": "N1"}}
+	to := patchedUser{Name: "Ada Lovelace", Tags: []string{"a", "b"}, Address: map[string]string{"city": "Paris", "zip": "N1"}}
+
+	mergePatch, err := clink.MergePatchDiff(from, to)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+
+	jsonPatch, err := clink.JSONPatchDiff(from, to)
+	if err != nil {
+		t.Fatalf("failed to diff: %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		option      clink.RequestOption
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "merge patch diff",
+			option:      clink.MergePatchBody(mergePatch),
+			contentType: clink.MergePatchMediaType,
+			expected:    `{"address":{"city":"Paris"},"email":null,"name":"Ada Lovelace","tags":["a","b"]}`,
+		},
+		{
+			name:        "json patch diff",
+			option:      clink.JSONPatchBody(jsonPatch),
+			contentType: clink.JSONPatchMediaType,
+			expected:    `[{"op":"replace","path":"/address/city","value":"Paris"},{"op":"remove","path":"/email"},{"op":"replace","path":"/name","value":"Ada Lovelace"},{"op":"replace","path":"/tags","value":["a","b"]}]`,
+		},
+		{
+			name: "json patch builder",
+			option: clink.JSONPatchBody(clink.JSONPatch{}.
+				Test(clink.JSONPointer("version"), 1).
+				Add(clink.JSONPointer("labels", "a/b"), nil).
+				Move("/old", "/new").
+				Remove("/tmp")),
+			contentType: clink.JSONPatchMediaType,
+			expected:    `[{"op":"test","path":"/version","value":1},{"op":"add","path":"/labels/a~1b","value":null},{"from":"/old","op":"move","path":"/new"},{"op":"remove","path":"/tmp"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := c.Patch(server.URL, nil, tc.option); err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			if contentType != tc.contentType {
+				t.Errorf("expected content type %q, got %q", tc.contentType, contentType)
+			}
+
+			var got, expected any
+			_ = json.Unmarshal([]byte(body), &got)
+			_ = json.Unmarshal([]byte(tc.expected), &expected)
+			gotJSON, _ := json.Marshal(got)
+			expectedJSON, _ := json.Marshal(expected)
+			if string(gotJSON) != string(expectedJSON) {
+				t.Errorf("expected body %s, got %s", expectedJSON, gotJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package clink
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Pool manages a client per key, such as a tenant or an API key, created on first use.
+// The clients share the transport, and so the connections, configured by the shared options,
+// while the options returned for each key, such as credentials and rate limits, are isolated to its client.
+// The least recently used clients are evicted once the pool holds more than its maximum number of clients.
+type Pool struct {
+	shared []Option
+	tenant func(key string) []Option
+	max    int
+	base   *Client
+
+	mu      sync.Mutex
+	lru     *list.List
+	clients map[string]*list.Element
+}
+
+type poolEntry struct {
+	key    string
+	client *Client
+}
+
+// NewPool creates a pool keeping at most maxClients clients, or any number of clients if maxClients is 0.
+// The client of each key is created with the shared options followed by the options returned by tenant for the key.
+// Dialer and transport options in the shared options configure the transport shared by the clients, while in the
+// options of a key they give its client a copy of that transport. The background tasks of the shared options run once
+// for the pool.
+func NewPool(maxClients int, tenant func(key string) []Option, shared ...Option) *Pool {
+	return &Pool{
+		shared:  shared,
+		tenant:  tenant,
+		max:     maxClients,
+		base:    NewClient(shared...),
+		lru:     list.New(),
+		clients: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the client of the key, creating it if needed.
+func (p *Pool) Get(key string) *Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.clients[key]; ok {
+		p.lru.MoveToFront(elem)
+		return elem.Value.(*poolEntry).client
+	}
+
+	c := p.newClient(key)
+	p.clients[key] = p.lru.PushFront(&poolEntry{key: key, client: c})
+
+	for p.max > 0 && p.lru.Len() > p.max {
+		p.remove(p.lru.Back())
+	}
+
+	return c
+}
+
+// Remove closes and removes the client of the key, if any.
+func (p *Pool) Remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.clients[key]; ok {
+		p.remove(elem)
+	}
+}
+
+// Len returns the number of clients in the pool.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lru.Len()
+}
+
+// Close closes and removes every client of the pool.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.lru.Len() > 0 {
+		p.remove(p.lru.Back())
+	}
+
+	return p.base.Close()
+}
+
+// remove closes the client of the element and removes it. It must be called with the lock held.
+func (p *Pool) remove(elem *list.Element) {
+	entry := p.lru.Remove(elem).(*poolEntry)
+	delete(p.clients, entry.key)
+	_ = entry.client.Close()
+}
+
+// newClient creates the client of the key on top of the transport of the base client.
+func (p *Pool) newClient(key string) *Client {
+	opts := append(append([]Option(nil), p.shared...), p.inheritBase)
+	if p.tenant != nil {
+		opts = append(opts, p.tenant(key)...)
+	}
+
+	return NewClient(opts...)
+}
+
+// inheritBase replaces the transport configured by the shared options with the transport of the base client,
+// before the transport wrappers are applied, and drops the background tasks already run by the base client.
+func (p *Pool) inheritBase(c *Client) {
+	c.HttpClient = p.base.HttpClient
+	if p.base.baseTransport != nil {
+		httpClient := *p.base.HttpClient
+		httpClient.Transport = p.base.baseTransport
+		c.HttpClient = &httpClient
+	}
+
+	c.dialer, c.dialNetwork, c.hostOverrides, c.transportOptions = nil, "", nil, nil
+	c.background = nil
+}
@@ -0,0 +1,125 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestPool(t *testing.T) {
+	var remoteAddrs = make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddrs[r.RemoteAddr] = true
+		w.Header().Set("X-Token", r.Header.Get("Authorization"))
+		w.Header().Set("X-Shared", r.Header.Get("X-Shared"))
+	}))
+	defer server.Close()
+
+	pool := clink.NewPool(2, func(key string) []clink.Option {
+		return []clink.Option{clink.WithBearerAuth(key)}
+	}, clink.WithHeader("X-Shared", "yes"), clink.WithClient(server.Client()))
+	defer pool.Close()
+
+	for _, key := range []string{"alice", "bob", "alice"} {
+		resp, err := pool.Get(key).Get(server.URL)
+		if err != nil {
+			t.Fatalf("request for %s failed: %v", key, err)
+		}
+		_ = resp.Body.Close()
+
+		if token := resp.Header.Get("X-Token"); token != "Bearer "+key {
+			t.Errorf("expected the token of %s, got %q", key, token)
+		}
+		if resp.Header.Get("X-Shared") != "yes" {
+			t.Errorf("expected the shared header to be sent for %s", key)
+		}
+	}
+
+	if len(remoteAddrs) != 1 {
+		t.Errorf("expected the clients to share one connection, got %d", len(remoteAddrs))
+	}
+
+	alice := pool.Get("alice")
+	if pool.Get("alice") != alice {
+		t.Error("expected the client of a key to be reused")
+	}
+
+	pool.Get("carol")
+	if pool.Len() != 2 {
+		t.Errorf("expected the pool to hold 2 clients, got %d", pool.Len())
+	}
+	if pool.Get("alice") != alice {
+		t.Error("expected the most recently used client to be kept")
+	}
+	if pool.Len() != 2 {
+		t.Errorf("expected the least recently used client to be evicted, got %d clients", pool.Len())
+	}
+
+	pool.Remove("alice")
+	if pool.Get("alice") == alice {
+		t.Error("expected a removed client to be created again")
+	}
+}
+
+func TestPool_TenantLimiterClock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool := clink.NewPool(0, func(key string) []clink.Option {
+		return []clink.Option{clink.WithLimiter(clink.NewLimiter(clink.FixedWindow, 1, time.Hour, 0))}
+	}, clink.WithClock(clock), clink.WithNonBlockingRateLimit(), clink.WithClient(server.Client()))
+	defer pool.Close()
+
+	client := pool.Get("alice")
+	get := func() error {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	var rateErr *clink.RateLimitError
+	if err := get(); !errors.As(err, &rateErr) {
+		t.Fatalf("expected the second request to be rate limited, got %v", err)
+	}
+
+	clock.Advance(time.Hour)
+	if err := get(); err != nil {
+		t.Errorf("expected the tenant limiter to use the pool clock, got %v", err)
+	}
+}
+
+func TestPool_SharedTransportWrapper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var calls int
+	pool := clink.NewPool(0, nil, clink.WithTransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return next.RoundTrip(req)
+		})
+	}), clink.WithClient(server.Client()))
+	defer pool.Close()
+
+	resp, err := pool.Get("alice").Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if calls != 1 {
+		t.Errorf("expected the shared wrapper to wrap the transport once, got %d calls", calls)
+	}
+}
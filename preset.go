@@ -0,0 +1,28 @@
+package clink
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewJSONClient returns a client for the JSON API at baseURL with defaults suited to most APIs:
+//   - requests with a relative URL are sent to baseURL
+//   - Accept is application/json, as is the Content-Type of requests with a body that have none
+//   - 4xx and 5xx responses are returned as a *StatusError
+//   - network errors, 429 and 5xx responses are retried following RetryPolicyStandard
+//   - requests time out after 30 seconds
+//   - responses are gzip compressed when the server supports it, and transparently decompressed
+//
+// The options are applied after the defaults and can override them.
+func NewJSONClient(baseURL string, opts ...Option) *Client {
+	defaults := []Option{
+		WithBaseURL(baseURL),
+		WithHeader("Accept", "application/json"),
+		WithDefaultContentType("application/json"),
+		WithStatusErrors(),
+		WithRetryPolicy(RetryPolicyStandard),
+		WithClient(&http.Client{Timeout: 30 * time.Second}),
+	}
+
+	return NewClient(append(defaults, opts...)...)
+}
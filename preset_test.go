@@ -0,0 +1,74 @@
+package clink_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestNewJSONClient(t *testing.T) {
+	var failures atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/users":
+			if r.Header.Get("Accept") != "application/json" || r.URL.Query().Get("page") != "2" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"name":"alice"}]`))
+		case "/v1/echo":
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			_, _ = io.Copy(w, r.Body)
+		case "/v1/flaky":
+			if failures.Add(1) < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewJSONClient(server.URL+"/v1/", clink.WithBackoff(func(int, *http.Response) time.Duration { return 0 }))
+
+	resp, err := c.Get("/users?page=2")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var users []struct {
+		Name string `json:"name"`
+	}
+	if err := clink.ResponseToJson(resp, &users); err != nil || len(users) != 1 || users[0].Name != "alice" {
+		t.Errorf("expected the users, got %v %v", users, err)
+	}
+
+	resp, err = c.Post("echo", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected the default content type, got %q", got)
+	}
+
+	resp, err = c.Get("/flaky")
+	if err != nil {
+		t.Fatalf("expected the request to be retried, got %v", err)
+	}
+	_ = resp.Body.Close()
+
+	var statusErr *clink.StatusError
+	if _, err := c.Get("/missing"); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a status error for a 404 response, got %v", err)
+	}
+}
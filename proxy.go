@@ -0,0 +1,209 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ProxyStrategy selects the proxy of each request of a proxy pool.
+type ProxyStrategy int
+
+const (
+	// ProxyRoundRobin uses the proxies in turn.
+	ProxyRoundRobin ProxyStrategy = iota
+	// ProxyRandom uses a random proxy.
+	ProxyRandom
+	// ProxyHealthiest uses the proxy with the best success ratio.
+	ProxyHealthiest
+)
+
+const (
+	// proxyFailureThreshold is the number of consecutive failures after which a proxy is ejected.
+	proxyFailureThreshold = 3
+	// proxyCooldown is how long an ejected proxy is left out of the rotation.
+	proxyCooldown = time.Minute
+)
+
+// ProxyStatus reports the health of a proxy of the pool.
+type ProxyStatus struct {
+	URL       string
+	Successes int
+	Failures  int
+	// EjectedUntil is when an ejected proxy is added back to the rotation, zero for proxies in rotation.
+	EjectedUntil time.Time
+}
+
+// WithProxyPool sends each request attempt through one of the proxies, chosen with the strategy.
+// Network errors and ban responses (403, 407 and 429) count as proxy failures, and proxies failing 3 times in a row
+// are left out of the rotation for a minute. Invalid proxy URLs are ignored. The proxies are set on the
+// *http.Transport of the client: with a custom transport that is not an *http.Transport, requests are sent directly.
+func WithProxyPool(proxies []string, strategy ProxyStrategy) Option {
+	return func(c *Client) {
+		pool := &proxyPool{strategy: strategy}
+		for _, proxy := range proxies {
+			if u, err := url.Parse(proxy); err == nil && u.Host != "" {
+				pool.proxies = append(pool.proxies, &proxyState{url: u})
+			}
+		}
+
+		if len(pool.proxies) == 0 {
+			return
+		}
+
+		c.proxies = pool
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			t.Proxy = proxyFromContext
+		})
+	}
+}
+
+// Proxies returns the status of the proxies of the pool set with WithProxyPool.
+func (c *Client) Proxies() []ProxyStatus {
+	if c.proxies == nil {
+		return nil
+	}
+
+	c.proxies.mu.Lock()
+	defer c.proxies.mu.Unlock()
+
+	statuses := make([]ProxyStatus, 0, len(c.proxies.proxies))
+	for _, p := range c.proxies.proxies {
+		statuses = append(statuses, ProxyStatus{
+			URL:          p.url.String(),
+			Successes:    p.successes,
+			Failures:     p.failures,
+			EjectedUntil: p.ejectedUntil,
+		})
+	}
+
+	return statuses
+}
+
+type proxyKey struct{}
+
+// proxyFromContext returns the proxy assigned to the request by the proxy pool.
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	if p, ok := requestValue[*proxyState](req, proxyKey{}); ok {
+		return p.url, nil
+	}
+
+	return nil, nil
+}
+
+type proxyState struct {
+	url          *url.URL
+	successes    int
+	failures     int
+	consecutive  int
+	ejectedUntil time.Time
+}
+
+type proxyPool struct {
+	mu       sync.Mutex
+	strategy ProxyStrategy
+	proxies  []*proxyState
+	next     int
+}
+
+// pick returns the proxy for the next request, or the proxy whose ejection ends first if all are ejected.
+func (p *proxyPool) pick(now time.Time, random *lockedRand) *proxyState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	available := make([]*proxyState, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		if !proxy.ejectedUntil.After(now) {
+			available = append(available, proxy)
+		}
+	}
+
+	if len(available) == 0 {
+		first := p.proxies[0]
+		for _, proxy := range p.proxies[1:] {
+			if proxy.ejectedUntil.Before(first.ejectedUntil) {
+				first = proxy
+			}
+		}
+		return first
+	}
+
+	switch p.strategy {
+	case ProxyRandom:
+		return available[random.Intn(len(available))]
+	case ProxyHealthiest:
+		best := available[0]
+		for _, proxy := range available[1:] {
+			if proxy.score() > best.score() {
+				best = proxy
+			}
+		}
+		return best
+	default:
+		proxy := available[p.next%len(available)]
+		p.next++
+		return proxy
+	}
+}
+
+// score is the success ratio of the proxy, with untried proxies considered healthy.
+func (s *proxyState) score() float64 {
+	return float64(s.successes+1) / float64(s.successes+s.failures+1)
+}
+
+// report records the outcome of a request through the proxy, reporting whether it got ejected.
+func (p *proxyPool) report(now time.Time, proxy *proxyState, resp *http.Response, err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil && !isProxyBan(resp) {
+		proxy.successes++
+		proxy.consecutive = 0
+		return false
+	}
+
+	proxy.failures++
+	proxy.consecutive++
+	if proxy.consecutive >= proxyFailureThreshold {
+		proxy.ejectedUntil = now.Add(proxyCooldown)
+		proxy.consecutive = 0
+		return true
+	}
+
+	return false
+}
+
+func isProxyBan(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusProxyAuthRequired, http.StatusTooManyRequests:
+		return true
+	}
+
+	return false
+}
+
+// useProxy assigns a proxy of the pool to the request attempt, returning it along with the request carrying it.
+func (c *Client) useProxy(req *http.Request) (*http.Request, *proxyState) {
+	if c.proxies == nil {
+		return req, nil
+	}
+
+	proxy := c.proxies.pick(c.clock().Now(), c.random)
+
+	return req.WithContext(context.WithValue(req.Context(), proxyKey{}, proxy)), proxy
+}
+
+// reportProxy records the outcome of the request attempt through the proxy, if any.
+// Cancelled requests are not held against the proxy.
+func (c *Client) reportProxy(req *http.Request, proxy *proxyState, resp *http.Response, err error) {
+	if proxy == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if c.proxies.report(c.clock().Now(), proxy, resp, err) {
+		c.emitState(StateEvent{Kind: ProxyEjected, Request: req, Target: proxy.url.String(), Err: err})
+	}
+}
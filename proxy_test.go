@@ -0,0 +1,57 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_ProxyPool(t *testing.T) {
+	banned := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer banned.Close()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "api.example.com" {
+			t.Errorf("expected the proxy to receive the target url, got %s", r.URL)
+		}
+		w.Header().Set("X-Proxy", "healthy")
+	}))
+	defer healthy.Close()
+
+	c := clink.NewClient(
+		clink.WithProxyPool([]string{banned.URL, healthy.URL, "::invalid"}, clink.ProxyRoundRobin),
+		clink.WithClient(&http.Client{}),
+	)
+
+	served := 0
+	for i := 0; i < 8; i++ {
+		resp, err := c.Get("http://api.example.com/items")
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.Header.Get("X-Proxy") == "healthy" {
+			served++
+		}
+	}
+
+	if served != 5 {
+		t.Errorf("expected the banned proxy to be ejected after 3 failures, got %d requests through the healthy one", served)
+	}
+
+	statuses := c.Proxies()
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 proxies, got %d", len(statuses))
+	}
+	if statuses[0].Failures != 3 || statuses[0].EjectedUntil.IsZero() {
+		t.Errorf("expected the banned proxy to be ejected, got %+v", statuses[0])
+	}
+	if statuses[1].Successes != 5 || !statuses[1].EjectedUntil.IsZero() {
+		t.Errorf("expected the healthy proxy to stay in rotation, got %+v", statuses[1])
+	}
+}
@@ -0,0 +1,142 @@
+package clink
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned for requests sent once the quota is used up, when the quota is enforced.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaPeriod is the billing window a quota applies to. Windows follow the UTC calendar.
+type QuotaPeriod int
+
+const (
+	// PerMinute windows start at the beginning of each minute.
+	PerMinute QuotaPeriod = iota
+	// PerHour windows start at the beginning of each hour.
+	PerHour
+	// PerDay windows start at midnight UTC.
+	PerDay
+	// PerMonth windows start at midnight UTC on the first day of each month.
+	PerMonth
+)
+
+// start returns the start of the window holding t.
+func (p QuotaPeriod) start(t time.Time) time.Time {
+	t = t.UTC()
+	switch p {
+	case PerMinute:
+		return t.Truncate(time.Minute)
+	case PerHour:
+		return t.Truncate(time.Hour)
+	case PerMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// next returns the start of the window following the one starting at start.
+func (p QuotaPeriod) next(start time.Time) time.Time {
+	switch p {
+	case PerMinute:
+		return start.Add(time.Minute)
+	case PerHour:
+		return start.Add(time.Hour)
+	case PerMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// QuotaStatus is the usage of the quota in the current window.
+type QuotaStatus struct {
+	Limit     int
+	Used      int
+	Remaining int
+	// Resets is when the current window ends.
+	Resets time.Time
+}
+
+// WithQuota counts every request sent, retries included, against an allowance of limit requests per period.
+// Responses served from the cache are not counted. The usage is reported by Quota.
+func WithQuota(limit int, period QuotaPeriod) Option {
+	return func(c *Client) {
+		c.quota = &quota{limit: limit, period: period}
+	}
+}
+
+// EnforceQuota makes requests fail with ErrQuotaExceeded once the quota set with WithQuota is used up.
+func EnforceQuota() Option {
+	return func(c *Client) {
+		c.quotaEnforced = true
+	}
+}
+
+// Quota returns the usage of the quota set with WithQuota, or false if the client has no quota.
+func (c *Client) Quota() (QuotaStatus, bool) {
+	if c.quota == nil {
+		return QuotaStatus{}, false
+	}
+
+	return c.quota.status(c.clock().Now()), true
+}
+
+type quota struct {
+	mu     sync.Mutex
+	limit  int
+	period QuotaPeriod
+	start  time.Time
+	used   int
+}
+
+// roll starts a new window if the current one is over. It must be called with the lock held.
+func (q *quota) roll(now time.Time) {
+	if start := q.period.start(now); !start.Equal(q.start) {
+		q.start, q.used = start, 0
+	}
+}
+
+// take counts a request, failing without counting it if enforce is set and the quota is used up.
+func (q *quota) take(now time.Time, enforce bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(now)
+	if enforce && q.used >= q.limit {
+		return ErrQuotaExceeded
+	}
+	q.used++
+
+	return nil
+}
+
+func (q *quota) status(now time.Time) QuotaStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(now)
+	remaining := q.limit - q.used
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return QuotaStatus{
+		Limit:     q.limit,
+		Used:      q.used,
+		Remaining: remaining,
+		Resets:    q.period.next(q.start),
+	}
+}
+
+// takeQuota counts a request against the quota of the client, if any.
+func (c *Client) takeQuota() error {
+	if c.quota == nil {
+		return nil
+	}
+
+	return c.quota.take(c.clock().Now(), c.quotaEnforced)
+}
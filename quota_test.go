@@ -0,0 +1,70 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Quota(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	testCases := []struct {
+		name      string
+		opts      []clink.Option
+		failures  int
+		remaining int
+		used      int
+	}{
+		{
+			name:      "tracks usage",
+			opts:      []clink.Option{clink.WithQuota(2, clink.PerDay)},
+			remaining: 0,
+			used:      3,
+		},
+		{
+			name:      "refuses requests when enforced",
+			opts:      []clink.Option{clink.WithQuota(2, clink.PerDay), clink.EnforceQuota()},
+			failures:  1,
+			remaining: 0,
+			used:      2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(append(tc.opts, clink.WithClient(server.Client()))...)
+
+			failures := 0
+			for i := 0; i < 3; i++ {
+				if _, err := c.Get(server.URL); errors.Is(err, clink.ErrQuotaExceeded) {
+					failures++
+				} else if err != nil {
+					t.Fatalf("request %d failed: %v", i, err)
+				}
+			}
+
+			if failures != tc.failures {
+				t.Errorf("expected %d refused requests, got %d", tc.failures, failures)
+			}
+
+			status, ok := c.Quota()
+			if !ok {
+				t.Fatal("expected the client to have a quota")
+			}
+			if status.Used != tc.used || status.Remaining != tc.remaining {
+				t.Errorf("expected %d used and %d remaining, got %+v", tc.used, tc.remaining, status)
+			}
+
+			now := time.Now().UTC()
+			if expected := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC); !status.Resets.Equal(expected) {
+				t.Errorf("expected the quota to reset at %v, got %v", expected, status.Resets)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package clink
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"math/rand"
+	"sync"
+)
+
+// WithRandom sets the source of randomness of the client, used for replay protection nonces, random proxy selection
+// and traffic splitting, so that tests and recordings are reproducible, such as WithRandom(rand.NewSource(1)).
+// Backoff jitter is drawn by the backoff function, see ExponentialBackoffWithSource.
+// By default, nonces are drawn from crypto/rand and other values from math/rand.
+func WithRandom(source rand.Source) Option {
+	return func(c *Client) {
+		c.random = newLockedRand(source)
+	}
+}
+
+// lockedRand is a *rand.Rand safe for concurrent use. A nil *lockedRand uses the default sources.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(source rand.Source) *lockedRand {
+	if source == nil {
+		return nil
+	}
+
+	return &lockedRand{r: rand.New(source)}
+}
+
+func (l *lockedRand) Int63n(n int64) int64 {
+	if l == nil {
+		return rand.Int63n(n)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.r.Int63n(n)
+}
+
+func (l *lockedRand) Intn(n int) int {
+	if l == nil {
+		return rand.Intn(n)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.r.Intn(n)
+}
+
+func (l *lockedRand) Float64() float64 {
+	if l == nil {
+		return rand.Float64()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.r.Float64()
+}
+
+// Read fills b with random bytes, from crypto/rand by default.
+func (l *lockedRand) Read(b []byte) {
+	if l == nil {
+		_, _ = crand.Read(b)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = l.r.Read(b)
+}
+
+// nonce returns 16 random bytes encoded in hexadecimal.
+func (l *lockedRand) nonce() string {
+	b := make([]byte, 16)
+	l.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,67 @@
+package clink_test
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_RandomNonces(t *testing.T) {
+	var nonces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, r.Header.Get("X-Nonce"))
+	}))
+	defer server.Close()
+
+	run := func(seed int64) []string {
+		nonces = nil
+		c := clink.NewClient(
+			clink.WithRandom(rand.NewSource(seed)),
+			clink.WithReplayProtection(clink.ReplayProtection{}),
+			clink.WithClient(server.Client()),
+		)
+		for i := 0; i < 3; i++ {
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+		}
+		return nonces
+	}
+
+	first, second, other := run(1), run(1), run(2)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected the same nonces for the same seed, got %v and %v", first, second)
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("expected different nonces for different seeds")
+	}
+	if first[0] == first[1] || len(first[0]) != 32 {
+		t.Errorf("expected distinct 16 byte nonces, got %v", first)
+	}
+}
+
+func TestExponentialBackoffWithSource(t *testing.T) {
+	delays := func(seed int64) []time.Duration {
+		backoff := clink.ExponentialBackoffWithSource(100*time.Millisecond, 10*time.Second, rand.NewSource(seed))
+		var d []time.Duration
+		for attempt := 0; attempt < 5; attempt++ {
+			delay := backoff(attempt, nil)
+			if delay < 0 || delay > 100*time.Millisecond<<attempt {
+				t.Errorf("expected attempt %d to be within its jitter range, got %v", attempt, delay)
+			}
+			d = append(d, delay)
+		}
+		return d
+	}
+
+	if first, second := delays(1), delays(1); !reflect.DeepEqual(first, second) {
+		t.Errorf("expected the same delays for the same seed, got %v and %v", first, second)
+	}
+}
@@ -0,0 +1,94 @@
+package clink
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ReplayProtection configures the timestamp and nonce headers attached to every request attempt.
+type ReplayProtection struct {
+	// TimestampHeader is the name of the timestamp header. Defaults to X-Timestamp.
+	TimestampHeader string
+	// NonceHeader is the name of the nonce header. Defaults to X-Nonce.
+	NonceHeader string
+	// Timestamp formats the timestamp. Defaults to Unix milliseconds.
+	Timestamp func(time.Time) string
+	// Nonce returns a new nonce. Defaults to 16 random bytes encoded in hexadecimal, drawn from the source set with WithRandom.
+	Nonce func() string
+}
+
+// WithReplayProtection attaches a timestamp and a nonce header to every request attempt, retries included.
+// Timestamps are strictly increasing by at least a millisecond, as required by APIs rejecting reused timestamps.
+func WithReplayProtection(protection ReplayProtection) Option {
+	return func(c *Client) {
+		if protection.TimestampHeader == "" {
+			protection.TimestampHeader = "X-Timestamp"
+		}
+		if protection.NonceHeader == "" {
+			protection.NonceHeader = "X-Nonce"
+		}
+		if protection.Timestamp == nil {
+			protection.Timestamp = func(t time.Time) string {
+				return strconv.FormatInt(t.UnixMilli(), 10)
+			}
+		}
+		c.replay = &replayStamper{ReplayProtection: protection}
+	}
+}
+
+type replayStamper struct {
+	ReplayProtection
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// next returns a timestamp later than the previous one by at least a millisecond.
+func (s *replayStamper) next(now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last = s.after(now)
+
+	return s.last
+}
+
+// peek returns the timestamp next would return, without recording it.
+func (s *replayStamper) peek(now time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.after(now)
+}
+
+// after returns now, truncated to the millisecond and moved past the previous timestamp. The caller must hold s.mu.
+func (s *replayStamper) after(now time.Time) time.Time {
+	now = now.Truncate(time.Millisecond)
+	if !now.After(s.last) {
+		now = s.last.Add(time.Millisecond)
+	}
+
+	return now
+}
+
+// stampRequest sets the replay protection headers of the request attempt.
+func (c *Client) stampRequest(req *http.Request) {
+	if c.replay == nil {
+		return
+	}
+
+	// Dry runs neither record their timestamp nor draw from the source set with WithRandom.
+	stamp, random := c.replay.next, c.random
+	if isDryRun(req) {
+		stamp, random = c.replay.peek, nil
+	}
+
+	req.Header.Set(c.replay.TimestampHeader, c.replay.Timestamp(stamp(c.now())))
+	nonce := c.replay.Nonce
+	if nonce == nil {
+		nonce = random.nonce
+	}
+	req.Header.Set(c.replay.NonceHeader, nonce())
+}
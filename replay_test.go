@@ -0,0 +1,51 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_ReplayProtection(t *testing.T) {
+	var timestamps []int64
+	nonces := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts, _ := strconv.ParseInt(r.Header.Get("X-Api-Timestamp"), 10, 64)
+		timestamps = append(timestamps, ts)
+		nonces[r.Header.Get("X-Nonce")] = true
+		if len(timestamps) == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(
+		clink.WithReplayProtection(clink.ReplayProtection{TimestampHeader: "X-Api-Timestamp"}),
+		clink.WithRetryPolicy(clink.RetryPolicy{
+			MaxRetries:  1,
+			ShouldRetry: clink.RetryOnStatus(http.StatusServiceUnavailable),
+			Backoff:     func(attempt int, resp *http.Response) time.Duration { return 0 },
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(server.URL); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	if len(timestamps) != 4 || len(nonces) != 4 {
+		t.Fatalf("expected 4 attempts with distinct nonces, got %d timestamps and %d nonces", len(timestamps), len(nonces))
+	}
+
+	for i := 1; i < len(timestamps); i++ {
+		if timestamps[i] <= timestamps[i-1] {
+			t.Errorf("expected strictly increasing timestamps, got %v", timestamps)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RequestOption configures a single request sent by the client.
+type RequestOption func(*requestConfig)
+
+// requestConfig holds the per request configuration carried by the request context.
+type requestConfig struct {
+	expectedStatus []int
+	body           func(*Client, *http.Request) error
+	host           string
+	serverName     string
+	cacheMode      cacheMode
+	headers        map[string]string
+	trailers       []func(*Client, *http.Request) error
+	tags           map[string]string
+	priority       Priority
+}
+
+type requestConfigKey struct{}
+
+// emptyRequestConfig is returned for requests without options. It must not be modified.
+var emptyRequestConfig = &requestConfig{}
+
+// WithRequestOptions returns a shallow copy of the request carrying the given options.
+func WithRequestOptions(req *http.Request, opts ...RequestOption) *http.Request {
+	if len(opts) == 0 {
+		return req
+	}
+
+	cfg := &requestConfig{}
+	if existing, ok := req.Context().Value(requestConfigKey{}).(*requestConfig); ok {
+		*cfg = *existing
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), requestConfigKey{}, cfg))
+}
+
+// requestConfigFrom returns the configuration carried by the request, or an empty one that must not be modified.
+func requestConfigFrom(req *http.Request) *requestConfig {
+	if cfg, ok := req.Context().Value(requestConfigKey{}).(*requestConfig); ok {
+		return cfg
+	}
+
+	return emptyRequestConfig
+}
+
+// setBody sets the request body to data, setting the Content-Type header unless the request already has one.
+func setBody(req *http.Request, data []byte, contentType string) {
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.ContentLength = int64(len(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	if contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+}
+
+// WithBaseURL resolves the requests sent with a relative URL, such as "/users?page=2", against the base URL,
+// appending their path to the path of the base URL. Invalid base URLs are ignored.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+			c.baseURL = u
+		}
+	}
+}
+
+// resolveURL points requests without a host at the base URL of the client.
+func (c *Client) resolveURL(req *http.Request) {
+	if c.baseURL == nil || req.URL == nil || req.URL.Host != "" {
+		return
+	}
+
+	u := *c.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(req.URL.Path, "/")
+	u.RawPath = ""
+	u.RawQuery = req.URL.RawQuery
+	u.Fragment = ""
+	req.URL = &u
+}
+
+// newRequest creates a request for the http method helpers.
+func newRequest(method, url string, body io.Reader, opts []RequestOption) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return WithRequestOptions(req, opts...), nil
+}
+
+// requestValue returns the value stored under key in the context of the request, if any.
+func requestValue[T any](req *http.Request, key any) (T, bool) {
+	var zero T
+	if req == nil {
+		return zero, false
+	}
+
+	value, ok := req.Context().Value(key).(T)
+	if !ok {
+		return zero, false
+	}
+
+	return value, true
+}
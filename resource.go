@@ -0,0 +1,106 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Resource exposes the conventional REST operations of a collection of T, such as "/users" and "/users/{id}".
+// Request and response bodies are JSON encoded with the client's JSON codec, and responses with a status
+// code of 300 or more return a *StatusError.
+type Resource[T any] struct {
+	client *Client
+	path   string
+}
+
+// NewResource returns the resource of the collection at path, resolved against the base URL of the client when relative.
+func NewResource[T any](c *Client, path string) *Resource[T] {
+	return &Resource[T]{client: c, path: strings.TrimSuffix(path, "/")}
+}
+
+// List returns the items of the collection. The request options can set query parameters or headers.
+func (r *Resource[T]) List(ctx context.Context, opts ...RequestOption) ([]T, error) {
+	var items []T
+	err := r.do(ctx, http.MethodGet, r.path, nil, &items, opts)
+
+	return items, err
+}
+
+// Get returns the item with the given id.
+func (r *Resource[T]) Get(ctx context.Context, id string, opts ...RequestOption) (T, error) {
+	var item T
+	err := r.do(ctx, http.MethodGet, r.itemPath(id), nil, &item, opts)
+
+	return item, err
+}
+
+// Create adds v to the collection and returns the created item, or v when the response has no content.
+func (r *Resource[T]) Create(ctx context.Context, v T, opts ...RequestOption) (T, error) {
+	item := v
+	err := r.do(ctx, http.MethodPost, r.path, v, &item, opts)
+
+	return item, err
+}
+
+// Update replaces the item with the given id by v and returns the updated item, or v when the response has no content.
+func (r *Resource[T]) Update(ctx context.Context, id string, v T, opts ...RequestOption) (T, error) {
+	item := v
+	err := r.do(ctx, http.MethodPut, r.itemPath(id), v, &item, opts)
+
+	return item, err
+}
+
+// Delete removes the item with the given id.
+func (r *Resource[T]) Delete(ctx context.Context, id string, opts ...RequestOption) error {
+	return r.do(ctx, http.MethodDelete, r.itemPath(id), nil, nil, opts)
+}
+
+func (r *Resource[T]) itemPath(id string) string {
+	return r.path + "/" + url.PathEscape(id)
+}
+
+// do sends the request and decodes the response into target, leaving it untouched when the response has no content.
+func (r *Resource[T]) do(ctx context.Context, method, path string, body any, target any, opts []RequestOption) error {
+	if body != nil {
+		opts = append([]RequestOption{JSONBody(body)}, opts...)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return err
+	}
+	req = WithRequestOptions(req, opts...)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return newStatusError(req, resp)
+	}
+
+	if target == nil || resp.StatusCode == http.StatusNoContent {
+		discardBody(resp)
+		return nil
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	cfg := responseDecodeConfig(resp, nil)
+	if err := cfg.checkContentType("application/json"); err != nil {
+		return err
+	}
+
+	if err := cfg.decode(resp.Body, target); err != nil && !errors.Is(err, io.EOF) {
+		return &DecodeError{Content: "response", Err: err}
+	}
+
+	return nil
+}
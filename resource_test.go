@@ -0,0 +1,103 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type resourceUser struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func newUsersServer() *httptest.Server {
+	var mu sync.Mutex
+	users := map[string]resourceUser{"1": {ID: "1", Name: "alice"}}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/users"), "/")
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && id == "":
+			list := make([]resourceUser, 0, len(users))
+			for _, key := range []string{"1", "2"} {
+				if user, ok := users[key]; ok {
+					list = append(list, user)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(list)
+		case r.Method == http.MethodPost && id == "":
+			var user resourceUser
+			_ = json.NewDecoder(r.Body).Decode(&user)
+			user.ID = "2"
+			users[user.ID] = user
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(user)
+		case r.Method == http.MethodPut:
+			var user resourceUser
+			_ = json.NewDecoder(r.Body).Decode(&user)
+			users[id] = user
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete:
+			delete(users, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			user, ok := users[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(user)
+		}
+	}))
+}
+
+func TestResource(t *testing.T) {
+	server := newUsersServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	users := clink.NewResource[resourceUser](clink.NewClient(clink.WithBaseURL(server.URL), clink.WithClient(server.Client())), "/users")
+
+	created, err := users.Create(ctx, resourceUser{Name: "bob"})
+	if err != nil || created != (resourceUser{ID: "2", Name: "bob"}) {
+		t.Fatalf("expected the created user, got %v %v", created, err)
+	}
+
+	updated, err := users.Update(ctx, "2", resourceUser{ID: "2", Name: "robert"})
+	if err != nil || updated.Name != "robert" {
+		t.Fatalf("expected the updated user, got %v %v", updated, err)
+	}
+
+	user, err := users.Get(ctx, "2")
+	if err != nil || user.Name != "robert" {
+		t.Fatalf("expected the user, got %v %v", user, err)
+	}
+
+	if err := users.Delete(ctx, "1"); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	list, err := users.List(ctx)
+	if err != nil || !reflect.DeepEqual(list, []resourceUser{{ID: "2", Name: "robert"}}) {
+		t.Errorf("expected the remaining users, got %v %v", list, err)
+	}
+
+	var statusErr *clink.StatusError
+	if _, err := users.Get(ctx, "1"); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a status error for a deleted user, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StatusResumeIncomplete is the status of resumable upload responses for uploads that are not complete.
+const StatusResumeIncomplete = http.StatusPermanentRedirect
+
+// ResumableUpload configures an upload to a resumable upload session, as used by Google Cloud Storage and YouTube.
+type ResumableUpload struct {
+	// ChunkSize is the size of each uploaded chunk. It should be a multiple of 256 KiB and defaults to 8 MiB.
+	ChunkSize int64
+	// MaxFailures is the number of consecutive failed chunks after which the upload is abandoned. Defaults to 5.
+	MaxFailures int
+	// ContentType is sent with every chunk when set.
+	ContentType string
+	// OnProgress is called with the number of bytes committed by the server after each chunk.
+	OnProgress func(committed, total int64)
+}
+
+// StartResumableUpload sends the request initiating a resumable upload session and returns the session URL
+// from the Location header of the response.
+func (c *Client) StartResumableUpload(req *http.Request) (string, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer discardBody(resp)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return "", newStatusError(req, resp)
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("resumable upload response has no Location header")
+	}
+
+	return location, nil
+}
+
+// UploadResumable uploads the size bytes of r to the resumable upload session in chunks and returns the final response.
+// After a failed chunk, the upload status is queried and the upload resumes from the offset committed by the server.
+func (c *Client) UploadResumable(ctx context.Context, sessionURL string, r io.ReaderAt, size int64, upload ResumableUpload) (*http.Response, error) {
+	chunkSize := upload.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 8 << 20
+	}
+
+	maxFailures := upload.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = 5
+	}
+
+	var offset int64
+	failures := 0
+	for {
+		end := offset + chunkSize
+		if end > size {
+			end = size
+		}
+
+		resp, err := c.uploadChunk(ctx, sessionURL, io.NewSectionReader(r, offset, end-offset), offset, end, size, upload.ContentType)
+		if err == nil && resp.StatusCode != StatusResumeIncomplete {
+			if upload.OnProgress != nil {
+				upload.OnProgress(size, size)
+			}
+			return resp, nil
+		}
+
+		if err == nil {
+			offset, err = committedOffset(resp)
+			discardBody(resp)
+			if err != nil {
+				return nil, err
+			}
+			failures = 0
+		} else {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			failures++
+			if failures >= maxFailures {
+				return nil, fmt.Errorf("failed to upload chunk at offset %d: %w", offset, err)
+			}
+
+			delay, err := c.backoff(failures-1, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			select {
+			case <-clockAfter(ctx, c.clock(), delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			if offset, err = c.uploadStatus(ctx, sessionURL, size); err != nil {
+				return nil, err
+			}
+		}
+
+		if upload.OnProgress != nil {
+			upload.OnProgress(offset, size)
+		}
+	}
+}
+
+// uploadChunk sends the bytes from start to end of the upload. Status codes other than success
+// and StatusResumeIncomplete are returned as a *StatusError.
+func (c *Client) uploadChunk(ctx context.Context, sessionURL string, chunk io.Reader, start, end, size int64, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = end - start
+
+	if end > start {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, size))
+	} else {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return c.Do(WithRequestOptions(req, ExpectStatus(http.StatusOK, http.StatusCreated, StatusResumeIncomplete)))
+}
+
+// uploadStatus queries the number of bytes committed by the server for the upload.
+func (c *Client) uploadStatus(ctx context.Context, sessionURL string, size int64) (int64, error) {
+	resp, err := c.uploadChunk(ctx, sessionURL, http.NoBody, 0, 0, size, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query upload status: %w", err)
+	}
+	defer discardBody(resp)
+
+	if resp.StatusCode != StatusResumeIncomplete {
+		return size, nil
+	}
+
+	return committedOffset(resp)
+}
+
+// committedOffset returns the offset following the bytes committed according to the Range header of the response.
+func committedOffset(resp *http.Response) (int64, error) {
+	rangeHeader := resp.Header.Get("Range")
+	if rangeHeader == "" {
+		return 0, nil
+	}
+
+	_, end, ok := strings.Cut(strings.TrimPrefix(rangeHeader, "bytes="), "-")
+	last, err := strconv.ParseInt(end, 10, 64)
+	if !ok || err != nil {
+		return 0, errors.New("invalid Range header " + strconv.Quote(rangeHeader))
+	}
+
+	return last + 1, nil
+}
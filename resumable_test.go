@@ -0,0 +1,97 @@
+package clink_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_UploadResumable(t *testing.T) {
+	var mu sync.Mutex
+	var stored []byte
+	var chunks int
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", server.URL+"/session")
+			return
+		}
+
+		var start, end, total int64
+		if _, err := fmt.Sscanf(r.Header.Get("Content-Range"), "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			// Status query.
+			if len(stored) > 0 {
+				w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(stored)-1))
+			}
+			w.WriteHeader(clink.StatusResumeIncomplete)
+			return
+		}
+
+		chunks++
+		if chunks == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		stored = append(stored[:start], body...)
+		if int64(len(stored)) == total {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(stored)-1))
+		w.WriteHeader(clink.StatusResumeIncomplete)
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(
+		clink.WithBackoff(func(attempt int, resp *http.Response) time.Duration { return time.Millisecond }),
+		clink.WithClient(server.Client()),
+	)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/upload?uploadType=resumable", nil)
+	session, err := c.StartResumableUpload(req)
+	if err != nil {
+		t.Fatalf("failed to start upload: %v", err)
+	}
+
+	data := "the quick brown fox jumps over the lazy dog"
+	var progress []int64
+	resp, err := c.UploadResumable(context.Background(), session, strings.NewReader(data), int64(len(data)), clink.ResumableUpload{
+		ChunkSize: 8,
+		OnProgress: func(committed, total int64) {
+			progress = append(progress, committed)
+		},
+	})
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected the final response to be 201, got %d", resp.StatusCode)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if string(stored) != data {
+		t.Errorf("expected %q to be stored, got %q", data, stored)
+	}
+
+	if progress[len(progress)-1] != int64(len(data)) {
+		t.Errorf("expected the progress to end at %d, got %v", len(data), progress)
+	}
+}
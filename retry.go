@@ -0,0 +1,238 @@
+package clink
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ErrorClass classifies errors returned by the http client.
+type ErrorClass int
+
+const (
+	// ErrorClassUnknown is an error that could not be classified.
+	ErrorClassUnknown ErrorClass = iota
+	// ErrorClassRetryable is a transient error, such as a reset connection or a timeout.
+	ErrorClassRetryable
+	// ErrorClassPermanent is an error that will not go away by retrying, such as a TLS verification or DNS NXDOMAIN error.
+	ErrorClassPermanent
+)
+
+// String returns the name of the error class.
+func (ec ErrorClass) String() string {
+	switch ec {
+	case ErrorClassRetryable:
+		return "retryable"
+	case ErrorClassPermanent:
+		return "permanent"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassifyError classifies a network error returned by the http client.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassUnknown
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrServerNameUnsupported) {
+		return ErrorClassPermanent
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidCertErr) || errors.As(err, &recordHeaderErr) {
+		return ErrorClassPermanent
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return ErrorClassPermanent
+		}
+		return ErrorClassRetryable
+	}
+
+	if errors.Is(err, ErrUpstreamUnavailable) {
+		return ErrorClassRetryable
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassRetryable
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassRetryable
+	}
+
+	return ErrorClassUnknown
+}
+
+// IsRetryableError reports whether the error is a transient network error worth retrying.
+func IsRetryableError(err error) bool {
+	return ClassifyError(err) == ErrorClassRetryable
+}
+
+// RetryOnNetworkErrors is a ShouldRetryFunc that retries requests failing with a retryable network error.
+// It is used when retries are enabled without a ShouldRetryFunc.
+func RetryOnNetworkErrors(_ *http.Request, _ *http.Response, err error) bool {
+	return IsRetryableError(err)
+}
+
+// RetryPolicy bundles the retry count, retry function and backoff used by the client.
+type RetryPolicy struct {
+	MaxRetries  int
+	ShouldRetry func(*http.Request, *http.Response, error) bool
+	Backoff     func(attempt int, resp *http.Response) time.Duration
+}
+
+var (
+	// RetryPolicyStandard retries network errors, 429 and 5xx responses up to 3 times
+	// with an exponential jittered backoff starting at 250ms and capped at 10s.
+	RetryPolicyStandard = RetryPolicy{
+		MaxRetries:  3,
+		ShouldRetry: RetryOnStatus(http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout),
+		Backoff:     ExponentialBackoff(250*time.Millisecond, 10*time.Second),
+	}
+
+	// RetryPolicyAggressive retries network errors, 408, 425, 429 and 5xx responses up to 10 times
+	// with an exponential jittered backoff starting at 100ms and capped at 30s.
+	RetryPolicyAggressive = RetryPolicy{
+		MaxRetries:  10,
+		ShouldRetry: RetryOnStatus(http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout),
+		Backoff:     ExponentialBackoff(100*time.Millisecond, 30*time.Second),
+	}
+)
+
+// WithRetryPolicy sets the retry count, retry function and backoff of the client from the policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.MaxRetries = policy.MaxRetries
+		c.ShouldRetryFunc = policy.ShouldRetry
+		c.BackoffFunc = policy.Backoff
+	}
+}
+
+// WithBackoff sets the function computing the delay before each retry.
+func WithBackoff(backoff func(attempt int, resp *http.Response) time.Duration) Option {
+	return func(c *Client) {
+		c.BackoffFunc = backoff
+	}
+}
+
+// RetryOnStatus returns a ShouldRetryFunc that retries retryable network errors and responses with one of the given status codes.
+func RetryOnStatus(codes ...int) func(*http.Request, *http.Response, error) bool {
+	return func(_ *http.Request, resp *http.Response, err error) bool {
+		if err != nil {
+			return IsRetryableError(err)
+		}
+
+		for _, code := range codes {
+			if resp != nil && resp.StatusCode == code {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// ExponentialBackoff returns a backoff doubling the delay on every attempt, with full jitter, starting at base and capped at maxDelay.
+// A Retry-After header on the response takes precedence, up to maxDelay.
+func ExponentialBackoff(base, maxDelay time.Duration) func(attempt int, resp *http.Response) time.Duration {
+	return ExponentialBackoffWithSource(base, maxDelay, nil)
+}
+
+// ExponentialBackoffWithSource returns an ExponentialBackoff drawing its jitter from source, so that retry delays
+// are reproducible. A nil source uses math/rand. Negative durations are treated as zero.
+func ExponentialBackoffWithSource(base, maxDelay time.Duration, source rand.Source) func(attempt int, resp *http.Response) time.Duration {
+	random := newLockedRand(source)
+	base, maxDelay = max(base, 0), max(maxDelay, 0)
+
+	return func(attempt int, resp *http.Response) time.Duration {
+		if delay, ok := RetryAfter(resp); ok {
+			if delay > maxDelay {
+				return maxDelay
+			}
+			return delay
+		}
+
+		// base<<attempt < maxDelay is checked as base <= (maxDelay-1)>>attempt, which cannot overflow.
+		delay := maxDelay
+		if attempt = max(attempt, 0); maxDelay > 0 && attempt < 63 && base <= (maxDelay-1)>>attempt {
+			delay = base << attempt
+		}
+
+		if delay == math.MaxInt64 {
+			return time.Duration(random.Int63n(int64(delay)))
+		}
+		return time.Duration(random.Int63n(int64(delay) + 1))
+	}
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of the response, in seconds or as an HTTP date.
+// Dates are compared to the clock of the client that sent the request, see WithClock.
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	return RetryAfterAt(resp, requestClock(resp.Request).Now())
+}
+
+// RetryAfterAt is like RetryAfter, computing the delay requested as an HTTP date from now.
+func RetryAfterAt(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// discardBody drains and closes the body of a response that will not be returned to the caller.
+func discardBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+
+	_, _ = buf.ReadFrom(io.LimitReader(resp.Body, 1<<16))
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,152 @@
+package clink_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name  string
+		err   error
+		class clink.ErrorClass
+	}{
+		{name: "nil error", err: nil, class: clink.ErrorClassUnknown},
+		{name: "connection reset", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, class: clink.ErrorClassRetryable},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, class: clink.ErrorClassRetryable},
+		{name: "eof on idle connection", err: &url.Error{Op: "Get", Err: io.EOF}, class: clink.ErrorClassRetryable},
+		{name: "dns timeout", err: &net.DNSError{IsTimeout: true}, class: clink.ErrorClassRetryable},
+		{name: "dns nxdomain", err: &net.DNSError{IsNotFound: true}, class: clink.ErrorClassPermanent},
+		{name: "unknown authority", err: &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, class: clink.ErrorClassPermanent},
+		{name: "context canceled", err: fmt.Errorf("wrapped: %w", context.Canceled), class: clink.ErrorClassPermanent},
+		{name: "unknown error", err: errors.New("something else"), class: clink.ErrorClassUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if class := clink.ClassifyError(tc.err); class != tc.class {
+				t.Errorf("expected %s, got %s", tc.class, class)
+			}
+		})
+	}
+}
+
+func TestDefaultShouldRetry(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			// Hijack and close the connection to simulate a reset.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithRetries(3, nil),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || requestCount != 2 {
+		t.Errorf("expected one retry after the network error, got %d requests", requestCount)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		switch requestCount {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithRetryPolicy(clink.RetryPolicyStandard),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || requestCount != 3 {
+		t.Errorf("expected success after 3 requests, got status %d after %d requests", resp.StatusCode, requestCount)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := clink.ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := backoff(attempt, nil)
+		limit := 100 * time.Millisecond << attempt
+		if limit > time.Second {
+			limit = time.Second
+		}
+
+		if delay < 0 || delay > limit {
+			t.Errorf("expected delay of attempt %d to be within [0, %s], got %s", attempt, limit, delay)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+	if delay := backoff(0, resp); delay != time.Second {
+		t.Errorf("expected Retry-After to be capped at 1s, got %s", delay)
+	}
+}
+
+func TestExponentialBackoff_Bounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		maxDelay time.Duration
+		attempt  int
+		limit    time.Duration
+	}{
+		{name: "shift overflowing", base: 5 * time.Second, maxDelay: time.Hour, attempt: 31, limit: time.Hour},
+		{name: "large attempt", base: time.Second, maxDelay: time.Minute, attempt: 200, limit: time.Minute},
+		{name: "negative max delay", base: time.Second, maxDelay: -time.Second, attempt: 1, limit: 0},
+		{name: "negative base", base: -time.Second, maxDelay: time.Second, attempt: 3, limit: 0},
+		{name: "negative attempt", base: time.Second, maxDelay: time.Minute, attempt: -1, limit: time.Second},
+		{name: "maximum duration", base: time.Second, maxDelay: math.MaxInt64, attempt: 70, limit: math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay := clink.ExponentialBackoff(tt.base, tt.maxDelay)(tt.attempt, nil)
+			if delay < 0 || delay > tt.limit {
+				t.Errorf("expected delay within [0, %s], got %s", tt.limit, delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package clink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDisallowedByRobots is matched by the *RobotsError returned for requests to paths disallowed by robots.txt.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// RobotsError is returned in crawler mode for requests to paths disallowed by the robots.txt of their host.
+type RobotsError struct {
+	URL string
+}
+
+// Error implements the error interface.
+func (e *RobotsError) Error() string {
+	return "robots.txt disallows " + e.URL
+}
+
+// Is makes errors.Is match ErrDisallowedByRobots.
+func (e *RobotsError) Is(target error) bool {
+	return target == ErrDisallowedByRobots
+}
+
+// robotsTTL is how long a robots.txt is cached.
+const robotsTTL = 24 * time.Hour
+
+// WithRobots enables crawler mode: the robots.txt of every host is fetched and cached, requests to paths it disallows
+// for the crawler fail with a *RobotsError, and requests to the host are spaced by its Crawl-delay.
+// The rules of the group matching crawler, the product token of the crawler's user agent, apply, or those of "*".
+func WithRobots(crawler string) Option {
+	return func(c *Client) {
+		c.robots = &robotsCache{crawler: strings.ToLower(crawler), hosts: make(map[string]*robotsHost)}
+	}
+}
+
+type robotsCache struct {
+	crawler string
+
+	mu    sync.Mutex
+	hosts map[string]*robotsHost
+}
+
+type robotsHost struct {
+	mu        sync.Mutex
+	rules     *robotsRules
+	fetched   time.Time
+	nextCrawl time.Time
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	// disallowAll is set when robots.txt could not be fetched because of a server error.
+	disallowAll bool
+}
+
+// checkRobots fails if robots.txt disallows the request, and waits for the crawl delay of the host otherwise.
+func (c *Client) checkRobots(req *http.Request) error {
+	if c.robots == nil {
+		return nil
+	}
+
+	c.robots.mu.Lock()
+	host, ok := c.robots.hosts[req.URL.Host]
+	if !ok {
+		host = &robotsHost{}
+		c.robots.hosts[req.URL.Host] = host
+	}
+	c.robots.mu.Unlock()
+
+	host.mu.Lock()
+	if host.rules == nil || c.clock().Now().Sub(host.fetched) > robotsTTL {
+		rules, cache := c.fetchRobots(req.Context(), req)
+		host.rules = rules
+		if cache {
+			host.fetched = c.clock().Now()
+		}
+	}
+
+	if !host.rules.allowed(requestPath(req)) {
+		host.mu.Unlock()
+		return &RobotsError{URL: req.URL.String()}
+	}
+
+	now := c.clock().Now()
+	wait := host.nextCrawl.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	host.nextCrawl = now.Add(wait + host.rules.crawlDelay)
+	host.mu.Unlock()
+
+	return sleepContext(req.Context(), c.clock(), wait)
+}
+
+// fetchRobots fetches and parses the robots.txt of the host of the request, reporting whether the result may be cached.
+// Missing robots.txt allow everything, while server errors disallow everything until it can be fetched.
+func (c *Client) fetchRobots(ctx context.Context, req *http.Request) (*robotsRules, bool) {
+	robotsURL := req.URL.Scheme + "://" + req.URL.Host + "/robots.txt"
+	robotsReq, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return &robotsRules{}, true
+	}
+
+	if ua := req.Header.Get("User-Agent"); ua != "" {
+		robotsReq.Header.Set("User-Agent", ua)
+	}
+
+	resp, err := c.HttpClient.Do(robotsReq)
+	if err != nil {
+		return &robotsRules{disallowAll: true}, false
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return &robotsRules{disallowAll: true}, false
+	case resp.StatusCode >= http.StatusBadRequest:
+		return &robotsRules{}, true
+	}
+
+	return parseRobots(io.LimitReader(resp.Body, 500<<10), c.robots.crawler), true
+}
+
+// parseRobots parses the rules of the group matching the crawler, or of the "*" group if none matches.
+func parseRobots(r io.Reader, crawler string) *robotsRules {
+	var matching, wildcard *robotsRules
+	var current []*robotsRules
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+				inAgents = true
+			}
+
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &robotsRules{}
+				}
+				current = append(current, wildcard)
+			case crawler != "" && strings.Contains(crawler, agent):
+				if matching == nil {
+					matching = &robotsRules{}
+				}
+				current = append(current, matching)
+			}
+			continue
+		}
+		inAgents = false
+
+		for _, rules := range current {
+			switch key {
+			case "allow", "disallow":
+				if value != "" {
+					rules.rules = append(rules.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					rules.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	switch {
+	case matching != nil:
+		return matching
+	case wildcard != nil:
+		return wildcard
+	default:
+		return &robotsRules{}
+	}
+}
+
+// allowed reports whether the path is allowed. The longest matching rule applies, and allow rules win ties.
+func (r *robotsRules) allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+
+	allowed, length := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+
+		if len(rule.pattern) > length || (len(rule.pattern) == length && rule.allow) {
+			allowed, length = rule.allow, len(rule.pattern)
+		}
+	}
+
+	return allowed
+}
+
+// robotsMatch reports whether the path matches the pattern, where * matches any sequence and a trailing $ anchors the end.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+
+	return strings.Contains(rest, last)
+}
+
+func requestPath(req *http.Request) string {
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+
+	return path
+}
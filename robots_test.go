@@ -0,0 +1,75 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Robots(t *testing.T) {
+	var robotsFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsFetches.Add(1)
+			_, _ = w.Write([]byte(`
+User-agent: *
+Disallow: /
+
+User-agent: clinkbot
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 0.05
+`))
+			return
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithRobots("clinkbot"), clink.WithClient(server.Client()))
+
+	testCases := []struct {
+		path    string
+		allowed bool
+	}{
+		{path: "/", allowed: true},
+		{path: "/private/data", allowed: false},
+		{path: "/private/public/data", allowed: true},
+		{path: "/docs/report.pdf", allowed: false},
+		{path: "/docs/report.pdf?download=1", allowed: true},
+	}
+
+	start := time.Now()
+	allowed := 0
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			resp, err := c.Get(server.URL + tc.path)
+			if tc.allowed {
+				allowed++
+				if err != nil {
+					t.Fatalf("expected the request to be allowed, got %v", err)
+				}
+				_ = resp.Body.Close()
+				return
+			}
+
+			var robotsErr *clink.RobotsError
+			if !errors.Is(err, clink.ErrDisallowedByRobots) || !errors.As(err, &robotsErr) {
+				t.Errorf("expected ErrDisallowedByRobots, got %v", err)
+			}
+		})
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Duration(allowed-1)*50*time.Millisecond {
+		t.Errorf("expected the crawl delay to space %d requests, took %v", allowed, elapsed)
+	}
+
+	if robotsFetches.Load() != 1 {
+		t.Errorf("expected robots.txt to be fetched once, got %d", robotsFetches.Load())
+	}
+}
@@ -0,0 +1,75 @@
+package clink
+
+import "net/http"
+
+// Transport returns an http.RoundTripper sending requests through the client, with its headers, rate limiting,
+// retries, middleware and hooks, so that libraries accepting an *http.Client benefit from them:
+//
+//	sdk.NewService(&http.Client{Transport: client.Transport()})
+//
+// Requests are cloned before being sent, as round trippers must not modify them. Redirects are followed by the
+// client, and errors, such as a *StatusError with WithStatusErrors, are returned by the round trip.
+// The client must not use the returned transport itself, which would loop.
+func (c *Client) Transport() http.RoundTripper {
+	return clientTransport{client: c}
+}
+
+// clientTransport adapts a client to http.RoundTripper.
+type clientTransport struct {
+	client *Client
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t clientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.client.Do(req.Clone(req.Context()))
+	if err != nil && req.Body != nil {
+		// Round trippers must close the request body, even when it was not sent.
+		_ = req.Body.Close()
+	}
+
+	return resp, err
+}
+
+// WithTransportWrapper wraps the transport of the http client with external round trippers, such as the ones of
+// tracing or metrics libraries, so that they see every request on the wire, below the middleware, retries and
+// redirects of the client. Wrappers added first are outermost. See RoundTripperMiddleware to place a round tripper
+// among the middleware instead.
+func WithTransportWrapper(wrap ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transportWrappers = append(c.transportWrappers, wrap...)
+	}
+}
+
+// wrapTransport wraps the transport with the transport wrappers, http.DefaultTransport standing for nil.
+func (c *Client) wrapTransport(transport http.RoundTripper) http.RoundTripper {
+	if len(c.transportWrappers) == 0 {
+		return transport
+	}
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(c.transportWrappers) - 1; i >= 0; i-- {
+		transport = c.transportWrappers[i](transport)
+	}
+
+	return transport
+}
+
+// RoundTripperMiddleware adapts a round tripper wrapper into Middleware, so that an external round tripper runs
+// at the position of the middleware among the others, seeing each attempt of the requests.
+// The wrapper is called for each attempt, so it should be cheap.
+func RoundTripperMiddleware(wrap func(http.RoundTripper) http.RoundTripper) Middleware {
+	return func(next Handler) Handler {
+		return wrap(handlerTransport(next)).RoundTrip
+	}
+}
+
+// handlerTransport adapts a Handler to http.RoundTripper.
+type handlerTransport Handler
+
+// RoundTrip implements http.RoundTripper.
+func (h handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return h(req)
+}
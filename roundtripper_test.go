@@ -0,0 +1,129 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestTransport(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("X-Api-Key")))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithHeader("X-Api-Key", "secret"),
+		clink.WithRetryPolicy(clink.RetryPolicy{
+			MaxRetries:  1,
+			ShouldRetry: clink.RetryOnStatus(http.StatusServiceUnavailable),
+			Backoff:     func(int, *http.Response) time.Duration { return 0 },
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	sdkClient := &http.Client{Transport: client.Transport()}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := sdkClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "secret" || calls.Load() != 2 {
+		t.Errorf("expected a retried request with the client headers, got %q after %d calls", body, calls.Load())
+	}
+	if req.Header.Get("X-Api-Key") != "" {
+		t.Error("expected the request passed to the transport to be left untouched")
+	}
+}
+
+// headerTransport is an external round tripper setting a header on every request.
+type headerTransport struct {
+	next  http.RoundTripper
+	value string
+	calls *atomic.Int32
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	req = req.Clone(req.Context())
+	req.Header.Add("X-Via", t.value)
+	return t.next.RoundTrip(req)
+}
+
+func TestWithTransportWrapper(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Values("X-Via")[0] + "," + r.Header.Values("X-Via")[1]))
+	}))
+	defer server.Close()
+
+	var calls atomic.Int32
+	client := clink.NewClient(
+		clink.WithTransportWrapper(
+			func(next http.RoundTripper) http.RoundTripper { return headerTransport{next, "outer", &calls} },
+			func(next http.RoundTripper) http.RoundTripper { return headerTransport{next, "inner", &calls} },
+		),
+		clink.WithClient(server.Client()),
+	)
+
+	for _, opts := range [][]clink.RequestOption{nil, {clink.WithServerName("example.com")}} {
+		resp, err := client.Get(server.URL, opts...)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if string(body) != "outer,inner" {
+			t.Errorf("expected the wrappers to run outermost first, got: %s", body)
+		}
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("expected the wrappers to see 2 requests, got %d calls", calls.Load())
+	}
+}
+
+func TestRoundTripperMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Values("X-Via")[0] + "," + r.Header.Values("X-Via")[1]))
+	}))
+	defer server.Close()
+
+	var calls atomic.Int32
+	client := clink.NewClient(
+		clink.WithMiddleware(
+			func(next clink.Handler) clink.Handler {
+				return func(req *http.Request) (*http.Response, error) {
+					req.Header.Add("X-Via", "middleware")
+					return next(req)
+				}
+			},
+			clink.RoundTripperMiddleware(func(next http.RoundTripper) http.RoundTripper {
+				return headerTransport{next, "transport", &calls}
+			}),
+		),
+		clink.WithClient(server.Client()),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "middleware,transport" || calls.Load() != 1 {
+		t.Errorf("expected the round tripper to run after the first middleware, got: %s", body)
+	}
+}
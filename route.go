@@ -0,0 +1,87 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+type routeTemplatesKey struct{}
+
+var (
+	routeUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	routeHex  = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+)
+
+// WithRouteTemplates sets the route templates of the client, such as "/users/{id}" or "/repos/{owner}/{repo}/issues",
+// matched against the path of requests by Route. Placeholders match any single non-empty segment.
+func WithRouteTemplates(templates ...string) Option {
+	return func(c *Client) {
+		for _, template := range templates {
+			c.routeTemplates = append(c.routeTemplates, strings.Split(strings.Trim(template, "/"), "/"))
+		}
+	}
+}
+
+// Route returns the route of the request URL, to be used as a low cardinality label in metrics and logs instead of
+// the URL: the "route" tag of the request if set with WithTag, the first route template of the client matching the
+// path, or the path with its numeric, UUID and long hexadecimal segments replaced by {id}, {uuid} and {hex}.
+func Route(req *http.Request) string {
+	if req == nil || req.URL == nil {
+		return ""
+	}
+
+	if route := Tag(req, "route"); route != "" {
+		return route
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+
+	if templates, ok := requestValue[[][]string](req, routeTemplatesKey{}); ok {
+		for _, template := range templates {
+			if routeMatches(template, segments) {
+				return "/" + strings.Join(template, "/")
+			}
+		}
+	}
+
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+		case strings.Trim(segment, "0123456789") == "":
+			segments[i] = "{id}"
+		case routeUUID.MatchString(segment):
+			segments[i] = "{uuid}"
+		case routeHex.MatchString(segment):
+			segments[i] = "{hex}"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// routeMatches reports whether the path segments match the template segments.
+func routeMatches(template, segments []string) bool {
+	if len(template) != len(segments) {
+		return false
+	}
+
+	for i, part := range template {
+		isPlaceholder := strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}")
+		if isPlaceholder && segments[i] == "" || !isPlaceholder && part != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withRouteTemplates makes the route templates of the client available to Route.
+func (c *Client) withRouteTemplates(req *http.Request) *http.Request {
+	if len(c.routeTemplates) == 0 {
+		return req
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), routeTemplatesKey{}, c.routeTemplates))
+}
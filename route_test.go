@@ -0,0 +1,55 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestRoute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var route string
+	client := clink.NewClient(
+		clink.WithRouteTemplates("/repos/{owner}/{repo}/issues", "/users/me"),
+		clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				route = clink.Route(req)
+				return next(req)
+			}
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	tests := []struct {
+		name string
+		path string
+		opts []clink.RequestOption
+		want string
+	}{
+		{"template", "/repos/golang/go/issues?state=open", nil, "/repos/{owner}/{repo}/issues"},
+		{"literal template", "/users/me", nil, "/users/me"},
+		{"numeric id", "/users/123", nil, "/users/{id}"},
+		{"uuid", "/orders/0b7c7d9e-8f1a-4c2b-9d3e-5f6a7b8c9d0e/items/2", nil, "/orders/{uuid}/items/{id}"},
+		{"hex", "/blobs/9f86d081884c7d659a2feaa0c55ad015", nil, "/blobs/{hex}"},
+		{"unmatched template", "/repos/golang/go", nil, "/repos/golang/go"},
+		{"tag", "/users/123", []clink.RequestOption{clink.WithTag("route", "/users/:id")}, "/users/:id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Get(server.URL+tt.path, tt.opts...)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if route != tt.want {
+				t.Errorf("expected route %s, got: %s", tt.want, route)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package clink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Rule mutates the requests matching its URL pattern and methods. It can be decoded from JSON, such as
+// {"match": "api.example.com/v2/*", "methods": ["POST"], "headers": {"X-Tenant": "acme"}, "host": "gateway.internal"}.
+type Rule struct {
+	// Match is the URL pattern of the requests the rule applies to, see MatchURL. An empty pattern matches every request.
+	Match string `json:"match,omitempty"`
+	// Methods restricts the rule to the given methods. When empty, the rule applies to every method.
+	Methods []string `json:"methods,omitempty"`
+	// Headers are set on the request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Scheme replaces the scheme of the URL.
+	Scheme string `json:"scheme,omitempty"`
+	// Host replaces the host of the URL.
+	Host string `json:"host,omitempty"`
+	// StripPathPrefix is removed from the beginning of the path.
+	StripPathPrefix string `json:"strip_path_prefix,omitempty"`
+	// AddPathPrefix is added to the beginning of the path, after StripPathPrefix is removed.
+	AddPathPrefix string `json:"add_path_prefix,omitempty"`
+}
+
+// WithRules applies the rules to every request before it is sent. Rules are evaluated in order,
+// each against the URL as rewritten by the previous ones, and all matching rules apply.
+func WithRules(rules ...Rule) Option {
+	return func(c *Client) {
+		c.rules = append(c.rules, rules...)
+	}
+}
+
+// WithURLRewrite sends the requests to the from URL to the to URL instead, replacing the scheme and host,
+// and the path prefix of from with the path of to, such as WithURLRewrite("https://api.example.com/v1", "http://localhost:8080/mock").
+// from and to may be host names without scheme, and from matches any scheme when it has none.
+// Invalid URLs are ignored.
+func WithURLRewrite(from, to string) Option {
+	return func(c *Client) {
+		if rule, err := rewriteRule(from, to); err == nil {
+			c.rules = append(c.rules, rule)
+		}
+	}
+}
+
+// rewriteRule returns the rule sending the requests to the from URL to the to URL.
+func rewriteRule(from, to string) (Rule, error) {
+	fromURL, err := parseRewriteURL(from)
+	if err != nil {
+		return Rule{}, err
+	}
+	toURL, err := parseRewriteURL(to)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	match := fromURL.Host + strings.TrimSuffix(fromURL.Path, "/") + "/*"
+	if fromURL.Scheme != "" {
+		match = fromURL.Scheme + "://" + match
+	}
+
+	return Rule{
+		Match:           match,
+		Scheme:          toURL.Scheme,
+		Host:            toURL.Host,
+		StripPathPrefix: fromURL.Path,
+		AddPathPrefix:   toURL.Path,
+	}, nil
+}
+
+// parseRewriteURL parses a URL or a host name optionally followed by a path.
+func parseRewriteURL(s string) (*url.URL, error) {
+	if !strings.Contains(s, "://") {
+		s = "//" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host in %q", s)
+	}
+
+	return u, nil
+}
+
+// LoadRules decodes a JSON array of rules, such as the content of a configuration file.
+func LoadRules(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to decode rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// matches reports whether the rule applies to the request.
+func (r *Rule) matches(req *http.Request) bool {
+	if len(r.Methods) > 0 {
+		found := false
+		for _, method := range r.Methods {
+			if strings.EqualFold(method, req.Method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return MatchURL(r.Match, req.URL)
+}
+
+// apply mutates the request according to the rule.
+func (r *Rule) apply(req *http.Request) {
+	for key, value := range r.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if r.Scheme == "" && r.Host == "" && r.StripPathPrefix == "" && r.AddPathPrefix == "" {
+		return
+	}
+
+	u := *req.URL
+	if r.Scheme != "" {
+		u.Scheme = r.Scheme
+	}
+	if r.Host != "" {
+		u.Host = r.Host
+		if requestConfigFrom(req).host == "" {
+			req.Host = ""
+		}
+	}
+	if r.StripPathPrefix != "" || r.AddPathPrefix != "" {
+		p := strings.TrimPrefix(u.Path, strings.TrimSuffix(r.StripPathPrefix, "/"))
+		if p != "" && !strings.HasPrefix(p, "/") {
+			p = u.Path
+		}
+		u.Path = strings.TrimSuffix(r.AddPathPrefix, "/") + p
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		u.RawPath = ""
+	}
+	req.URL = &u
+}
+
+// applyRules applies the matching rules of the client to the request.
+func (c *Client) applyRules(req *http.Request) {
+	for i := range c.rules {
+		if c.rules[i].matches(req) {
+			c.rules[i].apply(req)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Rules(t *testing.T) {
+	var gotPath, gotTenant string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotTenant = r.URL.Path, r.Header.Get("X-Tenant")
+	}))
+	defer gateway.Close()
+
+	gatewayURL, _ := url.Parse(gateway.URL)
+	rules, err := clink.LoadRules(strings.NewReader(`[
+		{"match": "api.example.com/*", "scheme": "http", "host": "` + gatewayURL.Host + `", "add_path_prefix": "/example"},
+		{"match": "*/example/v1/*", "strip_path_prefix": "/example/v1", "add_path_prefix": "/example/v2"},
+		{"match": "*/example/*", "methods": ["POST"], "headers": {"X-Tenant": "acme"}}
+	]`))
+	if err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+
+	c := clink.NewClient(clink.WithRules(rules...), clink.WithClient(gateway.Client()))
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		path   string
+		tenant string
+	}{
+		{name: "host and prefix", method: http.MethodGet, url: "https://api.example.com/users", path: "/example/users"},
+		{name: "chained rewrite", method: http.MethodGet, url: "https://api.example.com/v1/users", path: "/example/v2/users"},
+		{name: "method header", method: http.MethodPost, url: "https://api.example.com/users", path: "/example/users", tenant: "acme"},
+		{name: "no match", method: http.MethodGet, url: gateway.URL + "/direct", path: "/direct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if gotPath != tt.path || gotTenant != tt.tenant {
+				t.Errorf("expected %s with tenant %q, got %s with tenant %q", tt.path, tt.tenant, gotPath, gotTenant)
+			}
+		})
+	}
+}
+
+func TestClient_URLRewrite(t *testing.T) {
+	var gotPath, gotQuery string
+	staging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+	}))
+	defer staging.Close()
+
+	c := clink.NewClient(
+		clink.WithURLRewrite("https://api.example.com/v1", staging.URL+"/mock"),
+		clink.WithURLRewrite("auth.example.com", staging.URL),
+		clink.WithClient(staging.Client()),
+	)
+
+	tests := []struct {
+		url   string
+		path  string
+		query string
+	}{
+		{url: "https://api.example.com/v1/users?page=2", path: "/mock/users", query: "page=2"},
+		{url: "https://api.example.com/v1", path: "/mock"},
+		{url: "http://auth.example.com/token", path: "/token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			gotPath, gotQuery = "", ""
+
+			resp, err := c.Get(tt.url)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if gotPath != tt.path || gotQuery != tt.query {
+				t.Errorf("expected %s?%s, got %s?%s", tt.path, tt.query, gotPath, gotQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ErrClientClosed is returned by scheduled requests that were still waiting when the client was closed.
+var ErrClientClosed = errors.New("client closed")
+
+// ScheduledRequest is a request waiting to be sent by DoAt or DoAfter.
+type ScheduledRequest struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	resp   *http.Response
+	err    error
+}
+
+// DoAt sends the request with Do at the given time, in the background. The request fails with the context error
+// if its context is done before, and with ErrClientClosed if the client is closed before.
+func (c *Client) DoAt(at time.Time, req *http.Request) *ScheduledRequest {
+	return c.DoAfter(at.Sub(c.clock().Now()), req)
+}
+
+// DoAfter sends the request with Do once the delay has elapsed, in the background, as DoAt does.
+func (c *Client) DoAfter(delay time.Duration, req *http.Request) *ScheduledRequest {
+	ctx, cancel := context.WithCancel(req.Context())
+	s := &ScheduledRequest{done: make(chan struct{}), cancel: cancel}
+	closing := c.closing()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer close(s.done)
+
+		var wait <-chan time.Time
+		if delay > 0 {
+			wait = clockAfter(ctx, c.clock(), delay)
+		} else {
+			ready := make(chan time.Time, 1)
+			ready <- time.Time{}
+			wait = ready
+		}
+
+		select {
+		case <-wait:
+			s.resp, s.err = c.Do(req.WithContext(ctx))
+		case <-ctx.Done():
+			s.err = ctx.Err()
+		case <-closing:
+			cancel()
+			s.err = ErrClientClosed
+		}
+	}()
+
+	return s
+}
+
+// Done returns a channel closed once the request was sent or failed.
+func (s *ScheduledRequest) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits for the request to be sent and returns its response.
+func (s *ScheduledRequest) Wait() (*http.Response, error) {
+	<-s.done
+
+	return s.resp, s.err
+}
+
+// Cancel cancels the request, failing it with context.Canceled if it was not sent yet
+// and canceling it otherwise.
+func (s *ScheduledRequest) Cancel() {
+	s.cancel()
+}
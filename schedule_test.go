@@ -0,0 +1,107 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestClient_DoAt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clinktest.NewClock(start)
+	client := clink.NewClient(clink.WithClock(clock), clink.WithClient(server.Client()))
+	defer func() { _ = client.Close() }()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	scheduled := client.DoAt(start.Add(time.Hour), req)
+
+	clock.BlockUntil(1)
+	if calls.Load() != 0 {
+		t.Fatal("expected the request to wait for its time")
+	}
+
+	clock.Advance(time.Hour)
+
+	resp, err := scheduled.Wait()
+	if err != nil {
+		t.Fatalf("scheduled request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted || calls.Load() != 1 {
+		t.Errorf("expected the request to be sent once, got status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, server.URL, nil)
+	resp, err = client.DoAt(start, req).Wait()
+	if err != nil || calls.Load() != 2 {
+		t.Fatalf("expected requests scheduled in the past to be sent right away, got %v", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestClient_DoAfter_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		stop    func(client *clink.Client, scheduled *clink.ScheduledRequest, cancel context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name:    "cancel",
+			stop:    func(_ *clink.Client, scheduled *clink.ScheduledRequest, _ context.CancelFunc) { scheduled.Cancel() },
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "request context",
+			stop:    func(_ *clink.Client, _ *clink.ScheduledRequest, cancel context.CancelFunc) { cancel() },
+			wantErr: context.Canceled,
+		},
+		{
+			name:    "client closed",
+			stop:    func(client *clink.Client, _ *clink.ScheduledRequest, _ context.CancelFunc) { _ = client.Close() },
+			wantErr: clink.ErrClientClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := clink.NewClient(clink.WithClient(server.Client()))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			scheduled := client.DoAfter(time.Hour, req)
+			tt.stop(client, scheduled, cancel)
+
+			select {
+			case <-scheduled.Done():
+			case <-time.After(time.Second):
+				t.Fatal("expected the scheduled request to stop")
+			}
+
+			if _, err := scheduled.Wait(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
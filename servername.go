@@ -0,0 +1,122 @@
+package clink
+
+import (
+	"container/list"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrServerNameUnsupported is returned by requests overriding the TLS server name when the transport of the client
+// is not an *http.Transport, as the server name cannot be set on other round trippers.
+var ErrServerNameUnsupported = errors.New("tls server name override unsupported by the transport")
+
+// WithHostHeader sends the request with the given Host header and presents it as the TLS server name (SNI),
+// whatever the host of the request URL. This allows hitting a specific IP or load balancer as a virtual host.
+// The request fails with ErrServerNameUnsupported if the transport of the client is not an *http.Transport.
+func WithHostHeader(host string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.host = host
+		cfg.serverName = host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			cfg.serverName = h
+		}
+	}
+}
+
+// WithServerName presents name as the TLS server name (SNI) of the request, keeping the Host header of the request URL.
+// This allows probing the virtual hosts served behind a single address. The request fails with
+// ErrServerNameUnsupported if the transport of the client is not an *http.Transport.
+func WithServerName(name string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.serverName = name
+	}
+}
+
+// maxServerNameClients is the number of server names whose dedicated transports are kept. The transport of the
+// least recently used server name is dropped beyond it, and its idle connections closed.
+const maxServerNameClients = 32
+
+type serverNameClient struct {
+	serverName string
+	client     *http.Client
+	transport  *http.Transport
+}
+
+// doWithServerName sends the request with the http client returned by httpClientFor.
+func (c *Client) doWithServerName(req *http.Request) (*http.Response, error) {
+	client, err := c.httpClientFor(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Do(req)
+}
+
+// httpClientFor returns the http client to send the request with.
+// Requests overriding the TLS server name use a dedicated transport per server name so that connections are not shared.
+func (c *Client) httpClientFor(req *http.Request) (*http.Client, error) {
+	serverName := requestConfigFrom(req).serverName
+	if serverName == "" || c.HttpClient == nil {
+		return c.HttpClient, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.serverNameClients[serverName]; ok {
+		c.serverNameLRU.MoveToFront(elem)
+		return elem.Value.(*serverNameClient).client, nil
+	}
+
+	base := c.HttpClient.Transport
+	if len(c.transportWrappers) > 0 {
+		base = c.baseTransport
+	}
+
+	var transport *http.Transport
+	switch t := base.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, ErrServerNameUnsupported
+	}
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.ServerName = serverName
+
+	client := *c.HttpClient
+	client.Transport = c.wrapTransport(transport)
+
+	if c.serverNameClients == nil {
+		c.serverNameClients = make(map[string]*list.Element)
+		c.serverNameLRU = list.New()
+	}
+	c.serverNameClients[serverName] = c.serverNameLRU.PushFront(&serverNameClient{
+		serverName: serverName,
+		client:     &client,
+		transport:  transport,
+	})
+
+	for c.serverNameLRU.Len() > maxServerNameClients {
+		entry := c.serverNameLRU.Remove(c.serverNameLRU.Back()).(*serverNameClient)
+		delete(c.serverNameClients, entry.serverName)
+		entry.transport.CloseIdleConnections()
+	}
+
+	return &client, nil
+}
+
+// clearServerNameClients drops the transports of every server name, closing their idle connections.
+// It must be called with c.mu held.
+func (c *Client) clearServerNameClients() {
+	for _, elem := range c.serverNameClients {
+		elem.Value.(*serverNameClient).transport.CloseIdleConnections()
+	}
+	c.serverNameClients, c.serverNameLRU = nil, nil
+}
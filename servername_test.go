@@ -0,0 +1,134 @@
+package clink_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestHostHeader(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + " " + r.TLS.ServerName))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := client.Get(server.URL, clink.WithHostHeader("example.com"))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "example.com example.com" {
+		t.Errorf("expected host and server name to be example.com, got: %s", body)
+	}
+
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ = io.ReadAll(resp.Body)
+	if string(body) == "example.com example.com" {
+		t.Errorf("expected requests without host header to use the url host")
+	}
+}
+
+func TestServerName(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + " " + r.TLS.ServerName))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	for _, name := range []string{"a.example.com", "b.example.com", "a.example.com"} {
+		resp, err := client.Get(server.URL, clink.WithServerName(name))
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if string(body) != host+" "+name {
+			t.Errorf("expected host %s and server name %s, got: %s", host, name, body)
+		}
+	}
+}
+
+func TestServerName_CustomTransport(t *testing.T) {
+	calls := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	client := clink.NewClient(clink.WithClient(&http.Client{Transport: transport}))
+
+	for _, opt := range []clink.RequestOption{clink.WithServerName("example.com"), clink.WithHostHeader("example.com")} {
+		if _, err := client.Get("https://127.0.0.1", opt); !errors.Is(err, clink.ErrServerNameUnsupported) {
+			t.Errorf("expected the server name override to fail, got %v", err)
+		}
+	}
+
+	if calls != 0 {
+		t.Errorf("expected no request to be sent without the server name, got %d", calls)
+	}
+}
+
+func TestServerName_EvictsTransports(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+	get := func(name string) {
+		t.Helper()
+		resp, err := client.Get(server.URL, clink.WithServerName(name))
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	newConns := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		n := conns
+		conns = 0
+		return n
+	}
+
+	for i := 0; i <= 32; i++ {
+		get(fmt.Sprintf("host%d.example.com", i))
+	}
+	newConns()
+
+	get("host32.example.com")
+	if n := newConns(); n != 0 {
+		t.Errorf("expected a recently used server name to reuse its connection, got %d new connections", n)
+	}
+
+	get("host0.example.com")
+	if n := newConns(); n != 1 {
+		t.Errorf("expected the least recently used server name to be evicted, got %d new connections", n)
+	}
+}
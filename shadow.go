@@ -0,0 +1,224 @@
+package clink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxShadowBody bounds how much of the primary and shadow response bodies is compared.
+const maxShadowBody = 1 << 20
+
+// shadowTimeout bounds how long a shadow request may take.
+const shadowTimeout = 30 * time.Second
+
+// ShadowDiff reports how the response to a request mirrored in shadow mode differs from the primary response.
+type ShadowDiff struct {
+	// Request is the primary request.
+	Request *http.Request
+	// PrimaryStatus and ShadowStatus are the status codes of the responses, or 0 when a request failed.
+	PrimaryStatus int
+	ShadowStatus  int
+	// Fields holds the compared JSON paths whose values differ, when the status codes are the same.
+	Fields []ShadowFieldDiff
+	// Err is the error of the shadow request, if it failed.
+	Err error
+}
+
+// ShadowFieldDiff is a JSON path whose value differs between the primary and shadow responses.
+type ShadowFieldDiff struct {
+	Path    string
+	Primary any
+	Shadow  any
+}
+
+type shadowMode struct {
+	rule   Rule
+	paths  []string
+	onDiff func(ShadowDiff)
+}
+
+// WithShadow mirrors the requests to the primary base URL to the shadow base URL, rewriting them as WithURLRewrite does,
+// and calls onDiff when the status codes or the values at the given JSON paths of the responses differ, or when the
+// shadow request fails. Paths are dot separated object keys and array indexes, such as "data.items.0.id".
+// Shadow requests are sent asynchronously, without retries or rate limiting, and the primary body is compared as the
+// caller reads it, so that the primary request is not slowed down. Invalid URLs are ignored.
+func WithShadow(primary, shadow string, paths []string, onDiff func(ShadowDiff)) Option {
+	return func(c *Client) {
+		if rule, err := rewriteRule(primary, shadow); err == nil {
+			c.shadow = &shadowMode{rule: rule, paths: paths, onDiff: onDiff}
+		}
+	}
+}
+
+type shadowResult struct {
+	status int
+	body   []byte
+	err    error
+}
+
+// shadowRequest is a request mirrored in shadow mode, waiting for the primary response to be compared.
+type shadowRequest struct {
+	client *Client
+	req    *http.Request
+	result chan shadowResult
+}
+
+// startShadow mirrors the request if it matches the shadow mode of the client.
+func (c *Client) startShadow(req *http.Request) (*shadowRequest, error) {
+	if c.shadow == nil || !c.shadow.rule.matches(req) {
+		return nil, nil
+	}
+
+	getBody, err := c.replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), shadowTimeout)
+	mirrored := req.Clone(ctx)
+	c.shadow.rule.apply(mirrored)
+	if getBody != nil {
+		if mirrored.Body, err = getBody(); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	s := &shadowRequest{client: c, req: req, result: make(chan shadowResult, 1)}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer cancel()
+
+		resp, err := c.HttpClient.Do(mirrored)
+		if err != nil {
+			s.result <- shadowResult{err: err}
+			return
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(resp.Body)
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxShadowBody))
+		s.result <- shadowResult{status: resp.StatusCode, body: body, err: err}
+	}()
+
+	return s, nil
+}
+
+// observe compares the primary response with the shadow response once the caller closes the primary body.
+func (s *shadowRequest) observe(resp *http.Response, err error) {
+	if err != nil || resp == nil || resp.Body == nil {
+		s.compare(0, nil)
+		return
+	}
+
+	resp.Body = &shadowBody{ReadCloser: resp.Body, done: func(body []byte) {
+		s.compare(resp.StatusCode, body)
+	}}
+}
+
+// compare waits for the shadow response in the background and reports the differences.
+func (s *shadowRequest) compare(status int, body []byte) {
+	c := s.client
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		result := <-s.result
+		diff := ShadowDiff{Request: s.req, PrimaryStatus: status, ShadowStatus: result.status, Err: result.err}
+		if result.err == nil && status == result.status {
+			diff.Fields = diffJSONPaths(c.shadow.paths, body, result.body)
+		}
+
+		if diff.Err != nil || diff.PrimaryStatus != diff.ShadowStatus || len(diff.Fields) > 0 {
+			c.shadow.onDiff(diff)
+		}
+	}()
+}
+
+// diffJSONPaths returns the paths whose values differ between the two JSON documents.
+func diffJSONPaths(paths []string, primary, shadow []byte) []ShadowFieldDiff {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var primaryDoc, shadowDoc any
+	_ = json.Unmarshal(primary, &primaryDoc)
+	_ = json.Unmarshal(shadow, &shadowDoc)
+
+	var diffs []ShadowFieldDiff
+	for _, path := range paths {
+		p, s := jsonPathValue(primaryDoc, path), jsonPathValue(shadowDoc, path)
+		if !reflect.DeepEqual(p, s) {
+			diffs = append(diffs, ShadowFieldDiff{Path: path, Primary: p, Shadow: s})
+		}
+	}
+
+	return diffs
+}
+
+// jsonPathValue returns the value at the dot separated path of the decoded JSON document, or nil if there is none.
+func jsonPathValue(doc any, path string) any {
+	for _, key := range strings.Split(path, ".") {
+		switch v := doc.(type) {
+		case map[string]any:
+			doc = v[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			doc = v[i]
+		default:
+			return nil
+		}
+	}
+
+	return doc
+}
+
+// shadowBody captures the primary response body as it is read, and hands it over when it is closed.
+type shadowBody struct {
+	io.ReadCloser
+
+	mu       sync.Mutex
+	captured []byte
+	done     func([]byte)
+	once     sync.Once
+}
+
+// Read implements io.Reader.
+func (b *shadowBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	if remaining := maxShadowBody - len(b.captured); remaining > 0 && n > 0 {
+		b.captured = append(b.captured, p[:min(n, remaining)]...)
+	}
+	b.mu.Unlock()
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *shadowBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	b.once.Do(func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.done(b.captured)
+	})
+
+	return err
+}
@@ -0,0 +1,97 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Shadow(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte(`{"id":1,"items":[{"name":"a"}],"version":"v1"}`))
+	}))
+	defer primary.Close()
+
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/v2/same":
+			_, _ = w.Write([]byte(`{"id":1,"items":[{"name":"a"}],"version":"v2"}`))
+		case "/v2/different":
+			if string(body) != "payload" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":1,"items":[{"name":"b"}]}`))
+		case "/v2/slow":
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer shadow.Close()
+
+	tests := []struct {
+		path   string
+		diffs  int
+		status int
+		fields []string
+	}{
+		{path: "/same"},
+		{path: "/different", diffs: 1, status: http.StatusOK, fields: []string{"items.0.name"}},
+		{path: "/slow", diffs: 1, status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			diffs := make(chan clink.ShadowDiff, 1)
+			c := clink.NewClient(
+				clink.WithShadow(primary.URL+"/v1", shadow.URL+"/v2", []string{"id", "items.0.name"}, func(diff clink.ShadowDiff) {
+					diffs <- diff
+				}),
+				clink.WithClient(primary.Client()),
+			)
+
+			start := time.Now()
+			resp, err := c.Post(primary.URL+"/v1"+tt.path, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			if time.Since(start) > 50*time.Millisecond {
+				t.Errorf("expected the primary request not to wait for the shadow")
+			}
+			if !strings.Contains(string(body), `"version":"v1"`) {
+				t.Errorf("expected the primary body, got %q", body)
+			}
+
+			_ = c.Close()
+			close(diffs)
+
+			var got []clink.ShadowDiff
+			for diff := range diffs {
+				got = append(got, diff)
+			}
+			if len(got) != tt.diffs {
+				t.Fatalf("expected %d diffs, got %v", tt.diffs, got)
+			}
+			if tt.diffs == 0 {
+				return
+			}
+
+			if got[0].PrimaryStatus != http.StatusOK || got[0].ShadowStatus != tt.status {
+				t.Errorf("expected statuses 200 and %d, got %d and %d", tt.status, got[0].PrimaryStatus, got[0].ShadowStatus)
+			}
+			if len(got[0].Fields) != len(tt.fields) || (len(tt.fields) > 0 && got[0].Fields[0].Path != tt.fields[0]) {
+				t.Errorf("expected field diffs %v, got %v", tt.fields, got[0].Fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package clink
+
+import "errors"
+
+// ErrLoadShed is returned for low priority requests dropped instead of waiting for a saturated limiter.
+var ErrLoadShed = errors.New("load shed")
+
+// Priority is the priority of a request, set with WithPriority.
+type Priority int
+
+const (
+	// PriorityLow requests, such as background jobs, are shed with ErrLoadShed instead of waiting for a saturated
+	// rate limiter or adaptive concurrency limit.
+	PriorityLow Priority = iota - 1
+	// PriorityNormal is the priority of requests without WithPriority.
+	PriorityNormal
+	// PriorityHigh requests, such as user-facing calls, are admitted ahead of the others waiting for the adaptive
+	// concurrency limit.
+	PriorityHigh
+)
+
+// WithPriority sets the priority of the request, so that background requests do not starve user-facing ones when
+// the rate limiter or the adaptive concurrency limit is saturated. Rate limiters that do not implement TryLimiter
+// are waited for regardless of priority.
+func WithPriority(priority Priority) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.priority = priority
+	}
+}
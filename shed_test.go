@@ -0,0 +1,81 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithPriorityConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		<-unblock
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithAdaptiveConcurrency(clink.AdaptiveConcurrency{InitialLimit: 1, MaxLimit: 1}),
+		clink.WithClient(server.Client()),
+	)
+
+	var wg sync.WaitGroup
+	get := func(path string, priority clink.Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.Get(server.URL+path, clink.WithPriority(priority)); err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+
+	get("/first", clink.PriorityNormal)
+	for _, inFlight := client.ConcurrencyLimit(); inFlight == 0; _, inFlight = client.ConcurrencyLimit() {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := client.Get(server.URL+"/background", clink.WithPriority(clink.PriorityLow))
+	if !errors.Is(err, clink.ErrLoadShed) {
+		t.Errorf("expected low priority request to be shed, got: %v", err)
+	}
+
+	get("/normal", clink.PriorityNormal)
+	time.Sleep(50 * time.Millisecond)
+	get("/high", clink.PriorityHigh)
+	time.Sleep(50 * time.Millisecond)
+
+	close(unblock)
+	wg.Wait()
+
+	if want := []string{"/first", "/high", "/normal"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected requests to be admitted in order %v, got: %v", want, paths)
+	}
+}
+
+func TestWithPriorityRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithRateLimit(1), clink.WithClient(server.Client()))
+
+	resp, err := client.Get(server.URL, clink.WithPriority(clink.PriorityLow))
+	if err != nil {
+		t.Fatalf("expected low priority request to pass the idle limiter, got: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if _, err := client.Get(server.URL, clink.WithPriority(clink.PriorityLow)); !errors.Is(err, clink.ErrLoadShed) {
+		t.Errorf("expected low priority request to be shed by the saturated limiter, got: %v", err)
+	}
+}
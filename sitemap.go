@@ -0,0 +1,114 @@
+package clink
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SitemapEntry is a URL listed in a sitemap.
+type SitemapEntry struct {
+	Loc        string
+	LastMod    time.Time
+	ChangeFreq string
+	Priority   float64
+}
+
+// maxSitemapDepth bounds how deep sitemap indexes are followed, to stop on indexes referencing each other.
+const maxSitemapDepth = 3
+
+type sitemapURL struct {
+	Loc        string  `xml:"loc"`
+	LastMod    string  `xml:"lastmod"`
+	ChangeFreq string  `xml:"changefreq"`
+	Priority   float64 `xml:"priority"`
+}
+
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapURL `xml:"url"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// Sitemap fetches the sitemap at url and returns its entries. Sitemap indexes are followed and gzip compressed
+// sitemaps are decompressed. The sitemaps are requested through the client, so its caching and rate limiting apply.
+func (c *Client) Sitemap(ctx context.Context, url string) ([]SitemapEntry, error) {
+	return c.sitemap(ctx, url, 0)
+}
+
+func (c *Client) sitemap(ctx context.Context, url string, depth int) ([]SitemapEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, newStatusError(req, resp)
+	}
+
+	body := bufio.NewReader(resp.Body)
+	var r io.Reader = body
+	// Gzip sitemaps are recognized by their magic bytes, as they are often served without a Content-Encoding.
+	if magic, _ := body.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(io.LimitReader(r, 50<<20)).Decode(&doc); err != nil {
+		return nil, &DecodeError{Content: "sitemap " + url, Err: err}
+	}
+
+	entries := make([]SitemapEntry, 0, len(doc.URLs))
+	for _, u := range doc.URLs {
+		entries = append(entries, u.entry())
+	}
+
+	if doc.XMLName.Local == "sitemapindex" && depth < maxSitemapDepth {
+		for _, s := range doc.Sitemaps {
+			children, err := c.sitemap(ctx, strings.TrimSpace(s.Loc), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, children...)
+		}
+	}
+
+	return entries, nil
+}
+
+func (u sitemapURL) entry() SitemapEntry {
+	entry := SitemapEntry{
+		Loc:        strings.TrimSpace(u.Loc),
+		ChangeFreq: strings.TrimSpace(u.ChangeFreq),
+		Priority:   u.Priority,
+	}
+
+	lastMod := strings.TrimSpace(u.LastMod)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, lastMod); err == nil {
+			entry.LastMod = t
+			break
+		}
+	}
+
+	return entry
+}
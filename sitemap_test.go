@@ -0,0 +1,68 @@
+package clink_test
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Sitemap(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/pages.xml</loc></sitemap>
+  <sitemap><loc>%[1]s/posts.xml.gz</loc></sitemap>
+</sitemapindex>`, server.URL)
+		case "/pages.xml":
+			_, _ = fmt.Fprint(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc><lastmod>2024-03-01</lastmod><changefreq>daily</changefreq><priority>1.0</priority></url>
+</urlset>`)
+		case "/posts.xml.gz":
+			gz := gzip.NewWriter(w)
+			_, _ = fmt.Fprint(gz, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/posts/1</loc><lastmod>2024-03-02T10:00:00+00:00</lastmod></url>
+  <url><loc>https://example.com/posts/2</loc></url>
+</urlset>`)
+			_ = gz.Close()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClient(server.Client()))
+
+	entries, err := c.Sitemap(context.Background(), server.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("failed to fetch sitemap: %v", err)
+	}
+
+	expected := []clink.SitemapEntry{
+		{Loc: "https://example.com/", LastMod: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), ChangeFreq: "daily", Priority: 1},
+		{Loc: "https://example.com/posts/1", LastMod: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/posts/2"},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Loc != expected[i].Loc || !entry.LastMod.Equal(expected[i].LastMod) ||
+			entry.ChangeFreq != expected[i].ChangeFreq || entry.Priority != expected[i].Priority {
+			t.Errorf("expected entry %d to be %+v, got %+v", i, expected[i], entry)
+		}
+	}
+
+	if _, err := c.Sitemap(context.Background(), server.URL+"/missing.xml"); err == nil {
+		t.Error("expected an error for a missing sitemap")
+	}
+}
@@ -0,0 +1,79 @@
+package clink
+
+import (
+	"bytes"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// skewMarkers are found in the error bodies of APIs rejecting requests signed with a skewed clock.
+var skewMarkers = [][]byte{
+	[]byte("RequestTimeTooSkewed"),
+	[]byte("RequestExpired"),
+	[]byte("Signature expired"),
+	[]byte("InvalidTimestamp"),
+	[]byte("clock skew"),
+}
+
+// IsClockSkewError reports whether the response rejects the request because of the clock of the client, as the
+// RequestTimeTooSkewed error of AWS. The beginning of the body is read to find known error codes and left readable.
+func IsClockSkewError(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+	default:
+		return false
+	}
+
+	if resp.Body == nil {
+		return false
+	}
+
+	peeked := peekBody(resp, 4096)
+
+	for _, marker := range skewMarkers {
+		if bytes.Contains(peeked, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithClockSkewCorrection retries requests rejected because of clock skew once, after correcting the clock of the client
+// with the Date header of the response. The corrected clock is used for timestamps such as those of WithReplayProtection.
+// When isSkewError is nil, IsClockSkewError is used.
+func WithClockSkewCorrection(isSkewError func(*http.Response) bool) Option {
+	return func(c *Client) {
+		if isSkewError == nil {
+			isSkewError = IsClockSkewError
+		}
+		c.isSkewError = isSkewError
+	}
+}
+
+// ClockOffset returns the correction applied to the local clock after clock skew errors.
+func (c *Client) ClockOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.clockOffset))
+}
+
+// now returns the current time corrected for clock skew.
+func (c *Client) now() time.Time {
+	return c.clock().Now().Add(c.ClockOffset())
+}
+
+// correctClockSkew updates the clock offset from the response if it reports a clock skew error, and reports whether it did.
+func (c *Client) correctClockSkew(resp *http.Response) bool {
+	if c.isSkewError == nil || resp == nil || !c.isSkewError(resp) {
+		return false
+	}
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return false
+	}
+
+	atomic.StoreInt64(&c.clockOffset, int64(date.Sub(c.clock().Now())))
+
+	return true
+}
@@ -0,0 +1,104 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestClient_ClockSkewCorrection(t *testing.T) {
+	serverOffset := 2 * time.Hour
+
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		status   int
+		attempts int
+		body     string
+	}{
+		{
+			name:     "retries with a corrected clock",
+			opts:     []clink.Option{clink.WithClockSkewCorrection(nil)},
+			status:   http.StatusOK,
+			attempts: 2,
+			body:     "payload",
+		},
+		{
+			name:     "fails without correction",
+			status:   http.StatusForbidden,
+			attempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			var body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				serverNow := time.Now().Add(serverOffset)
+				w.Header().Set("Date", serverNow.UTC().Format(http.TimeFormat))
+
+				ts, _ := strconv.ParseInt(r.Header.Get("X-Timestamp"), 10, 64)
+				if serverNow.Sub(time.UnixMilli(ts)).Abs() > 15*time.Minute {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`<Error><Code>RequestTimeTooSkewed</Code></Error>`))
+					return
+				}
+
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+			}))
+			defer server.Close()
+
+			opts := append(tc.opts, clink.WithReplayProtection(clink.ReplayProtection{}), clink.WithClient(server.Client()))
+			c := clink.NewClient(opts...)
+
+			resp, err := c.Post(server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tc.status || attempts != tc.attempts || body != tc.body {
+				t.Errorf("expected status %d after %d attempts with body %q, got %d after %d with %q", tc.status, tc.attempts, tc.body, resp.StatusCode, attempts, body)
+			}
+
+			if tc.status == http.StatusOK && (c.ClockOffset()-serverOffset).Abs() > 2*time.Second {
+				t.Errorf("expected a clock offset of about %v, got %v", serverOffset, c.ClockOffset())
+			}
+		})
+	}
+}
+
+func TestClient_ClockSkewCorrection_Clock(t *testing.T) {
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Date", clock.Now().Add(time.Hour).Format(http.TimeFormat))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<Error><Code>RequestTimeTooSkewed</Code></Error>`))
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClock(clock), clink.WithClockSkewCorrection(nil), clink.WithClient(server.Client()))
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if c.ClockOffset() != time.Hour {
+		t.Errorf("expected the offset to be measured on the client clock, got %v", c.ClockOffset())
+	}
+}
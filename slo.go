@@ -0,0 +1,177 @@
+package clink
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// sloWindow is the rolling window over which SLO compliance is measured.
+	sloWindow = 5 * time.Minute
+	// sloBuckets is the number of buckets the SLO window is divided in.
+	sloBuckets = 10
+	// sloMinRequests is the number of requests in the window below which an endpoint is never degraded.
+	sloMinRequests = 20
+	// sloFastBurn is the burn rate from which an endpoint is degraded.
+	sloFastBurn = 2
+)
+
+// SLOStatus reports the compliance of an endpoint with the SLO of the client over the last 5 minutes.
+type SLOStatus struct {
+	// Endpoint is the method, host and route of the requests, such as "GET api.example.com/users/{id}".
+	Endpoint   string
+	Requests   int
+	Violations int
+	// BurnRate is the ratio of violations to the error budget, 1 consuming the budget exactly over the window.
+	BurnRate float64
+	// Degraded is true while the budget burns at least twice as fast as allowed.
+	Degraded bool
+}
+
+// WithSLO tracks the compliance of each endpoint with a service level objective: requests must get a response in
+// less than latency, with neither an error nor a 5xx status, except for an errorBudget fraction of them,
+// such as 0.01. Endpoints are grouped by method, host and Route. An endpoint burning its budget at least twice as
+// fast as allowed over the last 5 minutes is degraded, emitting an SLOBurning state event, and an SLORecovered one
+// once its burn rate is back to 1 or below. Invalid objectives are ignored.
+func WithSLO(latency time.Duration, errorBudget float64) Option {
+	return func(c *Client) {
+		if latency <= 0 || errorBudget <= 0 || errorBudget >= 1 {
+			return
+		}
+
+		c.slo = &sloTracker{latency: latency, errorBudget: errorBudget, endpoints: make(map[string]*sloEndpoint)}
+	}
+}
+
+// SLOStatus returns the compliance of the endpoints with the SLO set with WithSLO, sorted by endpoint.
+func (c *Client) SLOStatus() []SLOStatus {
+	if c.slo == nil {
+		return nil
+	}
+
+	now := c.clock().Now()
+
+	c.slo.mu.Lock()
+	defer c.slo.mu.Unlock()
+
+	statuses := make([]SLOStatus, 0, len(c.slo.endpoints))
+	for name, endpoint := range c.slo.endpoints {
+		requests, violations := endpoint.counts(now)
+		statuses = append(statuses, SLOStatus{
+			Endpoint:   name,
+			Requests:   requests,
+			Violations: violations,
+			BurnRate:   c.slo.burnRate(requests, violations),
+			Degraded:   endpoint.degraded,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Endpoint < statuses[j].Endpoint })
+
+	return statuses
+}
+
+type sloTracker struct {
+	mu          sync.Mutex
+	latency     time.Duration
+	errorBudget float64
+	endpoints   map[string]*sloEndpoint
+}
+
+type sloBucket struct {
+	epoch      int64
+	requests   int
+	violations int
+}
+
+type sloEndpoint struct {
+	buckets  [sloBuckets]sloBucket
+	degraded bool
+}
+
+// sloEpoch returns the index of the bucket of the window containing now.
+func sloEpoch(now time.Time) int64 {
+	return now.UnixNano() / int64(sloWindow/sloBuckets)
+}
+
+// counts returns the number of requests and violations of the endpoint in the window ending at now.
+func (e *sloEndpoint) counts(now time.Time) (int, int) {
+	epoch := sloEpoch(now)
+
+	requests, violations := 0, 0
+	for _, bucket := range e.buckets {
+		if bucket.epoch > epoch-sloBuckets {
+			requests += bucket.requests
+			violations += bucket.violations
+		}
+	}
+
+	return requests, violations
+}
+
+// burnRate returns how fast the error budget is consumed by the violations.
+func (t *sloTracker) burnRate(requests, violations int) float64 {
+	if requests == 0 {
+		return 0
+	}
+
+	return float64(violations) / float64(requests) / t.errorBudget
+}
+
+// observeSLO records the outcome of the request against the SLO, emitting a state event when its endpoint becomes
+// degraded or recovers.
+func (c *Client) observeSLO(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if c.slo == nil {
+		return
+	}
+
+	name := req.Method + " " + req.URL.Host + Route(req)
+	violation := err != nil || elapsed >= c.slo.latency || resp.StatusCode >= http.StatusInternalServerError
+	now := c.clock().Now()
+
+	c.slo.mu.Lock()
+	endpoint, ok := c.slo.endpoints[name]
+	if !ok {
+		endpoint = &sloEndpoint{}
+		c.slo.endpoints[name] = endpoint
+	}
+
+	epoch := sloEpoch(now)
+	bucket := &endpoint.buckets[epoch%sloBuckets]
+	if bucket.epoch != epoch {
+		*bucket = sloBucket{epoch: epoch}
+	}
+	bucket.requests++
+	if violation {
+		bucket.violations++
+	}
+
+	requests, violations := endpoint.counts(now)
+	burnRate := c.slo.burnRate(requests, violations)
+	wasDegraded := endpoint.degraded
+	switch {
+	case !wasDegraded && requests >= sloMinRequests && burnRate >= sloFastBurn:
+		endpoint.degraded = true
+	case wasDegraded && burnRate <= 1:
+		endpoint.degraded = false
+	}
+	degraded := endpoint.degraded
+	c.slo.mu.Unlock()
+
+	if degraded == wasDegraded {
+		return
+	}
+
+	kind := SLORecovered
+	if degraded {
+		kind = SLOBurning
+	}
+
+	var statusCode int
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	c.emitState(StateEvent{Kind: kind, Request: req, Target: name, Err: err, StatusCode: statusCode})
+}
@@ -0,0 +1,70 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestWithSLO(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	clock := clinktest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var events []clink.StateEvent
+	client := clink.NewClient(
+		clink.WithSLO(time.Minute, 0.1),
+		clink.WithClock(clock),
+		clink.WithStateHook(func(event clink.StateEvent) {
+			events = append(events, event)
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	get := func(path string) {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	for i := 0; i < 15; i++ {
+		get("/users/" + string(rune('1'+i%9)))
+	}
+	for i := 0; i < 5; i++ {
+		get("/users/7?fail=1")
+	}
+
+	statuses := client.SLOStatus()
+	if len(statuses) != 1 {
+		t.Fatalf("expected 1 endpoint, got: %+v", statuses)
+	}
+
+	status := statuses[0]
+	if status.Endpoint != "GET "+server.Listener.Addr().String()+"/users/{id}" || status.Requests != 20 ||
+		status.Violations != 5 || status.BurnRate != 2.5 || !status.Degraded {
+		t.Errorf("expected degraded endpoint with 5 violations out of 20 requests, got: %+v", status)
+	}
+	if len(events) != 1 || events[0].Kind != clink.SLOBurning || events[0].Target != status.Endpoint {
+		t.Fatalf("expected an slo burning event, got: %+v", events)
+	}
+
+	clock.Advance(10 * time.Minute)
+	get("/users/1")
+
+	if status := client.SLOStatus()[0]; status.Requests != 1 || status.Degraded {
+		t.Errorf("expected endpoint to recover once violations left the window, got: %+v", status)
+	}
+	if len(events) != 2 || events[1].Kind != clink.SLORecovered {
+		t.Errorf("expected an slo recovered event, got: %+v", events)
+	}
+}
@@ -0,0 +1,84 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Snapshot is a serializable copy of a prepared request, so that it can be persisted and replayed later.
+// It encodes to JSON with the body as base64.
+type Snapshot struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Host   string      `json:"host,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Snapshot prepares the request as DryRun does and returns a snapshot of it.
+// The body of the original request is consumed.
+func (c *Client) Snapshot(req *http.Request) (*Snapshot, error) {
+	prepared, err := c.DryRun(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if prepared.Body != nil && prepared.Body != http.NoBody {
+		body, err = io.ReadAll(prepared.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		_ = prepared.Body.Close()
+	}
+
+	snapshot := &Snapshot{
+		Method: prepared.Method,
+		URL:    prepared.URL.String(),
+		Header: prepared.Header,
+		Body:   body,
+	}
+
+	if prepared.Host != "" && prepared.Host != prepared.URL.Host {
+		snapshot.Host = prepared.Host
+	}
+
+	return snapshot, nil
+}
+
+// Request recreates the request from the snapshot.
+func (s *Snapshot) Request(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, s.Method, s.URL, bytes.NewReader(s.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if len(s.Body) == 0 {
+		req.Body = http.NoBody
+		req.GetBody = nil
+	}
+
+	req.Header = s.Header.Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	if s.Host != "" {
+		req.Host = s.Host
+	}
+
+	return req, nil
+}
+
+// Replay sends the request recreated from the snapshot.
+func (c *Client) Replay(ctx context.Context, s *Snapshot) (*http.Response, error) {
+	req, err := s.Request(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req)
+}
@@ -0,0 +1,52 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestSnapshotReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.Method + " " + r.Header.Get("Authorization") + " " + string(body)))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithBearerAuth("token"),
+	)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	snapshot, err := client.Snapshot(req)
+	if err != nil {
+		t.Fatalf("failed to snapshot request: %v", err)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+
+	var restored clink.Snapshot
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+
+	resp, err := clink.NewClient(clink.WithClient(server.Client())).Replay(context.Background(), &restored)
+	if err != nil {
+		t.Fatalf("failed to replay request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "POST Bearer token payload" {
+		t.Errorf("unexpected replayed request: %s", body)
+	}
+}
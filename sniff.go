@@ -0,0 +1,122 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"mime"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// xssiPrefixes are the prefixes some servers put before JSON bodies to prevent them from being executed as scripts.
+var xssiPrefixes = [][]byte{[]byte(")]}'"), []byte("while(1);"), []byte("for(;;);")}
+
+// SniffContent decodes bodies whatever their Content-Type claims, detecting the encoding from a byte order mark,
+// the pattern of null bytes of UTF-16 and UTF-32 text, or the charset parameter for ISO-8859-1 bodies.
+// A UTF-8 byte order mark and anti-XSSI prefixes such as ")]}'" are stripped.
+func SniffContent() DecodeOption {
+	return func(cfg *decodeConfig) {
+		cfg.sniffContent = true
+	}
+}
+
+// WithContentSniffing applies SniffContent to the responses returned by the client, for servers sending JSON
+// with a missing or wrong Content-Type or encoding.
+func WithContentSniffing() Option {
+	return func(c *Client) {
+		c.decodeOptions = append(c.decodeOptions, SniffContent())
+	}
+}
+
+// sniffJSON returns the body read from r converted to UTF-8, without byte order mark or anti-XSSI prefix.
+func sniffJSON(r io.Reader, contentType string) (io.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data = toUTF8(data, contentType)
+	data = bytes.TrimLeft(data, " \t\r\n")
+	for _, prefix := range xssiPrefixes {
+		if bytes.HasPrefix(data, prefix) {
+			data = bytes.TrimPrefix(data[len(prefix):], []byte(","))
+			break
+		}
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// toUTF8 converts the data to UTF-8, detecting its encoding as described in RFC 4627 section 3.
+func toUTF8(data []byte, contentType string) []byte {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return data[3:]
+	case bytes.HasPrefix(data, []byte{0x00, 0x00, 0xFE, 0xFF}):
+		return decodeUTF32(data[4:], binary.BigEndian)
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE, 0x00, 0x00}):
+		return decodeUTF32(data[4:], binary.LittleEndian)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], binary.BigEndian)
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], binary.LittleEndian)
+	}
+
+	if len(data) >= 4 {
+		switch {
+		case data[0] == 0 && data[1] == 0 && data[2] == 0 && data[3] != 0:
+			return decodeUTF32(data, binary.BigEndian)
+		case data[0] != 0 && data[1] == 0 && data[2] == 0 && data[3] == 0:
+			return decodeUTF32(data, binary.LittleEndian)
+		case data[0] == 0 && data[1] != 0 && data[2] == 0 && data[3] != 0:
+			return decodeUTF16(data, binary.BigEndian)
+		case data[0] != 0 && data[1] == 0 && data[2] != 0 && data[3] == 0:
+			return decodeUTF16(data, binary.LittleEndian)
+		}
+	}
+
+	if !utf8.Valid(data) && isLatin1(contentType) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return []byte(string(runes))
+	}
+
+	return data
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+
+	return []byte(string(utf16.Decode(units)))
+}
+
+func decodeUTF32(data []byte, order binary.ByteOrder) []byte {
+	runes := make([]rune, len(data)/4)
+	for i := range runes {
+		runes[i] = rune(order.Uint32(data[4*i:]))
+	}
+
+	return []byte(string(runes))
+}
+
+// isLatin1 reports whether the charset parameter of the content type is ISO-8859-1.
+func isLatin1(contentType string) bool {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(params["charset"]) {
+	case "iso-8859-1", "iso8859-1", "latin1", "l1":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,101 @@
+package clink_test
+
+import (
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/davesavic/clink"
+)
+
+func utf16Bytes(s string, order binary.AppendByteOrder, bom bool) []byte {
+	var data []byte
+	if bom {
+		data = order.AppendUint16(data, 0xFEFF)
+	}
+	for _, unit := range utf16.Encode([]rune(s)) {
+		data = order.AppendUint16(data, unit)
+	}
+
+	return data
+}
+
+func TestClient_ContentSniffing(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		sniff       bool
+		wantErr     bool
+	}{
+		{
+			name:        "utf-8 byte order mark",
+			contentType: "text/html",
+			body:        append([]byte{0xEF, 0xBB, 0xBF}, `{"name":"café"}`...),
+			sniff:       true,
+		},
+		{
+			name:        "utf-8 byte order mark without sniffing",
+			contentType: "text/html",
+			body:        append([]byte{0xEF, 0xBB, 0xBF}, `{"name":"café"}`...),
+			wantErr:     true,
+		},
+		{
+			name:  "utf-16 little endian with byte order mark",
+			body:  utf16Bytes(`{"name":"café"}`, binary.LittleEndian, true),
+			sniff: true,
+		},
+		{
+			name:        "utf-16 big endian without byte order mark",
+			contentType: "text/plain",
+			body:        utf16Bytes(`{"name":"café"}`, binary.BigEndian, false),
+			sniff:       true,
+		},
+		{
+			name:        "latin1 charset",
+			contentType: "text/html; charset=ISO-8859-1",
+			body:        []byte("{\"name\":\"caf\xe9\"}"),
+			sniff:       true,
+		},
+		{
+			name:        "anti-xssi prefix",
+			contentType: "application/javascript",
+			body:        []byte(")]}',\n{\"name\":\"café\"}"),
+			sniff:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			opts := []clink.Option{clink.WithClient(server.Client())}
+			if tt.sniff {
+				opts = append([]clink.Option{clink.WithContentSniffing()}, opts...)
+			}
+			c := clink.NewClient(opts...)
+
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			var target struct {
+				Name string `json:"name"`
+			}
+			err = clink.ResponseToJson(resp, &target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && target.Name != "café" {
+				t.Errorf("expected name café, got %q", target.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SOAPVersion is the version of the SOAP protocol used for envelopes.
+type SOAPVersion int
+
+const (
+	// SOAP11 is SOAP 1.1, using text/xml and the SOAPAction header.
+	SOAP11 SOAPVersion = iota
+	// SOAP12 is SOAP 1.2, using application/soap+xml with the action as a media type parameter.
+	SOAP12
+)
+
+const (
+	soap11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// SOAPFault is returned when decoding a SOAP response holding a fault.
+type SOAPFault struct {
+	Code string
+	// Subcode is the subcode value of SOAP 1.2 faults, refining Code.
+	Subcode string
+	Reason  string
+	Actor   string
+	// Detail holds the raw XML content of the fault detail element.
+	Detail string
+}
+
+// Error implements the error interface.
+func (f *SOAPFault) Error() string {
+	return fmt.Sprintf("soap fault %s: %s", f.Code, f.Reason)
+}
+
+// SOAPBody sets the body of the request to a SOAP envelope holding the XML encoding of payload,
+// along with the content type and action headers of the SOAP version.
+func SOAPBody(version SOAPVersion, action string, payload any) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.body = func(_ *Client, req *http.Request) error {
+			data, err := xml.Marshal(payload)
+			if err != nil {
+				return fmt.Errorf("failed to encode soap payload: %w", err)
+			}
+
+			namespace, contentType := soap11Namespace, "text/xml; charset=utf-8"
+			if version == SOAP12 {
+				namespace, contentType = soap12Namespace, "application/soap+xml; charset=utf-8"
+				if action != "" {
+					contentType += "; action=" + strconv.Quote(action)
+				}
+			} else {
+				req.Header.Set("SOAPAction", strconv.Quote(action))
+			}
+
+			var envelope bytes.Buffer
+			envelope.WriteString(xml.Header)
+			envelope.WriteString(`<soap:Envelope xmlns:soap="` + namespace + `"><soap:Body>`)
+			envelope.Write(data)
+			envelope.WriteString(`</soap:Body></soap:Envelope>`)
+
+			req.Header.Set("Content-Type", contentType)
+			setBody(req, envelope.Bytes(), "")
+
+			return nil
+		}
+	}
+}
+
+// DecodeSOAP decodes the content of the body of a SOAP 1.1 or 1.2 response envelope into the target.
+// A fault is returned as a *SOAPFault.
+func DecodeSOAP[T any](response *http.Response, target *T) error {
+	if response == nil {
+		return ErrNilResponse
+	}
+
+	if response.Body == nil {
+		return ErrNilBody
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	if err := responseDecodeConfig(response, nil).checkContentType("text/xml", "application/soap+xml"); err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Body struct {
+			Content []byte `xml:",innerxml"`
+		} `xml:"Body"`
+	}
+	if err := xml.NewDecoder(response.Body).Decode(&envelope); err != nil {
+		return &DecodeError{Content: "soap envelope", Err: err}
+	}
+
+	content := envelope.Body.Content
+	if soapRootElement(content) == "Fault" {
+		var fault struct {
+			Code      string       `xml:"faultcode"`
+			String    string       `xml:"faultstring"`
+			Actor     string       `xml:"faultactor"`
+			Detail    soapInnerXML `xml:"detail"`
+			Code12    string       `xml:"Code>Value"`
+			Reason12  string       `xml:"Reason>Text"`
+			Role12    string       `xml:"Role"`
+			Detail12  soapInnerXML `xml:"Detail"`
+			Subcode12 string       `xml:"Code>Subcode>Value"`
+		}
+		if err := xml.Unmarshal(content, &fault); err != nil {
+			return &DecodeError{Content: "soap fault", Err: err}
+		}
+
+		if fault.Code12 != "" || fault.Reason12 != "" {
+			return &SOAPFault{
+				Code:    fault.Code12,
+				Subcode: fault.Subcode12,
+				Reason:  fault.Reason12,
+				Actor:   fault.Role12,
+				Detail:  strings.TrimSpace(fault.Detail12.XML),
+			}
+		}
+
+		return &SOAPFault{Code: fault.Code, Reason: fault.String, Actor: fault.Actor, Detail: strings.TrimSpace(fault.Detail.XML)}
+	}
+
+	if err := xml.Unmarshal(content, target); err != nil {
+		return &DecodeError{Content: "soap body", Err: err}
+	}
+
+	return nil
+}
+
+type soapInnerXML struct {
+	XML string `xml:",innerxml"`
+}
+
+// soapRootElement returns the local name of the first element of the XML content.
+func soapRootElement(content []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
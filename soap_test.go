@@ -0,0 +1,94 @@
+package clink_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type soapAdd struct {
+	XMLName xml.Name `xml:"http://tempuri.org/ Add"`
+	A       int      `xml:"intA"`
+	B       int      `xml:"intB"`
+}
+
+type soapAddResponse struct {
+	Result int `xml:"AddResult"`
+}
+
+func TestSOAP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "<intA>1</intA>") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<soap:Fault><faultcode>soap:Client</faultcode><faultstring>bad input</faultstring><detail><x>1</x></detail></soap:Fault>
+</soap:Body></soap:Envelope>`))
+			return
+		}
+
+		w.Header().Set("X-Action", r.Header.Get("SOAPAction")+r.Header.Get("Content-Type"))
+		_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+<AddResponse xmlns="http://tempuri.org/"><AddResult>3</AddResult></AddResponse>
+</soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := client.Post(server.URL, nil, clink.SOAPBody(clink.SOAP11, "http://tempuri.org/Add", soapAdd{A: 1, B: 2}))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if resp.Header.Get("X-Action") != `"http://tempuri.org/Add"text/xml; charset=utf-8` {
+		t.Errorf("unexpected soap headers: %s", resp.Header.Get("X-Action"))
+	}
+
+	var result soapAddResponse
+	if err := clink.DecodeSOAP(resp, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if result.Result != 3 {
+		t.Errorf("expected result 3, got %d", result.Result)
+	}
+
+	resp, err = client.Post(server.URL, nil, clink.SOAPBody(clink.SOAP12, "Add", soapAdd{A: 5}))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	var fault *clink.SOAPFault
+	if err := clink.DecodeSOAP(resp, &result); !errors.As(err, &fault) || fault.Code != "soap:Client" ||
+		fault.Reason != "bad input" || fault.Detail != "<x>1</x>" {
+		t.Errorf("expected soap fault, got: %v", err)
+	}
+}
+
+func TestDecodeSOAP_Fault12(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{"Content-Type": []string{"application/soap+xml"}},
+		Body: io.NopCloser(strings.NewReader(`<?xml version="1.0"?>
+<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>
+<env:Code><env:Value>env:Sender</env:Value><env:Subcode><env:Value>m:InvalidInput</env:Value></env:Subcode></env:Code>
+<env:Reason><env:Text xml:lang="en">bad input</env:Text></env:Reason>
+</env:Fault></env:Body></env:Envelope>`)),
+	}
+
+	var result soapAddResponse
+	var fault *clink.SOAPFault
+	if err := clink.DecodeSOAP(resp, &result); !errors.As(err, &fault) || fault.Code != "env:Sender" ||
+		fault.Subcode != "m:InvalidInput" || fault.Reason != "bad input" {
+		t.Errorf("expected soap 1.2 fault with subcode, got: %+v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// TrafficPrimary labels the requests of a traffic split sent to the primary URL.
+	TrafficPrimary = "primary"
+	// TrafficCanary labels the requests of a traffic split sent to the canary URL.
+	TrafficCanary = "canary"
+)
+
+type trafficVariantKey struct{}
+
+type trafficSplit struct {
+	canary  Rule
+	percent float64
+}
+
+// WithTrafficSplit sends percent percent of the requests to the primary base URL to the canary base URL instead,
+// rewriting them as WithURLRewrite does. The requests of the split are labelled with TrafficPrimary or TrafficCanary,
+// which middleware and hooks can read with TrafficVariant. Invalid URLs are ignored.
+func WithTrafficSplit(primary, canary string, percent float64) Option {
+	return func(c *Client) {
+		if rule, err := rewriteRule(primary, canary); err == nil {
+			c.trafficSplit = &trafficSplit{canary: rule, percent: percent}
+		}
+	}
+}
+
+// TrafficVariant returns the label of the traffic split the request was routed by, or "" when it is not part of a split.
+// It also works on the request of a response.
+func TrafficVariant(req *http.Request) string {
+	variant, _ := requestValue[string](req, trafficVariantKey{})
+
+	return variant
+}
+
+// splitTraffic routes the request to the primary or canary URL of the traffic split, labelling it.
+func (c *Client) splitTraffic(req *http.Request) *http.Request {
+	split := c.trafficSplit
+	if split == nil || !split.canary.matches(req) {
+		return req
+	}
+
+	// Dry runs do not draw from the source set with WithRandom.
+	random := c.random
+	if isDryRun(req) {
+		random = nil
+	}
+
+	variant := TrafficPrimary
+	if random.Float64()*100 < split.percent {
+		variant = TrafficCanary
+		split.canary.apply(req)
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), trafficVariantKey{}, variant))
+}
@@ -0,0 +1,71 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_TrafficSplit(t *testing.T) {
+	var primaryHits, canaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+	}))
+	defer primary.Close()
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/users" {
+			canaryHits.Add(1)
+		}
+	}))
+	defer canary.Close()
+
+	tests := []struct {
+		name    string
+		percent float64
+		min     int32
+		max     int32
+	}{
+		{name: "none", percent: 0, min: 0, max: 0},
+		{name: "half", percent: 50, min: 50, max: 150},
+		{name: "all", percent: 100, min: 200, max: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primaryHits.Store(0)
+			canaryHits.Store(0)
+
+			labels := map[string]int32{}
+			c := clink.NewClient(
+				clink.WithTrafficSplit(primary.URL+"/v1", canary.URL+"/v2", tt.percent),
+				clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+					return func(req *http.Request) (*http.Response, error) {
+						labels[clink.TrafficVariant(req)]++
+						return next(req)
+					}
+				}),
+			)
+
+			for i := 0; i < 200; i++ {
+				resp, err := c.Get(primary.URL + "/v1/users")
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			if got := canaryHits.Load(); got < tt.min || got > tt.max {
+				t.Errorf("expected between %d and %d canary requests, got %d", tt.min, tt.max, got)
+			}
+			if primaryHits.Load()+canaryHits.Load() != 200 {
+				t.Errorf("expected every request to reach a server, got %d and %d", primaryHits.Load(), canaryHits.Load())
+			}
+			if labels[clink.TrafficCanary] != canaryHits.Load() || labels[clink.TrafficPrimary] != primaryHits.Load() {
+				t.Errorf("expected the requests to be labelled, got %v", labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package clink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// WithSpooling reads response bodies entirely when Do returns, keeping those of up to threshold bytes in memory
+// and writing larger ones to a temporary file in dir, or the default temporary directory when empty.
+// The body of the response can then be seeked, see SpooledBody, and the temporary file is removed when it is closed.
+func WithSpooling(threshold int64, dir string) Option {
+	return func(c *Client) {
+		c.spool = &spoolConfig{threshold: threshold, dir: dir}
+	}
+}
+
+type spoolConfig struct {
+	threshold int64
+	dir       string
+}
+
+// SpooledBody returns the body of a response of a client created with WithSpooling as an io.ReadSeeker,
+// reporting whether it was spooled to a temporary file. It returns false if the body of the response is not spooled.
+func SpooledBody(resp *http.Response) (body io.ReadSeeker, onDisk bool, ok bool) {
+	if resp == nil {
+		return nil, false, false
+	}
+
+	reader := resp.Body
+	if captured, isCaptured := reader.(*capturingBody); isCaptured {
+		reader = captured.ReadCloser
+	}
+
+	spooled, ok := reader.(*spooledBody)
+	if !ok {
+		return nil, false, false
+	}
+
+	return spooled, spooled.file != nil, true
+}
+
+// spoolBody replaces the response body with a seekable copy, in memory or in a temporary file.
+func (c *Client) spoolBody(resp *http.Response) error {
+	if c.spool == nil || resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	var buf bytes.Buffer
+	n, err := buf.ReadFrom(io.LimitReader(resp.Body, c.spool.threshold+1))
+	if err != nil {
+		return fmt.Errorf("failed to spool response body: %w", err)
+	}
+
+	if n <= c.spool.threshold {
+		resp.Body = &spooledBody{ReadSeeker: bytes.NewReader(buf.Bytes())}
+		resp.ContentLength = n
+		return nil
+	}
+
+	file, err := os.CreateTemp(c.spool.dir, "clink-spool-*")
+	if err != nil {
+		return fmt.Errorf("failed to spool response body: %w", err)
+	}
+
+	size, err := io.Copy(file, io.MultiReader(&buf, resp.Body))
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return fmt.Errorf("failed to spool response body: %w", err)
+	}
+
+	resp.Body = &spooledBody{ReadSeeker: file, file: file}
+	resp.ContentLength = size
+
+	return nil
+}
+
+// spooledBody is a response body read from memory or from a temporary file, removed when closed.
+type spooledBody struct {
+	io.ReadSeeker
+	file   *os.File
+	closed bool
+}
+
+// Close implements io.Closer.
+func (b *spooledBody) Close() error {
+	if b.file == nil || b.closed {
+		return nil
+	}
+	b.closed = true
+
+	err := b.file.Close()
+	if removeErr := os.Remove(b.file.Name()); removeErr != nil && !os.IsNotExist(removeErr) {
+		return removeErr
+	}
+
+	return err
+}
@@ -0,0 +1,67 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithSpooling(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       int
+		wantOnDisk bool
+	}{
+		{name: "small body in memory", size: 100},
+		{name: "body at threshold in memory", size: 1024},
+		{name: "large body on disk", size: 10000, wantOnDisk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := strings.Repeat("a", tt.size)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(payload))
+			}))
+			defer server.Close()
+
+			dir := t.TempDir()
+			client := clink.NewClient(clink.WithSpooling(1024, dir), clink.WithClient(server.Client()))
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			body, onDisk, ok := clink.SpooledBody(resp)
+			if !ok || onDisk != tt.wantOnDisk {
+				t.Fatalf("expected spooled body on disk %v, got %v %v", tt.wantOnDisk, onDisk, ok)
+			}
+			if resp.ContentLength != int64(tt.size) {
+				t.Errorf("expected content length %d, got %d", tt.size, resp.ContentLength)
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := body.Seek(0, io.SeekStart); err != nil {
+					t.Fatalf("failed to seek: %v", err)
+				}
+				data, err := io.ReadAll(body)
+				if err != nil || string(data) != payload {
+					t.Fatalf("expected the body to be read again after seeking, got %d bytes: %v", len(data), err)
+				}
+			}
+
+			if err := resp.Body.Close(); err != nil {
+				t.Errorf("failed to close body: %v", err)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("expected the temporary file to be removed, got %d files", len(entries))
+			}
+		})
+	}
+}
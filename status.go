@@ -0,0 +1,99 @@
+package clink
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StatusError is returned when a response has a status code that was not expected.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	// Body holds the beginning of the response body, to help diagnose the error.
+	Body []byte
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s for %s %s", e.Status, e.Method, e.URL)
+}
+
+// Is makes errors.Is match ErrPreconditionFailed for 412 responses.
+func (e *StatusError) Is(target error) bool {
+	return target == ErrPreconditionFailed && e.StatusCode == http.StatusPreconditionFailed
+}
+
+// WithExpectedStatus makes the client return a *StatusError for any response whose status code is not one of codes.
+func WithExpectedStatus(codes ...int) Option {
+	return func(c *Client) {
+		c.expectedStatus = codes
+	}
+}
+
+// WithStatusErrors makes the client return a *StatusError for 4xx and 5xx responses.
+// Expected statuses set with WithExpectedStatus or ExpectStatus take precedence.
+func WithStatusErrors() Option {
+	return func(c *Client) {
+		c.statusErrors = true
+	}
+}
+
+// ExpectStatus makes the request return a *StatusError for any response whose status code is not one of codes.
+// It takes precedence over WithExpectedStatus.
+func ExpectStatus(codes ...int) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.expectedStatus = codes
+	}
+}
+
+// checkStatus returns a *StatusError, closing the response body, if the response status is not expected.
+// Conditional requests failing with 412 always return a *StatusError matching ErrPreconditionFailed.
+func (c *Client) checkStatus(req *http.Request, resp *http.Response) error {
+	if err := checkPrecondition(req, resp); err != nil {
+		return err
+	}
+
+	expected := requestConfigFrom(req).expectedStatus
+	if expected == nil {
+		expected = c.expectedStatus
+	}
+
+	if len(expected) == 0 {
+		if c.statusErrors && resp.StatusCode >= http.StatusBadRequest {
+			return newStatusError(req, resp)
+		}
+		return nil
+	}
+
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+
+	return newStatusError(req, resp)
+}
+
+// newStatusError returns a *StatusError for the response, reading the beginning of the body and closing it.
+func newStatusError(req *http.Request, resp *http.Response) *StatusError {
+	var body []byte
+	if resp.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+	}
+
+	err := &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       body,
+	}
+	if req != nil {
+		err.Method = req.Method
+		err.URL = req.URL.String()
+	}
+
+	return err
+}
@@ -0,0 +1,86 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestExpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		path       string
+		reqOpts    []clink.RequestOption
+		resultFunc func(*http.Response, error) bool
+	}{
+		{
+			name: "no expected status",
+			path: "/missing",
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusNotFound
+			},
+		},
+		{
+			name: "client expected status matches",
+			opts: []clink.Option{clink.WithExpectedStatus(http.StatusOK, http.StatusCreated)},
+			path: "/",
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusCreated
+			},
+		},
+		{
+			name: "client expected status does not match",
+			opts: []clink.Option{clink.WithExpectedStatus(http.StatusOK, http.StatusCreated)},
+			path: "/missing",
+			resultFunc: func(resp *http.Response, err error) bool {
+				var statusErr *clink.StatusError
+				return resp == nil && errors.As(err, &statusErr) &&
+					statusErr.StatusCode == http.StatusNotFound && string(statusErr.Body) == "not found"
+			},
+		},
+		{
+			name:    "request expected status overrides client",
+			opts:    []clink.Option{clink.WithExpectedStatus(http.StatusOK)},
+			path:    "/missing",
+			reqOpts: []clink.RequestOption{clink.ExpectStatus(http.StatusNotFound)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusNotFound
+			},
+		},
+		{
+			name:    "request expected status does not match",
+			path:    "/",
+			reqOpts: []clink.RequestOption{clink.ExpectStatus(http.StatusOK)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				var statusErr *clink.StatusError
+				return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusCreated
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append(tc.opts, clink.WithClient(server.Client()))
+			client := clink.NewClient(opts...)
+
+			resp, err := client.Get(server.URL+tc.path, tc.reqOpts...)
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v, %v", resp, err)
+			}
+		})
+	}
+}
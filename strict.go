@@ -0,0 +1,66 @@
+package clink
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrUnexpectedContentType is matched by the *ContentTypeError returned in strict mode.
+var ErrUnexpectedContentType = errors.New("unexpected content type")
+
+// ContentTypeError is returned in strict mode when the Content-Type of a response does not match the media type
+// expected by the decoder.
+type ContentTypeError struct {
+	ContentType string
+	Expected    []string
+}
+
+// Error implements the error interface.
+func (e *ContentTypeError) Error() string {
+	if e.ContentType == "" {
+		return fmt.Sprintf("missing content type, expected %s", strings.Join(e.Expected, " or "))
+	}
+
+	return fmt.Sprintf("unexpected content type %q, expected %s", e.ContentType, strings.Join(e.Expected, " or "))
+}
+
+// Is makes errors.Is match ErrUnexpectedContentType.
+func (e *ContentTypeError) Is(target error) bool {
+	return target == ErrUnexpectedContentType
+}
+
+// StrictContentType fails decoding with a *ContentTypeError, before reading the body, when the Content-Type
+// of the response does not match the expected media type. Structured syntax suffixes match, so that
+// application/problem+json is accepted as JSON.
+func StrictContentType() DecodeOption {
+	return func(cfg *decodeConfig) {
+		cfg.strictContentType = true
+	}
+}
+
+// WithStrictContentTypes applies StrictContentType to the responses returned by the client.
+func WithStrictContentTypes() Option {
+	return func(c *Client) {
+		c.decodeOptions = append(c.decodeOptions, StrictContentType())
+	}
+}
+
+// checkContentType returns a *ContentTypeError in strict mode when the content type matches none of the expected media types.
+func (cfg *decodeConfig) checkContentType(expected ...string) error {
+	if !cfg.strictContentType {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(cfg.contentType)
+	if err == nil {
+		for _, e := range expected {
+			if mediaType == e || strings.HasSuffix(mediaType, "+"+e[strings.Index(e, "/")+1:]) {
+				return nil
+			}
+		}
+	}
+
+	return &ContentTypeError{ContentType: cfg.contentType, Expected: expected}
+}
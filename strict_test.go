@@ -0,0 +1,64 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_StrictContentTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		strict      bool
+		wantErr     bool
+	}{
+		{name: "json", contentType: "application/json; charset=utf-8", strict: true},
+		{name: "json suffix", contentType: "application/problem+json", strict: true},
+		{name: "html", contentType: "text/html", strict: true, wantErr: true},
+		{name: "missing", strict: true, wantErr: true},
+		{name: "html without strict mode", contentType: "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header()["Content-Type"] = []string{tt.contentType}
+				_, _ = w.Write([]byte(`{"name":"clink"}`))
+			}))
+			defer server.Close()
+
+			opts := []clink.Option{clink.WithClient(server.Client())}
+			if tt.strict {
+				opts = append([]clink.Option{clink.WithStrictContentTypes()}, opts...)
+			}
+			c := clink.NewClient(opts...)
+
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			var target struct {
+				Name string `json:"name"`
+			}
+			err = clink.ResponseToJson(resp, &target)
+			if tt.wantErr {
+				var contentTypeErr *clink.ContentTypeError
+				if !errors.Is(err, clink.ErrUnexpectedContentType) || !errors.As(err, &contentTypeErr) || contentTypeErr.ContentType != tt.contentType {
+					t.Fatalf("expected a content type error for %q, got %v", tt.contentType, err)
+				}
+				if target.Name != "" {
+					t.Errorf("expected the target to be left untouched, got %q", target.Name)
+				}
+				return
+			}
+			if err != nil || target.Name != "clink" {
+				t.Errorf("expected the body to be decoded, got %q %v", target.Name, err)
+			}
+		})
+	}
+}
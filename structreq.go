@@ -0,0 +1,247 @@
+package clink
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DoStruct sends the request defined by the struct pointed to by def, and decodes the JSON response into target,
+// leaving it untouched when the response has no content or target is nil. The response headers are bound into the
+// fields of target tagged `header:"Name"`, see DecodeHeaders. The request struct declares its method and path with
+// a tag on a blank field, and each field sets a part of the request:
+//
+//	type GetUserRequest struct {
+//		_      struct{} `clink:"GET /users/{id}"`
+//		ID     int      `path:"id"`
+//		Page   int      `query:"page,omitempty"`
+//		Tenant string   `header:"X-Tenant"`
+//		Body   any      `json:"body"`
+//	}
+//
+// Path values are escaped, and placeholders of the path without a field fail the request. Slices add a query
+// parameter or header value per element, nil pointers and zero values tagged omitempty are left out, and the field
+// tagged `json:"body"` is JSON encoded as the request body unless it is a nil pointer, interface, map or slice, zero
+// structs and false booleans being sent.
+// Times are formatted as RFC 3339. Responses with a status code of 300 or more return a *StatusError.
+func (c *Client) DoStruct(ctx context.Context, def any, target any, opts ...RequestOption) error {
+	req, err := c.newStructRequest(ctx, def)
+	if err != nil {
+		return err
+	}
+	req = WithRequestOptions(req, opts...)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return newStatusError(req, resp)
+	}
+
+	if target == nil || resp.StatusCode == http.StatusNoContent {
+		discardBody(resp)
+		return decodeStructHeaders(resp, target)
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	cfg := responseDecodeConfig(resp, nil)
+	if err := cfg.checkContentType("application/json"); err != nil {
+		return err
+	}
+
+	if err := cfg.decode(resp.Body, target); err != nil && !errors.Is(err, io.EOF) {
+		return &DecodeError{Content: "response", Err: err}
+	}
+
+	return decodeStructHeaders(resp, target)
+}
+
+// decodeStructHeaders binds the response headers into the header tagged fields of target, if any.
+func decodeStructHeaders(resp *http.Response, target any) error {
+	if !hasHeaderFields(target) {
+		return nil
+	}
+
+	return DecodeHeaders(resp, target)
+}
+
+// newStructRequest builds the request defined by the tags of the struct pointed to by def.
+func (c *Client) newStructRequest(ctx context.Context, def any) (*http.Request, error) {
+	v := reflect.ValueOf(def)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid request definition %T: not a struct", def)
+	}
+
+	var method, path string
+	query, header := url.Values{}, http.Header{}
+	var body any
+	hasBody := false
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if endpoint, ok := field.Tag.Lookup("clink"); ok {
+			method, path, _ = strings.Cut(endpoint, " ")
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Tag.Get("json") == "body" {
+			switch value.Kind() {
+			case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+				if value.IsNil() {
+					continue
+				}
+			}
+			body, hasBody = value.Interface(), true
+			continue
+		}
+
+		for _, key := range []string{"path", "query", "header"} {
+			tag, ok := field.Tag.Lookup(key)
+			if !ok {
+				continue
+			}
+
+			name, options, _ := strings.Cut(tag, ",")
+			values, err := structValues(value, options == "omitempty")
+			if err != nil {
+				return nil, fmt.Errorf("invalid request field %s: %w", field.Name, err)
+			}
+
+			switch key {
+			case "path":
+				if len(values) != 1 {
+					return nil, fmt.Errorf("invalid request field %s: path values must be set", field.Name)
+				}
+				path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(values[0]))
+			case "query":
+				query[name] = append(query[name], values...)
+			case "header":
+				for _, value := range values {
+					header.Add(name, value)
+				}
+			}
+		}
+	}
+
+	if method == "" || path == "" {
+		return nil, fmt.Errorf("invalid request definition %T: missing `clink:\"METHOD /path\"` tag", def)
+	}
+
+	if start := strings.Index(path, "{"); start >= 0 {
+		if end := strings.Index(path[start:], "}"); end >= 0 {
+			return nil, fmt.Errorf("invalid request definition %T: no path field for %s", def, path[start:start+end+1])
+		}
+	}
+
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		path += separator + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if hasBody {
+		if err := c.setJSONBody(req, body, "application/json"); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// structValues formats the value of a field, one string per element of slices.
+// Nil pointers, and zero values when omitEmpty is set, have no values.
+func structValues(v reflect.Value, omitEmpty bool) ([]string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if omitEmpty && v.IsZero() {
+		return nil, nil
+	}
+
+	if v.Kind() == reflect.Array || v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := structValues(v.Index(i), false)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elem...)
+		}
+		return values, nil
+	}
+
+	value, err := formatStructValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{value}, nil
+}
+
+// formatStructValue formats a single value of a field.
+func formatStructValue(v reflect.Value) (string, error) {
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339), nil
+	case encoding.TextMarshaler:
+		text, err := value.MarshalText()
+		return string(text), err
+	case fmt.Stringer:
+		return value.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		return string(v.Bytes()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
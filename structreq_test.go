@@ -0,0 +1,123 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+type updateUserRequest struct {
+	_       struct{}   `clink:"PUT /orgs/{org}/users/{id}"`
+	Org     string     `path:"org"`
+	ID      int        `path:"id"`
+	Fields  []string   `query:"fields"`
+	Page    int        `query:"page,omitempty"`
+	Since   *time.Time `query:"since"`
+	Tenant  string     `header:"X-Tenant"`
+	Body    userUpdate `json:"body"`
+	ignored string
+}
+
+type getUserRequest struct {
+	_    struct{} `clink:"GET /users/{id}"`
+	ID   int      `path:"id"`
+	Body any      `json:"body"`
+}
+
+type publishRequest struct {
+	_    struct{} `clink:"POST /posts/{id}/published"`
+	ID   int      `path:"id"`
+	Body bool     `json:"body"`
+}
+
+type missingPathRequest struct {
+	_  struct{} `clink:"GET /orgs/{org}/users/{id}"`
+	ID int      `path:"id"`
+}
+
+type userUpdate struct {
+	Name string `json:"name"`
+}
+
+func TestDoStruct(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"method":      r.Method,
+			"path":        r.URL.EscapedPath(),
+			"query":       r.URL.RawQuery,
+			"tenant":      r.Header.Get("X-Tenant"),
+			"body":        string(body),
+			"contentType": r.Header.Get("Content-Type"),
+		})
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithBaseURL(server.URL), clink.WithClient(server.Client()))
+
+	var got map[string]string
+	err := client.DoStruct(context.Background(), &updateUserRequest{
+		Org:    "acme corp",
+		ID:     5,
+		Fields: []string{"name", "email"},
+		Tenant: "t1",
+		Body:   userUpdate{Name: "Ada"},
+	}, &got)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	want := map[string]string{
+		"method": http.MethodPut,
+		"path":   "/orgs/acme%20corp/users/5",
+		"query":  "fields=name&fields=email",
+		"tenant": "t1",
+		"body":   `{"name":"Ada"}`,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("expected %s %q, got: %q", key, value, got[key])
+		}
+	}
+
+	got = nil
+	if err := client.DoStruct(context.Background(), &getUserRequest{ID: 5}, &got); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	if got["method"] != http.MethodGet || got["body"] != "" || got["contentType"] != "" {
+		t.Errorf("expected a GET request without body, got: %v", got)
+	}
+
+	zeroBodies := []struct {
+		def  any
+		want string
+	}{
+		{def: &updateUserRequest{Org: "acme", ID: 5}, want: `{"name":""}`},
+		{def: &publishRequest{ID: 5}, want: "false"},
+	}
+	for _, tt := range zeroBodies {
+		got = nil
+		if err := client.DoStruct(context.Background(), tt.def, &got); err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		if strings.TrimSpace(got["body"]) != tt.want {
+			t.Errorf("expected the zero body of %T to be sent as %s, got: %q", tt.def, tt.want, got["body"])
+		}
+	}
+
+	if err := client.DoStruct(context.Background(), &userUpdate{}, nil); err == nil {
+		t.Error("expected an error for a struct without endpoint")
+	}
+
+	if err := client.DoStruct(context.Background(), &missingPathRequest{ID: 5}, nil); err == nil || !strings.Contains(err.Error(), "{org}") {
+		t.Errorf("expected an error for the path placeholder without field, got %v", err)
+	}
+}
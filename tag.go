@@ -0,0 +1,44 @@
+package clink
+
+import "net/http"
+
+// WithTag tags the request with a key and value, such as WithTag("operation", "getUser"), so that the middleware
+// and hooks receiving it can group telemetry by logical operation rather than by URL. See Tags.
+func WithTag(key, value string) RequestOption {
+	return func(cfg *requestConfig) {
+		tags := make(map[string]string, len(cfg.tags)+1)
+		for k, v := range cfg.tags {
+			tags[k] = v
+		}
+		tags[key] = value
+		cfg.tags = tags
+	}
+}
+
+// Tags returns a copy of the tags set on the request with WithTag, or nil if it has none.
+func Tags(req *http.Request) map[string]string {
+	if req == nil {
+		return nil
+	}
+
+	cfg := requestConfigFrom(req)
+	if len(cfg.tags) == 0 {
+		return nil
+	}
+
+	tags := make(map[string]string, len(cfg.tags))
+	for k, v := range cfg.tags {
+		tags[k] = v
+	}
+
+	return tags
+}
+
+// Tag returns the value of the tag of the request, or an empty string if it is not set.
+func Tag(req *http.Request, key string) string {
+	if req == nil {
+		return ""
+	}
+
+	return requestConfigFrom(req).tags[key]
+}
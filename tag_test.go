@@ -0,0 +1,55 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var middlewareTags, waitTags map[string]string
+	client := clink.NewClient(
+		clink.WithMiddleware(func(next clink.Handler) clink.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				middlewareTags = clink.Tags(req)
+				return next(req)
+			}
+		}),
+		clink.WithRateLimit(6000),
+		clink.WithRateLimitWaitHook(func(req *http.Request, _ time.Duration) {
+			waitTags = clink.Tags(req)
+		}),
+		clink.WithClient(server.Client()),
+	)
+
+	resp, err := client.Get(server.URL+"/users/123", clink.WithTag("operation", "getUser"), clink.WithTag("team", "identity"))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	want := map[string]string{"operation": "getUser", "team": "identity"}
+	if !reflect.DeepEqual(middlewareTags, want) || !reflect.DeepEqual(waitTags, want) {
+		t.Errorf("expected tags %v in hooks, got %v and %v", want, middlewareTags, waitTags)
+	}
+	if clink.Tag(resp.Request, "operation") != "getUser" {
+		t.Errorf("expected the response request to carry the tags, got %v", clink.Tags(resp.Request))
+	}
+
+	resp, err = client.Get(server.URL + "/users/456")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if middlewareTags != nil || clink.Tag(resp.Request, "operation") != "" {
+		t.Errorf("expected untagged requests to have no tags, got %v", middlewareTags)
+	}
+}
@@ -0,0 +1,90 @@
+package clink
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+)
+
+// transportTLSConfig returns the TLS configuration of the transport, creating it if needed.
+func transportTLSConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+
+	return t.TLSClientConfig
+}
+
+// WithTLSSessionCache caches up to capacity TLS sessions so that new connections resume them with an abbreviated handshake.
+func WithTLSSessionCache(capacity int) Option {
+	return func(c *Client) {
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			transportTLSConfig(t).ClientSessionCache = tls.NewLRUClientSessionCache(capacity)
+		})
+	}
+}
+
+// WithoutTLSResumption disables TLS session resumption, so that every connection performs a full handshake.
+func WithoutTLSResumption() Option {
+	return func(c *Client) {
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			config := transportTLSConfig(t)
+			config.ClientSessionCache = nil
+			config.SessionTicketsDisabled = true
+		})
+	}
+}
+
+// WithTLSPolicy sets the minimum TLS version and the cipher suites allowed for TLS 1.0 to 1.2 connections.
+// A nil cipherSuites keeps the Go defaults. TLS 1.3 cipher suites are not configurable and are all considered secure.
+func WithTLSPolicy(minVersion uint16, cipherSuites []uint16) Option {
+	return func(c *Client) {
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			config := transportTLSConfig(t)
+			config.MinVersion = minVersion
+			if cipherSuites != nil {
+				config.CipherSuites = cipherSuites
+			}
+		})
+	}
+}
+
+// WithStrictTLS requires TLS 1.2 or later with forward secret AEAD cipher suites.
+func WithStrictTLS() Option {
+	return WithTLSPolicy(tls.VersionTLS12, []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+	})
+}
+
+// WithVerifyPeerCertificate calls verify after the standard certificate verification of every TLS connection,
+// including resumed sessions, with the raw certificates sent by the server and the chains verified against the
+// trusted roots, so that custom trust decisions such as pinning or certificate transparency checks can reject
+// connections by returning an error. Callbacks already set on the TLS configuration run first.
+func WithVerifyPeerCertificate(verify func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error) Option {
+	return func(c *Client) {
+		c.transportOptions = append(c.transportOptions, func(t *http.Transport) {
+			config := transportTLSConfig(t)
+			// VerifyConnection is used rather than VerifyPeerCertificate, which is skipped when a session is resumed.
+			previous := config.VerifyConnection
+			config.VerifyConnection = func(cs tls.ConnectionState) error {
+				if previous != nil {
+					if err := previous(cs); err != nil {
+						return err
+					}
+				}
+
+				rawCerts := make([][]byte, len(cs.PeerCertificates))
+				for i, cert := range cs.PeerCertificates {
+					rawCerts[i] = cert.Raw
+				}
+
+				return verify(rawCerts, cs.VerifiedChains)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package clink_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_TLSResumption(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		option  clink.Option
+		resumed bool
+	}{
+		{name: "session cache resumes sessions", option: clink.WithTLSSessionCache(8), resumed: true},
+		{name: "resumption disabled", option: clink.WithoutTLSResumption(), resumed: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := server.Client()
+			httpClient.Transport.(*http.Transport).DisableKeepAlives = true
+
+			var mu sync.Mutex
+			var resumed []bool
+			c := clink.NewClient(
+				tc.option,
+				clink.WithConnectionHooks(clink.ConnectionHooks{
+					TLSHandshake: func(state tls.ConnectionState, err error) {
+						mu.Lock()
+						defer mu.Unlock()
+						resumed = append(resumed, state.DidResume)
+					},
+				}),
+				clink.WithClient(httpClient),
+			)
+
+			for i := 0; i < 2; i++ {
+				resp, err := c.Get(server.URL)
+				if err != nil {
+					t.Fatalf("request %d failed: %v", i, err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(resumed) != 2 || resumed[0] || resumed[1] != tc.resumed {
+				t.Errorf("expected the second handshake resumption to be %v, got %v", tc.resumed, resumed)
+			}
+		})
+	}
+}
+
+func TestClient_TLSPolicy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		server  *tls.Config
+		option  clink.Option
+		succeed bool
+	}{
+		{
+			name:    "strict policy accepts modern servers",
+			server:  &tls.Config{},
+			option:  clink.WithStrictTLS(),
+			succeed: true,
+		},
+		{
+			name:   "strict policy rejects non forward secret suites",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12, CipherSuites: []uint16{tls.TLS_RSA_WITH_AES_128_GCM_SHA256}},
+			option: clink.WithStrictTLS(),
+		},
+		{
+			name:   "minimum version rejects older servers",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12},
+			option: clink.WithTLSPolicy(tls.VersionTLS13, nil),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			server.TLS = tc.server
+			server.StartTLS()
+			defer server.Close()
+
+			c := clink.NewClient(tc.option, clink.WithClient(server.Client()))
+
+			resp, err := c.Get(server.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if (err == nil) != tc.succeed {
+				t.Errorf("expected success to be %v, got error %v", tc.succeed, err)
+			}
+		})
+	}
+}
+
+func TestWithVerifyPeerCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "accepted", err: nil},
+		{name: "rejected", err: errors.New("certificate not logged"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			var chains int
+			c := clink.NewClient(
+				clink.WithVerifyPeerCertificate(func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+					calls++
+					chains = len(verifiedChains)
+					return tc.err
+				}),
+				clink.WithClient(server.Client()),
+			)
+
+			resp, err := c.Get(server.URL)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if calls != 1 || chains == 0 {
+				t.Errorf("expected the callback to run once with verified chains, got %d calls and %d chains", calls, chains)
+			}
+		})
+	}
+}
+
+func TestWithVerifyPeerCertificate_ResumedSession(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	httpClient := server.Client()
+	httpClient.Transport.(*http.Transport).DisableKeepAlives = true
+
+	var mu sync.Mutex
+	var resumed []bool
+	var calls int
+	c := clink.NewClient(
+		clink.WithTLSSessionCache(8),
+		clink.WithVerifyPeerCertificate(func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			if len(rawCerts) == 0 || len(verifiedChains) == 0 {
+				return errors.New("missing certificates")
+			}
+			return nil
+		}),
+		clink.WithConnectionHooks(clink.ConnectionHooks{
+			TLSHandshake: func(state tls.ConnectionState, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				resumed = append(resumed, state.DidResume)
+			},
+		}),
+		clink.WithClient(httpClient),
+	)
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(resumed) != 2 || !resumed[1] {
+		t.Fatalf("expected the second connection to resume the session, got %v", resumed)
+	}
+	if calls != 2 {
+		t.Errorf("expected the callback to run on both handshakes, got %d calls", calls)
+	}
+}
@@ -0,0 +1,126 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// RequestTrailer declares trailers sent after the body of the request, which is then sent chunked.
+// values is called once the body has been read, and the values it returns for the declared keys are sent.
+func RequestTrailer(values func() http.Header, keys ...string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.trailers = append(cfg.trailers, func(_ *Client, req *http.Request) error {
+			setTrailer(req, keys, func(body io.ReadCloser) io.ReadCloser {
+				return &trailerBody{ReadCloser: body, r: body, done: func() {
+					trailer := values()
+					for _, key := range keys {
+						req.Trailer[http.CanonicalHeaderKey(key)] = trailer.Values(key)
+					}
+				}}
+			})
+			return nil
+		})
+	}
+}
+
+// ChecksumTrailer sends the hex encoded digest of the request body in the named trailer,
+// computed with the algorithm while the body is sent.
+func ChecksumTrailer(name string, algorithm HashAlgorithm) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.trailers = append(cfg.trailers, func(c *Client, req *http.Request) error {
+			if _, err := c.NewHash(algorithm); err != nil {
+				return err
+			}
+
+			setTrailer(req, []string{name}, func(body io.ReadCloser) io.ReadCloser {
+				h, _ := c.NewHash(algorithm)
+				return &trailerBody{ReadCloser: body, r: io.TeeReader(body, h), done: func() {
+					req.Trailer.Set(name, hex.EncodeToString(h.Sum(nil)))
+				}}
+			})
+			return nil
+		})
+	}
+}
+
+// setTrailer declares the trailer keys on the request and wraps its body, including the bodies returned by GetBody.
+func setTrailer(req *http.Request, keys []string, wrap func(io.ReadCloser) io.ReadCloser) {
+	if req.Trailer == nil {
+		req.Trailer = make(http.Header, len(keys))
+	}
+	for _, key := range keys {
+		req.Trailer[http.CanonicalHeaderKey(key)] = nil
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		req.Body = io.NopCloser(bytes.NewReader(nil))
+	}
+	req.Body = wrap(req.Body)
+	req.ContentLength = -1
+
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return wrap(body), nil
+		}
+	}
+}
+
+// trailerBody calls done once its reader is exhausted, so that trailers are set before the transport sends them.
+type trailerBody struct {
+	io.ReadCloser
+	r    io.Reader
+	done func()
+	once sync.Once
+}
+
+// Read implements io.Reader.
+func (b *trailerBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.done)
+	}
+
+	return n, err
+}
+
+// DeclaredTrailers returns the sorted names of the trailers the response announced in its Trailer header.
+// Their values are only available once the body has been read.
+func DeclaredTrailers(resp *http.Response) []string {
+	if resp == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(resp.Trailer))
+	for name := range resp.Trailer {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ReadTrailers reads and closes the response body, returning it with the trailers received after it,
+// such as Grpc-Status or checksum trailers.
+func ReadTrailers(resp *http.Response) ([]byte, http.Header, error) {
+	if resp == nil || resp.Body == nil {
+		return nil, nil, nil
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return body, resp.Trailer, nil
+}
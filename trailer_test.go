@@ -0,0 +1,91 @@
+package clink_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_RequestTrailers(t *testing.T) {
+	sum := sha256.Sum256([]byte("payload"))
+
+	tests := []struct {
+		name    string
+		opts    []clink.RequestOption
+		trailer http.Header
+	}{
+		{
+			name:    "values",
+			opts:    []clink.RequestOption{clink.RequestTrailer(func() http.Header { return http.Header{"X-Count": {"7"}} }, "X-Count")},
+			trailer: http.Header{"X-Count": {"7"}},
+		},
+		{
+			name:    "checksum",
+			opts:    []clink.RequestOption{clink.ChecksumTrailer("X-Checksum-Sha256", clink.HashSHA256)},
+			trailer: http.Header{"X-Checksum-Sha256": {hex.EncodeToString(sum[:])}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			var trailer http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				body, trailer = string(data), r.Trailer
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithClient(server.Client()))
+
+			resp, err := c.Post(server.URL, strings.NewReader("payload"), tt.opts...)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if body != "payload" {
+				t.Errorf("expected body payload, got %q", body)
+			}
+			if !reflect.DeepEqual(trailer, tt.trailer) {
+				t.Errorf("expected trailer %v, got %v", tt.trailer, trailer)
+			}
+		})
+	}
+}
+
+func TestReadTrailers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		_, _ = w.Write([]byte("data"))
+		w.Header().Set("Grpc-Status", "0")
+		w.Header().Set("Grpc-Message", "ok")
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithClient(server.Client()))
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if declared := clink.DeclaredTrailers(resp); !reflect.DeepEqual(declared, []string{"Grpc-Message", "Grpc-Status"}) {
+		t.Errorf("expected declared trailers, got %v", declared)
+	}
+
+	body, trailer, err := clink.ReadTrailers(resp)
+	if err != nil {
+		t.Fatalf("failed to read trailers: %v", err)
+	}
+	if string(body) != "data" || trailer.Get("Grpc-Status") != "0" || trailer.Get("Grpc-Message") != "ok" {
+		t.Errorf("expected body and trailers, got %q %v", body, trailer)
+	}
+}
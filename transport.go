@@ -0,0 +1,132 @@
+package clink
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// configureTransport applies the dialer and transport options to a copy of the http client's transport, then wraps
+// it with the transport wrappers. It runs once all options have been applied so that it does not depend on the order
+// of WithClient. Custom transports that are not an *http.Transport are only wrapped.
+func (c *Client) configureTransport() {
+	if c.HttpClient == nil || (c.dialer == nil && len(c.transportOptions) == 0 && len(c.transportWrappers) == 0) {
+		return
+	}
+
+	base := c.HttpClient.Transport
+	if c.dialer != nil || len(c.transportOptions) > 0 {
+		var transport *http.Transport
+		switch t := base.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		}
+
+		if transport != nil {
+			if c.dialer != nil {
+				transport.DialContext = c.dialContext
+			}
+
+			for _, opt := range c.transportOptions {
+				opt(transport)
+			}
+
+			base = transport
+		}
+	}
+
+	c.baseTransport = base
+
+	httpClient := *c.HttpClient
+	httpClient.Transport = c.wrapTransport(base)
+	c.HttpClient = &httpClient
+}
+
+// netDialer returns the dialer used for new connections, creating it with the same defaults as http.DefaultTransport.
+func (c *Client) netDialer() *net.Dialer {
+	if c.dialer == nil {
+		c.dialer = &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+	}
+
+	return c.dialer
+}
+
+// dialContext dials the given address using the client's dialer, applying any host overrides.
+func (c *Client) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if c.dialNetwork != "" && network == "tcp" {
+		network = c.dialNetwork
+	}
+
+	return c.dialer.DialContext(ctx, network, c.overrideAddr(addr))
+}
+
+// overrideAddr returns the address to dial for addr according to the host overrides.
+// An override for "host:port" takes precedence over an override for "host".
+func (c *Client) overrideAddr(addr string) string {
+	if len(c.hostOverrides) == 0 {
+		return addr
+	}
+
+	if target, ok := c.hostOverrides[addr]; ok {
+		return target
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	target, ok := c.hostOverrides[host]
+	if !ok {
+		return addr
+	}
+
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return net.JoinHostPort(target, port)
+	}
+
+	return target
+}
+
+// WithHostOverride dials target whenever a connection to host is made, like an /etc/hosts entry.
+// The host may include a port to only override that port, and the target may omit the port to keep the original one.
+// The request URL is unchanged so the Host header and TLS server name still use host.
+func WithHostOverride(host, target string) Option {
+	return func(c *Client) {
+		c.netDialer()
+		if c.hostOverrides == nil {
+			c.hostOverrides = make(map[string]string)
+		}
+		c.hostOverrides[host] = target
+	}
+}
+
+// WithIPv4Only only connects to IPv4 addresses.
+func WithIPv4Only() Option {
+	return func(c *Client) {
+		c.netDialer()
+		c.dialNetwork = "tcp4"
+	}
+}
+
+// WithIPv6Only only connects to IPv6 addresses.
+func WithIPv6Only() Option {
+	return func(c *Client) {
+		c.netDialer()
+		c.dialNetwork = "tcp6"
+	}
+}
+
+// WithFallbackDelay sets how long to wait for an IPv6 connection before falling back to IPv4 (Happy Eyeballs).
+// A negative delay disables the fallback so addresses are tried sequentially.
+func WithFallbackDelay(delay time.Duration) Option {
+	return func(c *Client) {
+		c.netDialer().FallbackDelay = delay
+	}
+}
@@ -0,0 +1,109 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestHostOverride(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + " " + r.TLS.ServerName))
+	}))
+	defer server.Close()
+
+	addr := strings.TrimPrefix(server.URL, "https://")
+
+	testCases := []struct {
+		name   string
+		host   string
+		target string
+		url    string
+		result string
+	}{
+		{
+			name:   "override host with address",
+			host:   "example.com",
+			target: addr,
+			url:    "https://example.com/",
+			result: "example.com example.com",
+		},
+		{
+			name:   "override host and port with address",
+			host:   "example.com:8443",
+			target: addr,
+			url:    "https://example.com:8443/",
+			result: "example.com:8443 example.com",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := clink.NewClient(
+				clink.WithClient(server.Client()),
+				clink.WithHostOverride(tc.host, tc.target),
+			)
+
+			resp, err := client.Get(tc.url)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tc.result {
+				t.Errorf("expected %q, got %q", tc.result, body)
+			}
+		})
+	}
+}
+
+func TestIPFamily(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		resultFunc func(*http.Response, error) bool
+	}{
+		{
+			name: "ipv4 only reaches ipv4 server",
+			opts: []clink.Option{clink.WithIPv4Only()},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK
+			},
+		},
+		{
+			name: "ipv6 only does not reach ipv4 server",
+			opts: []clink.Option{clink.WithIPv6Only()},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err != nil
+			},
+		},
+		{
+			name: "fallback delay",
+			opts: []clink.Option{clink.WithFallbackDelay(-1)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append(tc.opts, clink.WithClient(server.Client()))
+			client := clink.NewClient(opts...)
+
+			resp, err := client.Get(server.URL)
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v, %v", resp, err)
+			}
+		})
+	}
+}
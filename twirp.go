@@ -0,0 +1,75 @@
+package clink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// TwirpError is returned when decoding a Twirp response holding an error envelope.
+type TwirpError struct {
+	// Code is the Twirp error code, such as "not_found" or "invalid_argument".
+	Code       string
+	Msg        string
+	Meta       map[string]string
+	StatusCode int
+}
+
+// Error implements the error interface.
+func (e *TwirpError) Error() string {
+	return fmt.Sprintf("twirp error %s: %s", e.Code, e.Msg)
+}
+
+// NewTwirpRequest returns a POST request calling the method of a Twirp service, such as "example.haberdasher.Haberdasher",
+// served under baseURL with the default /twirp prefix, with the JSON encoding of message as body.
+func NewTwirpRequest(ctx context.Context, baseURL, service, method string, message any) (*http.Request, error) {
+	url := strings.TrimSuffix(baseURL, "/") + "/twirp/" + service + "/" + method
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return WithRequestOptions(req, JSONBody(message)), nil
+}
+
+// DecodeTwirp decodes the message of a Twirp JSON response into the target. Error responses are returned as
+// a *TwirpError, with an "internal" code when the body is not a Twirp error envelope.
+func DecodeTwirp[T any](response *http.Response, target *T) error {
+	if response == nil {
+		return ErrNilResponse
+	}
+
+	if response.StatusCode == http.StatusOK {
+		return ResponseToJson(response, target)
+	}
+
+	if response.Body == nil {
+		return ErrNilBody
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+
+	var envelope struct {
+		Code string            `json:"code"`
+		Msg  string            `json:"msg"`
+		Meta map[string]string `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Code == "" {
+		return &TwirpError{
+			Code:       "internal",
+			Msg:        "non-twirp error response with status " + response.Status,
+			Meta:       map[string]string{"body": string(body)},
+			StatusCode: response.StatusCode,
+		}
+	}
+
+	return &TwirpError{Code: envelope.Code, Msg: envelope.Msg, Meta: envelope.Meta, StatusCode: response.StatusCode}
+}
@@ -0,0 +1,111 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type twirpSize struct {
+	Inches int `json:"inches"`
+}
+
+type twirpHat struct {
+	Color string `json:"color"`
+	Size  int    `json:"size"`
+}
+
+func TestTwirp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/twirp/example.haberdasher.Haberdasher/MakeHat" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"bad_route","msg":"no handler for path"}`))
+			return
+		}
+
+		var size twirpSize
+		if err := json.NewDecoder(r.Body).Decode(&size); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if size.Inches <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"invalid_argument","msg":"inches must be positive","meta":{"argument":"inches"}}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(twirpHat{Color: "red", Size: size.Inches})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		method   string
+		size     twirpSize
+		wantCode string
+		wantMeta string
+	}{
+		{name: "success", method: "MakeHat", size: twirpSize{Inches: 12}},
+		{name: "error envelope", method: "MakeHat", wantCode: "invalid_argument", wantMeta: "inches"},
+		{name: "unknown method", method: "MakeShoe", size: twirpSize{Inches: 12}, wantCode: "bad_route"},
+	}
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := clink.NewTwirpRequest(context.Background(), server.URL+"/", "example.haberdasher.Haberdasher", tt.method, tt.size)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			var hat twirpHat
+			err = clink.DecodeTwirp(resp, &hat)
+
+			if tt.wantCode != "" {
+				var twirpErr *clink.TwirpError
+				if !errors.As(err, &twirpErr) || twirpErr.Code != tt.wantCode || twirpErr.Meta["argument"] != tt.wantMeta {
+					t.Fatalf("expected twirp error %s, got %v", tt.wantCode, err)
+				}
+				return
+			}
+
+			if err != nil || hat.Size != 12 || hat.Color != "red" {
+				t.Errorf("unexpected hat %+v: %v", hat, err)
+			}
+		})
+	}
+}
+
+func TestTwirp_NonTwirpError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()))
+
+	req, _ := clink.NewTwirpRequest(context.Background(), server.URL, "example.haberdasher.Haberdasher", "MakeHat", twirpSize{Inches: 1})
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	var hat twirpHat
+	var twirpErr *clink.TwirpError
+	if err := clink.DecodeTwirp(resp, &hat); !errors.As(err, &twirpErr) || twirpErr.Code != "internal" || twirpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected an internal twirp error, got %v", err)
+	}
+}
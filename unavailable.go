@@ -0,0 +1,101 @@
+package clink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrUpstreamUnavailable is matched by the *UpstreamUnavailableError returned for maintenance and challenge pages.
+var ErrUpstreamUnavailable = errors.New("upstream unavailable")
+
+// UpstreamUnavailableError is returned instead of an HTML maintenance or challenge page served on a JSON endpoint.
+// It is classified as retryable.
+type UpstreamUnavailableError struct {
+	StatusCode int
+	// Reason is "challenge" for bot protection challenges such as Cloudflare's, and "maintenance" otherwise.
+	Reason string
+	// RetryAfter is the delay requested by the Retry-After header, if any.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *UpstreamUnavailableError) Error() string {
+	return fmt.Sprintf("upstream unavailable (%s page, status %d)", e.Reason, e.StatusCode)
+}
+
+// Is makes errors.Is match ErrUpstreamUnavailable.
+func (e *UpstreamUnavailableError) Is(target error) bool {
+	return target == ErrUpstreamUnavailable
+}
+
+var (
+	challengeMarkers   = [][]byte{[]byte("challenge-platform"), []byte("cf-browser-verification"), []byte("Just a moment..."), []byte("cf_chl_")}
+	maintenanceMarkers = [][]byte{[]byte("maintenance"), []byte("Maintenance"), []byte("temporarily unavailable"), []byte("Temporarily Unavailable")}
+)
+
+// WithMaintenanceDetection makes requests expecting JSON fail with a *UpstreamUnavailableError when the server answers with
+// an HTML maintenance or challenge page, instead of failing later to decode it. Such failures are retried like network errors.
+func WithMaintenanceDetection() Option {
+	return func(c *Client) {
+		c.detectMaintenance = true
+	}
+}
+
+// UnavailablePage returns a *UpstreamUnavailableError if the response to a request expecting JSON is an HTML maintenance
+// or challenge page. The beginning of the body is read to find known markers and left readable.
+func UnavailablePage(req *http.Request, resp *http.Response) *UpstreamUnavailableError {
+	if resp == nil || resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return nil
+	}
+
+	if accept := req.Header.Get("Accept"); accept != "" && !strings.Contains(accept, "json") {
+		return nil
+	}
+
+	peeked := peekBody(resp, 8192)
+
+	unavailable := &UpstreamUnavailableError{StatusCode: resp.StatusCode}
+	if delay, ok := RetryAfter(resp); ok {
+		unavailable.RetryAfter = delay
+	}
+
+	if resp.Header.Get("Cf-Mitigated") == "challenge" || containsAny(peeked, challengeMarkers) {
+		unavailable.Reason = "challenge"
+		return unavailable
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError || containsAny(peeked, maintenanceMarkers) {
+		unavailable.Reason = "maintenance"
+		return unavailable
+	}
+
+	return nil
+}
+
+// detectUnavailable replaces maintenance and challenge pages with a *UpstreamUnavailableError when detection is enabled.
+func (c *Client) detectUnavailable(req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+	if !c.detectMaintenance || err != nil {
+		return resp, err
+	}
+
+	if unavailable := UnavailablePage(req, resp); unavailable != nil {
+		discardBody(resp)
+		return nil, unavailable
+	}
+
+	return resp, nil
+}
+
+func containsAny(data []byte, markers [][]byte) bool {
+	for _, marker := range markers {
+		if bytes.Contains(data, marker) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_MaintenanceDetection(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		accept  string
+		reason  string
+	}{
+		{
+			name: "maintenance page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.Header().Set("Retry-After", "120")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("<html><body>Down for maintenance</body></html>"))
+			},
+			reason: "maintenance",
+		},
+		{
+			name: "cloudflare challenge",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+				w.Header().Set("Cf-Mitigated", "challenge")
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte("<html><title>Just a moment...</title></html>"))
+			},
+			reason: "challenge",
+		},
+		{
+			name: "json error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error": "maintenance"}`))
+			},
+		},
+		{
+			name: "html requested",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("<html><body>Down for maintenance</body></html>"))
+			},
+			accept: "text/html",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				tc.handler(w, r)
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(
+				clink.WithMaintenanceDetection(),
+				clink.WithRetries(1, nil),
+				clink.WithBackoff(func(attempt int, resp *http.Response) time.Duration { return 0 }),
+				clink.WithClient(server.Client()),
+			)
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			resp, err := c.Do(req)
+			if tc.reason == "" {
+				if err != nil {
+					t.Fatalf("expected the response to be returned, got %v", err)
+				}
+				_ = resp.Body.Close()
+				return
+			}
+
+			var unavailable *clink.UpstreamUnavailableError
+			if !errors.Is(err, clink.ErrUpstreamUnavailable) || !errors.As(err, &unavailable) {
+				t.Fatalf("expected ErrUpstreamUnavailable, got %v", err)
+			}
+			if unavailable.Reason != tc.reason {
+				t.Errorf("expected reason %q, got %q", tc.reason, unavailable.Reason)
+			}
+			if attempts != 2 {
+				t.Errorf("expected the request to be retried, got %d attempts", attempts)
+			}
+		})
+	}
+}
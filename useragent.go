@@ -0,0 +1,67 @@
+package clink
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const modulePath = "github.com/davesavic/clink"
+
+var (
+	versionOnce sync.Once
+	version     string
+)
+
+// Version returns the version of the clink module the binary was built with, or "devel" if it is unknown.
+func Version() string {
+	versionOnce.Do(func() {
+		version = "devel"
+
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+			return
+		}
+
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+				return
+			}
+		}
+	})
+
+	return version
+}
+
+// WithUserAgentProduct adds a product to the user agent header of the client.
+// Products are listed in the order they are added, followed by the clink product and the Go version,
+// for example "app/1.2.0 clink/v0.3.0 (go1.21.4; linux/amd64)".
+func WithUserAgentProduct(name, version string) Option {
+	return func(c *Client) {
+		product := userAgentToken(name)
+		if version != "" {
+			product += "/" + userAgentToken(version)
+		}
+		c.userAgentProducts = append(c.userAgentProducts, product)
+
+		c.Headers["User-Agent"] = strings.Join(c.userAgentProducts, " ") +
+			" clink/" + Version() + " (" + runtime.Version() + "; " + runtime.GOOS + "/" + runtime.GOARCH + ")"
+	}
+}
+
+// userAgentToken replaces the characters that are not allowed in a product token with a dash.
+func userAgentToken(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r > ' ' && r < 0x7f && !strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return r
+		}
+		return '-'
+	}, s)
+}
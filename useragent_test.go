@@ -0,0 +1,26 @@
+package clink_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestUserAgentProduct(t *testing.T) {
+	client := clink.NewClient(
+		clink.WithUserAgentProduct("my app", "1.2.0"),
+		clink.WithUserAgentProduct("plugin", "0.1"),
+	)
+
+	ua := client.Headers["User-Agent"]
+	expectedPrefix := "my-app/1.2.0 plugin/0.1 clink/" + clink.Version()
+	if !strings.HasPrefix(ua, expectedPrefix) {
+		t.Errorf("expected user agent to start with %q, got %q", expectedPrefix, ua)
+	}
+
+	if !strings.HasSuffix(ua, "("+runtime.Version()+"; "+runtime.GOOS+"/"+runtime.GOARCH+")") {
+		t.Errorf("expected user agent to end with the go version, got %q", ua)
+	}
+}
@@ -0,0 +1,202 @@
+package clink
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrWebhookUndelivered is matched by the *WebhookError returned when an event could not be delivered.
+var ErrWebhookUndelivered = errors.New("webhook undelivered")
+
+// WebhookEvent is an event delivered by a WebhookSender. Its payload is sent as the JSON body of the request.
+type WebhookEvent struct {
+	// ID identifies the event and is sent as its idempotency key, so that receivers can ignore redeliveries.
+	// A random ID is generated when empty.
+	ID      string
+	Type    string
+	Payload any
+}
+
+// WebhookError is returned when an event could not be delivered, after its last attempt.
+type WebhookError struct {
+	Event    WebhookEvent
+	URL      string
+	Attempts int
+	// StatusCode is the status of the last response, or 0 if the last attempt failed with a network error.
+	StatusCode int
+	Err        error
+}
+
+// Error implements the error interface.
+func (e *WebhookError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("webhook %s undelivered to %s after %d attempts: %v", e.Event.ID, e.URL, e.Attempts, e.Err)
+	}
+
+	return fmt.Sprintf("webhook %s undelivered to %s after %d attempts: status %d", e.Event.ID, e.URL, e.Attempts, e.StatusCode)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is match ErrWebhookUndelivered.
+func (e *WebhookError) Is(target error) bool {
+	return target == ErrWebhookUndelivered
+}
+
+// Webhook configures the delivery of webhooks by a WebhookSender.
+type Webhook struct {
+	// Secret is the key of the HMAC signature of the events.
+	Secret []byte
+	// Algorithm is the hash of the signature, defaulting to SHA-256.
+	Algorithm HashAlgorithm
+	// SignatureHeader is the header holding the signature, defaulting to Webhook-Signature. Its value is
+	// "t=<unix timestamp>,v1=<hex hmac>", where the HMAC covers the timestamp, a dot and the body.
+	SignatureHeader string
+	// MaxAttempts is the number of deliveries attempted before giving up, defaulting to 5.
+	MaxAttempts int
+	// Backoff computes the delay before each redelivery, defaulting to an exponential backoff starting at
+	// 1s and capped at 1h. Retry-After headers are honored by the default backoff.
+	Backoff func(attempt int, resp *http.Response) time.Duration
+	// DeadLetter is called with the *WebhookError of the events that could not be delivered. Its panics are
+	// recovered and returned by Send as a *PanicError joined to the *WebhookError.
+	DeadLetter func(ctx context.Context, err *WebhookError)
+}
+
+// WebhookSender delivers signed JSON events with the client, redelivering them on network errors,
+// 408, 429 and 5xx responses. Other responses are not redelivered, and 2xx responses are successful.
+type WebhookSender struct {
+	client *Client
+	config Webhook
+}
+
+// NewWebhookSender returns a sender delivering webhooks with the client and configuration.
+func NewWebhookSender(c *Client, config Webhook) *WebhookSender {
+	if config.Algorithm == "" {
+		config.Algorithm = HashSHA256
+	}
+	if config.SignatureHeader == "" {
+		config.SignatureHeader = "Webhook-Signature"
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.Backoff == nil {
+		config.Backoff = ExponentialBackoff(time.Second, time.Hour)
+	}
+
+	return &WebhookSender{client: c, config: config}
+}
+
+// Send delivers the event to url, waiting between attempts. Once all attempts failed, the dead letter
+// callback is called and a *WebhookError is returned.
+func (s *WebhookSender) Send(ctx context.Context, url string, event WebhookEvent) error {
+	c := s.client
+	if event.ID == "" {
+		event.ID = c.random.nonce()
+	}
+
+	body, err := c.marshalJSON(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	var resp *http.Response
+	attempts := 0
+	for {
+		resp, err = s.deliver(ctx, url, event, body)
+		attempts++
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			discardBody(resp)
+			return nil
+		}
+
+		if attempts >= s.config.MaxAttempts || !webhookRetryable(resp, err) {
+			break
+		}
+
+		delay := s.config.Backoff(attempts-1, resp)
+		discardBody(resp)
+		if sleepErr := sleepContext(ctx, c.clock(), delay); sleepErr != nil {
+			resp, err = nil, sleepErr
+			break
+		}
+	}
+
+	undelivered := &WebhookError{Event: event, URL: url, Attempts: attempts, Err: err}
+	if resp != nil {
+		undelivered.StatusCode = resp.StatusCode
+		discardBody(resp)
+	}
+
+	if s.config.DeadLetter != nil {
+		if panicErr := s.deadLetter(ctx, undelivered); panicErr != nil {
+			return errors.Join(undelivered, panicErr)
+		}
+	}
+
+	return undelivered
+}
+
+// deadLetter calls the dead letter callback, recovering its panics.
+func (s *WebhookSender) deadLetter(ctx context.Context, undelivered *WebhookError) (panicErr error) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr = newPanicError(value)
+		}
+	}()
+
+	s.config.DeadLetter(ctx, undelivered)
+
+	return nil
+}
+
+// deliver sends one attempt of the event, signed at the time of the attempt.
+func (s *WebhookSender) deliver(ctx context.Context, url string, event WebhookEvent, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	setBody(req, body, "application/json")
+	req.Header.Set("Idempotency-Key", event.ID)
+	if event.Type != "" {
+		req.Header.Set("Webhook-Event", event.Type)
+	}
+
+	if s.config.Secret != nil {
+		timestamp := strconv.FormatInt(s.client.clock().Now().Unix(), 10)
+		mac, err := s.client.NewHMAC(s.config.Algorithm, s.config.Secret)
+		if err != nil {
+			return nil, err
+		}
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		req.Header.Set(s.config.SignatureHeader, "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return s.client.Do(req)
+}
+
+// webhookRetryable reports whether a failed delivery is worth redelivering.
+func webhookRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return ClassifyError(err) != ErrorClassPermanent
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true
+	}
+
+	return false
+}
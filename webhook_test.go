@@ -0,0 +1,125 @@
+package clink_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWebhookSender(t *testing.T) {
+	secret := []byte("whsec")
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "delivered", statuses: []int{http.StatusOK}, wantAttempts: 1},
+		{name: "redelivered after server errors", statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusNoContent}, wantAttempts: 3},
+		{name: "dead letter after max attempts", statuses: []int{http.StatusServiceUnavailable}, wantAttempts: 3, wantErr: true},
+		{name: "client error is not redelivered", statuses: []int{http.StatusBadRequest}, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var keys []string
+			attempts := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				timestamp, signature, _ := strings.Cut(strings.TrimPrefix(r.Header.Get("Webhook-Signature"), "t="), ",v1=")
+				mac := hmac.New(sha256.New, secret)
+				mac.Write([]byte(timestamp + "." + string(body)))
+				if signature != hex.EncodeToString(mac.Sum(nil)) {
+					t.Errorf("invalid signature %q", r.Header.Get("Webhook-Signature"))
+				}
+				if string(body) != `{"order":42}` || r.Header.Get("Webhook-Event") != "order.paid" {
+					t.Errorf("unexpected event %s %s", r.Header.Get("Webhook-Event"), body)
+				}
+
+				mu.Lock()
+				keys = append(keys, r.Header.Get("Idempotency-Key"))
+				status := tt.statuses[min(attempts, len(tt.statuses)-1)]
+				attempts++
+				mu.Unlock()
+
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			var deadLetter *clink.WebhookError
+			sender := clink.NewWebhookSender(clink.NewClient(clink.WithClient(server.Client())), clink.Webhook{
+				Secret:      secret,
+				MaxAttempts: 3,
+				Backoff: func(attempt int, resp *http.Response) time.Duration {
+					return time.Millisecond
+				},
+				DeadLetter: func(ctx context.Context, err *clink.WebhookError) {
+					deadLetter = err
+				},
+			})
+
+			err := sender.Send(context.Background(), server.URL, clink.WebhookEvent{Type: "order.paid", Payload: map[string]int{"order": 42}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, attempts)
+			}
+			for _, key := range keys {
+				if key == "" || key != keys[0] {
+					t.Errorf("expected the same idempotency key on every attempt, got %v", keys)
+				}
+			}
+
+			if !tt.wantErr {
+				if deadLetter != nil {
+					t.Errorf("unexpected dead letter %v", deadLetter)
+				}
+				return
+			}
+
+			if !errors.Is(err, clink.ErrWebhookUndelivered) || deadLetter == nil || deadLetter.Attempts != tt.wantAttempts {
+				t.Errorf("expected a dead letter after %d attempts, got %v", tt.wantAttempts, deadLetter)
+			}
+			if deadLetter.StatusCode != tt.statuses[len(tt.statuses)-1] || deadLetter.Event.ID != keys[0] {
+				t.Errorf("unexpected dead letter %+v", deadLetter)
+			}
+		})
+	}
+}
+
+func TestWebhookSender_DeadLetterPanic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := clink.NewWebhookSender(clink.NewClient(clink.WithClient(server.Client())), clink.Webhook{
+		DeadLetter: func(ctx context.Context, err *clink.WebhookError) {
+			panic("boom")
+		},
+	})
+
+	err := sender.Send(context.Background(), server.URL, clink.WebhookEvent{Payload: map[string]int{"order": 42}})
+
+	var webhookErr *clink.WebhookError
+	if !errors.As(err, &webhookErr) || !errors.Is(err, clink.ErrPanic) {
+		t.Errorf("expected the undelivered and panic errors, got %v", err)
+	}
+}